@@ -0,0 +1,218 @@
+// Package accesslog audits web server access logs for requests made with
+// signed URLs.
+package accesslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+// Format is the format of an access log.
+type Format int
+
+const (
+	// Common is the Common Log Format, e.g.
+	//
+	//	127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /a/b?expiry=..&signature=.. HTTP/1.0" 200 2326
+	Common Format = iota
+	// Combined is the Combined Log Format, which is the Common Log Format
+	// followed by the referer and user agent.
+	Combined
+	// JSON is one JSON object per line, with the request URL and time held
+	// in the fields named by Auditor.URLField and Auditor.TimeField.
+	JSON
+)
+
+// ParseFormat parses the name of a format: common, combined or json.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "common":
+		return Common, nil
+	case "combined":
+		return Combined, nil
+	case "json":
+		return JSON, nil
+	default:
+		return 0, fmt.Errorf("unknown log format: %s", name)
+	}
+}
+
+// clfTime is the layout of the time in the Common and Combined Log Formats.
+const clfTime = "02/Jan/2006:15:04:05 -0700"
+
+// Auditor audits access logs, verifying the signed URLs of requests as of the
+// time each request was made. Only the signature, expiry, not-before time and
+// lifetime of each URL are verified, with Signer.VerifySignatureAt: URLs
+// revoked since are not reported, and neither the override audit hook nor the
+// drift detector of the signer is invoked.
+type Auditor struct {
+	// Signer verifies signed URLs.
+	Signer *surl.Signer
+	// Format is the format of the logs.
+	Format Format
+	// Base is the scheme and host against which the request URLs in the logs
+	// are resolved, e.g. https://example.com. It is required unless the logs
+	// hold absolute URLs or the signer skips the scheme and host.
+	Base *url.URL
+	// URLField is the name of the field holding the request URL in JSON logs.
+	// Defaults to url.
+	URLField string
+	// TimeField is the name of the field holding the time of the request, in
+	// RFC 3339 format, in JSON logs. Defaults to time.
+	TimeField string
+}
+
+// Report is the outcome of an audit.
+type Report struct {
+	// Lines is the number of lines read.
+	Lines int
+	// Skipped is the number of lines that could not be parsed.
+	Skipped int
+	// Unsigned is the number of requests without a signed URL.
+	Unsigned int
+	// Valid is the number of requests with a valid, unexpired, signed URL.
+	Valid int
+	// Expired is the number of requests with a valid but expired signed URL.
+	Expired int
+	// Forged is the number of requests with a signed URL whose signature is
+	// invalid.
+	Forged int
+	// ForgedURLs are the URLs of the requests counted in Forged.
+	ForgedURLs []string
+}
+
+// Audit reads the logs line by line, verifying the URL of each request.
+func (a *Auditor) Audit(r io.Reader) (*Report, error) {
+	var report Report
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		report.Lines++
+
+		target, t, err := a.parse(line)
+		if err != nil {
+			report.Skipped++
+			continue
+		}
+		signed, err := a.resolve(target)
+		if err != nil {
+			report.Skipped++
+			continue
+		}
+
+		err = a.Signer.VerifySignatureAt(signed, t)
+		switch {
+		case err == nil:
+			report.Valid++
+		case errors.Is(err, surl.ErrExpired):
+			report.Expired++
+		case errors.Is(err, surl.ErrInvalidFormat):
+			report.Unsigned++
+		default:
+			report.Forged++
+			report.ForgedURLs = append(report.ForgedURLs, signed)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// parse parses a log line, returning the request URL and time.
+func (a *Auditor) parse(line string) (string, time.Time, error) {
+	switch a.Format {
+	case Common, Combined:
+		return parseCLF(line)
+	case JSON:
+		return a.parseJSON(line)
+	default:
+		return "", time.Time{}, fmt.Errorf("unknown log format: %d", a.Format)
+	}
+}
+
+// parseCLF parses a line in the Common or Combined Log Format. The latter only
+// appends fields to the former, so both are parsed alike.
+func parseCLF(line string) (string, time.Time, error) {
+	_, rest, found := strings.Cut(line, "[")
+	if !found {
+		return "", time.Time{}, errors.New("missing time")
+	}
+	ts, rest, found := strings.Cut(rest, "]")
+	if !found {
+		return "", time.Time{}, errors.New("missing time")
+	}
+	t, err := time.Parse(clfTime, ts)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	_, rest, found = strings.Cut(rest, `"`)
+	if !found {
+		return "", time.Time{}, errors.New("missing request")
+	}
+	request, _, found := strings.Cut(rest, `"`)
+	if !found {
+		return "", time.Time{}, errors.New("missing request")
+	}
+	// request line is <method> <target> <protocol>
+	fields := strings.Fields(request)
+	if len(fields) != 3 {
+		return "", time.Time{}, fmt.Errorf("invalid request: %s", request)
+	}
+	return fields[1], t, nil
+}
+
+// parseJSON parses a line of JSON.
+func (a *Auditor) parseJSON(line string) (string, time.Time, error) {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return "", time.Time{}, err
+	}
+	urlField, timeField := a.URLField, a.TimeField
+	if urlField == "" {
+		urlField = "url"
+	}
+	if timeField == "" {
+		timeField = "time"
+	}
+	target, ok := fields[urlField].(string)
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("missing field: %s", urlField)
+	}
+	ts, ok := fields[timeField].(string)
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("missing field: %s", timeField)
+	}
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return target, t, nil
+}
+
+// resolve resolves a request target against the base URL.
+func (a *Auditor) resolve(target string) (string, error) {
+	u, err := url.ParseRequestURI(target)
+	if err != nil {
+		return "", err
+	}
+	if a.Base != nil && !u.IsAbs() {
+		u.Scheme = a.Base.Scheme
+		u.Host = a.Base.Host
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,122 @@
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditor(t *testing.T) {
+	signer := surl.New([]byte("abc123"))
+	requested := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	sign := func(expiry time.Time) string {
+		signed, err := signer.Sign("https://example.com/a/b?foo=bar", expiry)
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		return u.RequestURI()
+	}
+	valid := sign(requested.Add(time.Hour))
+	expired := sign(requested.Add(-time.Hour))
+	forged := strings.Replace(valid, "foo=bar", "foo=baz", 1)
+	unsigned := "/a/b?foo=bar"
+
+	base, err := url.Parse("https://example.com")
+	require.NoError(t, err)
+
+	t.Run("common", func(t *testing.T) {
+		var logs strings.Builder
+		for _, target := range []string{valid, expired, forged, unsigned} {
+			fmt.Fprintf(&logs, "127.0.0.1 - - [%s] \"GET %s HTTP/1.1\" 200 2326\n", requested.Format(clfTime), target)
+		}
+		logs.WriteString("garbage\n")
+
+		auditor := Auditor{Signer: signer, Format: Common, Base: base}
+		report, err := auditor.Audit(strings.NewReader(logs.String()))
+		require.NoError(t, err)
+
+		assert.Equal(t, &Report{
+			Lines:      5,
+			Skipped:    1,
+			Unsigned:   1,
+			Valid:      1,
+			Expired:    1,
+			Forged:     1,
+			ForgedURLs: []string{"https://example.com" + forged},
+		}, report)
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		logs := fmt.Sprintf("127.0.0.1 - frank [%s] \"GET %s HTTP/1.1\" 200 2326 \"http://example.com/start.html\" \"Mozilla/4.08 [en] (Win98; I ;Nav)\"\n", requested.Format(clfTime), valid)
+
+		auditor := Auditor{Signer: signer, Format: Combined, Base: base}
+		report, err := auditor.Audit(strings.NewReader(logs))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, report.Valid)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var logs strings.Builder
+		for _, target := range []string{valid, expired} {
+			line, err := json.Marshal(map[string]string{
+				"request_uri": target,
+				"timestamp":   requested.Format(time.RFC3339),
+			})
+			require.NoError(t, err)
+			logs.Write(append(line, '\n'))
+		}
+
+		auditor := Auditor{Signer: signer, Format: JSON, Base: base, URLField: "request_uri", TimeField: "timestamp"}
+		report, err := auditor.Audit(strings.NewReader(logs.String()))
+		require.NoError(t, err)
+
+		assert.Equal(t, &Report{Lines: 2, Valid: 1, Expired: 1}, report)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		var audited int
+		signer := surl.New([]byte("abc123"),
+			surl.WithRevocationChecker(func(context.Context, string) (bool, error) { return true, nil }),
+			surl.WithOverrideKey([]byte("break-glass"), func(*surl.Result) error {
+				audited++
+				return nil
+			}),
+		)
+		revoked, err := signer.Sign("https://example.com/a/b", requested.Add(time.Hour))
+		require.NoError(t, err)
+		override, err := surl.New([]byte("break-glass")).Sign("https://example.com/a/b", requested.Add(time.Hour))
+		require.NoError(t, err)
+
+		var logs strings.Builder
+		for _, target := range []string{revoked, override} {
+			fmt.Fprintf(&logs, "127.0.0.1 - - [%s] \"GET %s HTTP/1.1\" 200 2326\n", requested.Format(clfTime), target)
+		}
+
+		auditor := Auditor{Signer: signer, Format: Common}
+		report, err := auditor.Audit(strings.NewReader(logs.String()))
+		require.NoError(t, err)
+
+		// neither revocation nor the override audit hook apply to audits
+		assert.Equal(t, &Report{Lines: 2, Valid: 2}, report)
+		assert.Zero(t, audited)
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	got, err := ParseFormat("combined")
+	require.NoError(t, err)
+	assert.Equal(t, Combined, got)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
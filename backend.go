@@ -0,0 +1,211 @@
+package surl
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"fmt"
+)
+
+// SignFunc computes the signature of data, e.g. by delegating to a hardware
+// security module or a remote signing service holding the key. It must be
+// deterministic, returning the same signature for the same data, such as an
+// HMAC.
+type SignFunc func(data []byte) ([]byte, error)
+
+// NewFromSignFunc constructs a signer that delegates the computation of
+// signatures to the function, so that it never holds the key. URLs are
+// verified by computing their signature again and comparing it, so every
+// verification also calls the function. Errors from the function are returned
+// from signing and verification alike.
+//
+// The signer cannot derive scoped signers, and its configuration has no key
+// fingerprint.
+func NewFromSignFunc(fn SignFunc, opts ...Option) *Signer {
+	return newSigner(nil, signFunc(fn), opts...)
+}
+
+// MACService computes and verifies MACs with a key held by a remote service,
+// e.g. a key management service, so that the key never enters the process.
+// See the kms package for an implementation.
+type MACService interface {
+	// GenerateMAC returns the MAC of the data.
+	GenerateMAC(ctx context.Context, data []byte) ([]byte, error)
+	// VerifyMAC returns ErrInvalidSignature if mac is not a valid MAC of the
+	// data.
+	VerifyMAC(ctx context.Context, data, mac []byte) error
+}
+
+// NewFromMACService constructs a signer that delegates the computation and
+// verification of signatures to the service, so that it never holds the key.
+// The context given to SignContext, VerifyContext, or that of a request
+// being verified, is passed to the service; other methods pass a background
+// context. Errors from the service are returned from signing and verification
+// alike.
+//
+// The signer cannot derive scoped signers, its signatures cannot be truncated
+// with WithSignatureLength, and its configuration has no key fingerprint.
+func NewFromMACService(svc MACService, opts ...Option) *Signer {
+	return newSigner(nil, macService{svc}, opts...)
+}
+
+// NewFromCryptoSigner constructs a signer that delegates the computation of
+// signatures to a crypto.Signer, e.g. a key held in a hardware security
+// module or key management service. Ed25519, ECDSA and RSA (PKCS #1 v1.5)
+// keys are supported. URLs are verified locally using the public key, and can
+// also be verified by a Verifier holding only the public key.
+//
+// The signer cannot derive scoped signers.
+func NewFromCryptoSigner(signer crypto.Signer, opts ...Option) (*Signer, error) {
+	alg, err := newPublicKeyAlgorithm(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	alg.signer = signer
+	return newSigner(nil, alg, opts...), nil
+}
+
+// NewVerifier constructs a verifier of URLs signed by a signer constructed
+// with NewEd25519 or NewFromCryptoSigner, using the public key. Ed25519,
+// ECDSA and RSA keys are supported. The options must match those of the
+// signer.
+func NewVerifier(key crypto.PublicKey, opts ...Option) (*Verifier, error) {
+	alg, err := newPublicKeyAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{signer: newSigner(nil, alg, opts...)}, nil
+}
+
+// signFunc adapts a SignFunc to an algorithm.
+type signFunc SignFunc
+
+func (f signFunc) sign(data []byte) ([]byte, error) {
+	return f(data)
+}
+
+func (f signFunc) verify(data, sig []byte) error {
+	compare, err := f(data)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(sig, compare) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// fingerprint returns nil because fingerprinting would call the function.
+func (signFunc) fingerprint() []byte {
+	return nil
+}
+
+// macService adapts a MACService to an algorithm.
+type macService struct {
+	svc MACService
+}
+
+func (m macService) sign(data []byte) ([]byte, error) {
+	return m.signContext(context.Background(), data)
+}
+
+func (m macService) verify(data, sig []byte) error {
+	return m.verifyContext(context.Background(), data, sig)
+}
+
+func (m macService) signContext(ctx context.Context, data []byte) ([]byte, error) {
+	return m.svc.GenerateMAC(ctx, data)
+}
+
+func (m macService) verifyContext(ctx context.Context, data, sig []byte) error {
+	return m.svc.VerifyMAC(ctx, data, sig)
+}
+
+// fingerprint returns nil because fingerprinting would call the service.
+func (macService) fingerprint() []byte {
+	return nil
+}
+
+// publicKeyAlgorithm signs with a private key and verifies with the public
+// key.
+type publicKeyAlgorithm struct {
+	name string
+	// signer is nil if the algorithm only verifies.
+	signer crypto.Signer
+	public crypto.PublicKey
+	// hash is the hash applied to data before signing, or zero if the data
+	// is signed directly, as with Ed25519.
+	hash crypto.Hash
+}
+
+func newPublicKeyAlgorithm(key crypto.PublicKey) (*publicKeyAlgorithm, error) {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return &publicKeyAlgorithm{name: "ed25519", public: k}, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return &publicKeyAlgorithm{name: "ecdsa-sha256", public: k, hash: crypto.SHA256}, nil
+		case elliptic.P384():
+			return &publicKeyAlgorithm{name: "ecdsa-sha384", public: k, hash: crypto.SHA384}, nil
+		case elliptic.P521():
+			return &publicKeyAlgorithm{name: "ecdsa-sha512", public: k, hash: crypto.SHA512}, nil
+		}
+		return nil, fmt.Errorf("unsupported elliptic curve: %s", k.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return &publicKeyAlgorithm{name: "rsa-sha256", public: k, hash: crypto.SHA256}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type: %T", key)
+}
+
+func (a *publicKeyAlgorithm) sign(data []byte) ([]byte, error) {
+	if a.signer == nil {
+		return nil, fmt.Errorf("%s: cannot sign without private key", a.name)
+	}
+	return a.signer.Sign(rand.Reader, a.digest(data), a.hash)
+}
+
+func (a *publicKeyAlgorithm) verify(data, sig []byte) error {
+	var valid bool
+	switch k := a.public.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, data, sig)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, a.digest(data), sig)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(k, a.hash, a.digest(data), sig) == nil
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// digest returns the data to be signed: the hash of the data, or the data
+// itself if there is no hash.
+func (a *publicKeyAlgorithm) digest(data []byte) []byte {
+	if a.hash == 0 {
+		return data
+	}
+	h := a.hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// fingerprint identifies the key pair by its public key, so that a signer and
+// verifier have the same fingerprint.
+func (a *publicKeyAlgorithm) fingerprint() []byte {
+	der, err := x509.MarshalPKIXPublicKey(a.public)
+	if err != nil {
+		return nil
+	}
+	sum := sha256.Sum256(der)
+	return sum[:]
+}
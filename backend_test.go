@@ -0,0 +1,117 @@
+package surl
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromSignFunc(t *testing.T) {
+	hsm := func(data []byte) ([]byte, error) {
+		mac := hmac.New(sha256.New, []byte("abc123"))
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	}
+	signer := NewFromSignFunc(hsm)
+
+	signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	assert.NoError(t, signer.Verify(signed))
+	// interoperable with a signer holding the key
+	assert.NoError(t, New([]byte("abc123"), WithHMACSHA256()).Verify(signed))
+
+	assert.Equal(t, "custom", signer.Config().Algorithm)
+	assert.Empty(t, signer.Config().KeyFingerprint)
+
+	t.Run("errors", func(t *testing.T) {
+		unavailable := errors.New("hsm unavailable")
+		failing := NewFromSignFunc(func([]byte) ([]byte, error) { return nil, unavailable })
+
+		_, err := failing.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, unavailable)
+
+		err = failing.Verify(signed)
+		assert.ErrorIs(t, err, unavailable)
+
+		_, err = failing.SignBytes([]byte("data"), time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, unavailable)
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		_, err := signer.Scoped("/a").Sign("https://example.com/a/b", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrNoKey)
+
+		scoped, err := New([]byte("abc123")).Scoped("/a").Sign("https://example.com/a/b", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(scoped), ErrInvalidSignature)
+	})
+}
+
+func TestNewFromCryptoSigner(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		key       crypto.Signer
+		algorithm string
+	}{
+		{"ecdsa", ecdsaKey, "ecdsa-sha256"},
+		{"rsa", rsaKey, "rsa-sha256"},
+		{"ed25519", ed25519Key, "ed25519"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewFromCryptoSigner(tt.key, WithPathFormatter())
+			require.NoError(t, err)
+			verifier, err := NewVerifier(tt.key.Public(), WithPathFormatter())
+			require.NoError(t, err)
+
+			signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			assert.NoError(t, signer.Verify(signed))
+			assert.NoError(t, verifier.Verify(signed))
+
+			tampered, err := signer.Sign("https://example.com/a/b/d", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			tampered = tampered[:len(tampered)-1] + "c"
+			assert.ErrorIs(t, verifier.Verify(tampered), ErrInvalidSignature)
+
+			assert.Equal(t, tt.algorithm, signer.Config().Algorithm)
+			assert.Len(t, signer.Config().KeyFingerprint, 16)
+			assert.Equal(t, signer.Config().KeyFingerprint, verifier.signer.Config().KeyFingerprint)
+		})
+	}
+
+	t.Run("compatible with NewEd25519", func(t *testing.T) {
+		signer, err := NewFromCryptoSigner(ed25519Key)
+		require.NoError(t, err)
+
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, NewEd25519Verifier(ed25519Key.Public().(ed25519.PublicKey)).Verify(signed))
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		_, err := NewVerifier("not a key")
+		assert.Error(t, err)
+	})
+}
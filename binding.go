@@ -0,0 +1,86 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidBinding is returned when a binding is empty or cannot be
+// retrieved.
+var ErrInvalidBinding = errors.New("invalid binding")
+
+// BindingFunc retrieves the value to which a request's signed URL must be
+// bound, e.g. the ID of the user's session from a cookie.
+type BindingFunc func(r *http.Request) (string, error)
+
+// SignWithBinding is like Sign but binds the signed URL to a value, e.g. a
+// session or user ID, so that it is only valid when verified with the same
+// value. The value is covered by the signature but it is not stored in the
+// URL, which stops a leaked URL from working in another session. Verify it with
+// VerifyWithBinding or, retrieving the value from a request,
+// VerifyRequestWithBinding.
+func (s *Signer) SignWithBinding(unsigned string, expiry time.Time, value string) (string, error) {
+	if value == "" {
+		return "", ErrInvalidBinding
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	if err := s.signURL(context.Background(), u, expiry, valueBinding(value)); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// VerifyWithBinding verifies a URL signed with SignWithBinding, rejecting it
+// unless it was bound to the value.
+func (s *Signer) VerifyWithBinding(signed, value string) error {
+	if value == "" {
+		return ErrInvalidBinding
+	}
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyURL(context.Background(), u, valueBinding(value))
+	return err
+}
+
+// VerifyRequestWithBinding is like VerifyRequest but verifies a URL signed
+// with SignWithBinding, rejecting it unless it was bound to the value
+// retrieved from the request by the function.
+func (s *Signer) VerifyRequestWithBinding(r *http.Request, fn BindingFunc) error {
+	_, err := s.verifyBoundRequest(r, fn)
+	return err
+}
+
+// verifyBoundRequest verifies the URL of a request bound to the value
+// retrieved from the request.
+func (s *Signer) verifyBoundRequest(r *http.Request, fn BindingFunc) (*Result, error) {
+	value, err := fn(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBinding, err)
+	}
+	if value == "" {
+		return nil, ErrInvalidBinding
+	}
+	return s.verifyRequestURL(r, requestURL(r), valueBinding(value))
+}
+
+// tagBinding binds a value, identified by the tag, along with any other
+// binding, to a signature. The value is length-prefixed so that no part of the
+// other binding can be moved into it, or vice versa, to yield the same data.
+func tagBinding(tag, value, binding string) string {
+	return tag + ":" + strconv.Itoa(len(value)) + ":" + value + binding
+}
+
+// valueBinding binds a value to a signature.
+func valueBinding(value string) string {
+	return "value:" + value
+}
@@ -0,0 +1,123 @@
+package surl
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithBinding(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignWithBinding("https://example.com/a/b/c", time.Now().Add(time.Minute), "session-1")
+	require.NoError(t, err)
+	assert.NotContains(t, signed, "session-1")
+
+	assert.NoError(t, signer.VerifyWithBinding(signed, "session-1"))
+	assert.ErrorIs(t, signer.VerifyWithBinding(signed, "session-2"), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.VerifyWithBinding(signed, ""), ErrInvalidBinding)
+	assert.ErrorIs(t, signer.Verify(signed), ErrInvalidSignature)
+
+	t.Run("empty value", func(t *testing.T) {
+		_, err := signer.SignWithBinding("https://example.com/a/b/c", time.Now().Add(time.Minute), "")
+		assert.ErrorIs(t, err, ErrInvalidBinding)
+	})
+
+	t.Run("not a client binding", func(t *testing.T) {
+		// a URL bound to a value is not valid for a client with the same value
+		signer := New([]byte("abc123"), WithClientFunc(func(*http.Request) (string, error) {
+			return "session-1", nil
+		}))
+		assert.ErrorIs(t, signer.VerifyForRequest(httptest.NewRequest("GET", signed, nil)), ErrInvalidSignature)
+	})
+}
+
+func TestVerifyRequestWithBinding(t *testing.T) {
+	signer := New([]byte("abc123"))
+	session := func(r *http.Request) (string, error) {
+		c, err := r.Cookie("session")
+		if err != nil {
+			return "", err
+		}
+		return c.Value, nil
+	}
+
+	signed, err := signer.SignWithBinding("https://example.com/a/b/c", time.Now().Add(time.Minute), "session-1")
+	require.NoError(t, err)
+
+	request := func(session string) *http.Request {
+		r := httptest.NewRequest("GET", signed, nil)
+		if session != "" {
+			r.AddCookie(&http.Cookie{Name: "session", Value: session})
+		}
+		return r
+	}
+
+	assert.NoError(t, signer.VerifyRequestWithBinding(request("session-1"), session))
+	assert.ErrorIs(t, signer.VerifyRequestWithBinding(request("session-2"), session), ErrInvalidSignature)
+
+	err = signer.VerifyRequestWithBinding(request(""), session)
+	assert.ErrorIs(t, err, ErrInvalidBinding)
+	assert.True(t, errors.Is(err, http.ErrNoCookie))
+
+	t.Run("middleware", func(t *testing.T) {
+		handler := signer.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), RequireBinding(session))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, request("session-1"))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, request("session-2"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestTagBinding(t *testing.T) {
+	// Moving a binding into the value of the binding preceding it, as can be
+	// done by rewriting one query parameter and removing another, must not
+	// yield the same binding, whichever way it is serialized.
+	id := "G7wEBtFOT7KhJfYcU6fD8g"
+	tests := []struct {
+		name   string
+		signed string
+		forged []string
+	}{
+		{
+			name:   "uses into ID",
+			signed: idBinding(id, usesBinding(1, "")),
+			forged: []string{
+				idBinding(id+":uses:1", ""),
+				idBinding(id+usesBinding(1, ""), ""),
+			},
+		},
+		{
+			name:   "not before into ID",
+			signed: idBinding(id, notBeforeBinding("1700000000", "")),
+			forged: []string{
+				idBinding(id+":nbf:1700000000", ""),
+				idBinding(id+notBeforeBinding("1700000000", ""), ""),
+			},
+		},
+		{
+			name:   "methods into content types",
+			signed: uploadBinding(&UploadConstraints{ContentTypes: []string{"image/png"}}, methodsBinding([]string{"PUT"}, "")),
+			forged: []string{
+				uploadBinding(&UploadConstraints{ContentTypes: []string{"image/png:methods:PUT"}}, ""),
+				uploadBinding(&UploadConstraints{ContentTypes: []string{"image/png" + methodsBinding([]string{"PUT"}, "")}}, ""),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, forged := range tt.forged {
+				assert.NotEqual(t, tt.signed, forged)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package surl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Builder builds a signed URL piece by piece, validating each piece as it is
+// added. The first error encountered is returned by Build, and any further
+// calls are ignored.
+type Builder struct {
+	signer *Signer
+	u      *url.URL
+	query  url.Values
+	expiry time.Time
+	err    error
+}
+
+// URL starts building a signed URL from a base URL, which must either be
+// absolute or an absolute path.
+func (s *Signer) URL(base string) *Builder {
+	b := &Builder{signer: s, query: url.Values{}}
+	b.u, b.err = url.ParseRequestURI(base)
+	if b.err == nil {
+		b.query = b.u.Query()
+	}
+	return b
+}
+
+// Path sets the path of the URL. It must begin with a slash.
+func (b *Builder) Path(p string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if !strings.HasPrefix(p, "/") {
+		b.err = fmt.Errorf("path must begin with a slash: %s", p)
+		return b
+	}
+	b.u.Path = p
+	return b
+}
+
+// Query adds a query parameter to the URL.
+func (b *Builder) Query(key, value string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if key == "" {
+		b.err = errors.New("query parameter key cannot be empty")
+		return b
+	}
+	b.query.Add(key, value)
+	return b
+}
+
+// TTL sets the expiry of the signed URL to the given duration from now.
+func (b *Builder) TTL(ttl time.Duration) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if ttl <= 0 {
+		b.err = fmt.Errorf("ttl must be positive: %s", ttl)
+		return b
+	}
+	b.expiry = time.Now().Add(ttl)
+	return b
+}
+
+// Expiry sets the expiry of the signed URL.
+func (b *Builder) Expiry(expiry time.Time) *Builder {
+	if b.err != nil {
+		return b
+	}
+	b.expiry = expiry
+	return b
+}
+
+// Build signs and returns the URL.
+func (b *Builder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.expiry.IsZero() {
+		return "", errors.New("expiry must be set")
+	}
+	u := *b.u
+	u.RawQuery = b.query.Encode()
+	return b.signer.Sign(u.String(), b.expiry)
+}
@@ -0,0 +1,58 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.URL("https://example.com?foo=bar").
+		Path("/a/b").
+		Query("baz", "qux").
+		TTL(time.Hour).
+		Build()
+	require.NoError(t, err)
+	assert.Regexp(t, `^https://example.com/a/b\?baz=qux&foo=bar&expiry=\d+&signature=.+$`, signed)
+
+	err = signer.Verify(signed)
+	require.NoError(t, err)
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			builder *Builder
+		}{
+			{
+				name:    "relative url",
+				builder: signer.URL("example.com").TTL(time.Hour),
+			},
+			{
+				name:    "relative path",
+				builder: signer.URL("https://example.com").Path("a/b").TTL(time.Hour),
+			},
+			{
+				name:    "empty query key",
+				builder: signer.URL("https://example.com").Query("", "bar").TTL(time.Hour),
+			},
+			{
+				name:    "negative ttl",
+				builder: signer.URL("https://example.com").TTL(-time.Hour),
+			},
+			{
+				name:    "missing expiry",
+				builder: signer.URL("https://example.com"),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.builder.Build()
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Callback(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignCallback("https://example.com/callbacks/jobs/123", "partner-a", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	t.Run("same audience", func(t *testing.T) {
+		err := signer.VerifyCallback(signed, "partner-a")
+		require.NoError(t, err)
+	})
+
+	t.Run("different audience", func(t *testing.T) {
+		err := signer.VerifyCallback(signed, "partner-b")
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("verify without audience", func(t *testing.T) {
+		err := signer.Verify(signed)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("bound value", func(t *testing.T) {
+		bound, err := signer.SignWithBinding("https://example.com/callbacks/jobs/123", time.Now().Add(time.Minute), "x")
+		require.NoError(t, err)
+
+		err = signer.VerifyCallback(bound, "value:x")
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("empty audience", func(t *testing.T) {
+		_, err := signer.SignCallback("https://example.com/callbacks/jobs/123", "", time.Now().Add(time.Minute))
+		assert.Equal(t, ErrInvalidAudience, err)
+	})
+}
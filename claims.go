@@ -0,0 +1,46 @@
+package surl
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SignClaims is like Signer.SignWithData but embeds claims of any type that
+// can be encoded as JSON, typically a struct, which are retrieved with
+// VerifyClaims using the same type:
+//
+//	type Download struct {
+//		UserID string `json:"uid"`
+//		FileID int    `json:"fid"`
+//	}
+//
+//	signed, err := surl.SignClaims(signer, unsigned, expiry, Download{"bob", 42})
+//	claims, err := surl.VerifyClaims[Download](signer, signed)
+//
+// Short JSON field names keep the signed URL compact.
+func SignClaims[T any](s *Signer, unsigned string, expiry time.Time, claims T) (string, error) {
+	encoded, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return s.signData(unsigned, expiry, encoded)
+}
+
+// VerifyClaims verifies a signed URL, as Signer.Verify does, returning the
+// claims embedded with SignClaims. An error wrapping ErrInvalidFormat is
+// returned if the URL has no claims or they cannot be decoded into T.
+func VerifyClaims[T any](s *Signer, signed string) (T, error) {
+	var claims T
+	encoded, err := s.verifyData(signed)
+	if err != nil {
+		return claims, err
+	}
+	if encoded == nil {
+		return claims, fmt.Errorf("%w: no claims", ErrInvalidFormat)
+	}
+	if err := json.Unmarshal(encoded, &claims); err != nil {
+		return claims, fmt.Errorf("%w: invalid claims: %s", ErrInvalidFormat, err.Error())
+	}
+	return claims, nil
+}
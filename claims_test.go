@@ -0,0 +1,50 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	UserID string `json:"uid"`
+	FileID int    `json:"fid"`
+}
+
+func TestSignClaims(t *testing.T) {
+	signer := New([]byte("abc123"))
+	want := testClaims{UserID: "bob", FileID: 42}
+
+	signed, err := SignClaims(signer, "https://example.com/a/b/c", time.Now().Add(time.Minute), want)
+	require.NoError(t, err)
+
+	got, err := VerifyClaims[testClaims](signer, signed)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	t.Run("as data", func(t *testing.T) {
+		// numeric field cannot be decoded into a string map
+		_, err := signer.VerifyData(signed)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		_, err := VerifyClaims[[]string](signer, signed)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("no claims", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = VerifyClaims[testClaims](signer, signed)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		_, err := VerifyClaims[testClaims](New([]byte("xyz789")), signed)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,101 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// ErrInvalidClient is returned when a client is empty or cannot be identified.
+var ErrInvalidClient = errors.New("invalid client")
+
+// ClientFunc identifies the client making a request, e.g. by its IP address,
+// for verifying URLs signed with SignForClient.
+type ClientFunc func(r *http.Request) (string, error)
+
+// RemoteIP is the default ClientFunc, identifying the client by the IP address
+// of the remote end of the connection. Behind a reverse proxy, configure a
+// ClientFunc that identifies the client from a header set by the proxy, e.g.
+// X-Forwarded-For, instead.
+func RemoteIP(r *http.Request) (string, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidClient, r.RemoteAddr)
+	}
+	return host, nil
+}
+
+// WithClientFunc sets the function that identifies the client making a
+// request, in place of RemoteIP. It need not return an IP address: any string
+// identifying the client, e.g. a device ID, can be bound to a URL with
+// SignForClient.
+func WithClientFunc(fn ClientFunc) Option {
+	return func(s *Signer) {
+		s.clientFunc = fn
+	}
+}
+
+// SignForClient is like Sign but binds the signed URL to a client, usually
+// its IP address, so that it is only valid for requests from that client,
+// stopping links from being shared outside the original network. The client is
+// covered by the signature but it is not stored in the URL. Verify requests
+// with VerifyForRequest.
+func (s *Signer) SignForClient(unsigned string, expiry time.Time, client string) (string, error) {
+	if client == "" {
+		return "", ErrInvalidClient
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	if err := s.signURL(context.Background(), u, expiry, clientBinding(client)); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// VerifyForRequest is like VerifyRequest but verifies a URL signed with
+// SignForClient or SignForNetwork, rejecting it unless the request is from the
+// client to which it was bound, or from a client in the network to which it was
+// bound, as identified by RemoteIP or the function configured with
+// WithClientFunc.
+func (s *Signer) VerifyForRequest(r *http.Request) error {
+	_, err := s.verifyClientRequest(r)
+	return err
+}
+
+// verifyClientRequest verifies the URL of a request from the client to which
+// it was bound.
+func (s *Signer) verifyClientRequest(r *http.Request) (*Result, error) {
+	fn := s.clientFunc
+	if fn == nil {
+		fn = RemoteIP
+	}
+	client, err := fn(r)
+	if err != nil {
+		return nil, err
+	}
+	if client == "" {
+		return nil, ErrInvalidClient
+	}
+	u := requestURL(r)
+	binding, err := clientNetworkBinding(u, client)
+	if err != nil {
+		return nil, err
+	}
+	return s.verifyRequestURL(r, u, binding)
+}
+
+// clientBinding binds a client to a signature. IP addresses are normalized so
+// that, e.g., an IPv4-mapped IPv6 address matches its IPv4 address.
+func clientBinding(client string) string {
+	if addr, err := netip.ParseAddr(client); err == nil {
+		client = addr.Unmap().WithZone("").String()
+	}
+	return "client:" + client
+}
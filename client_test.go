@@ -0,0 +1,76 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignForClient(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignForClient("https://example.com/a/b/c", time.Now().Add(time.Minute), "192.0.2.1")
+	require.NoError(t, err)
+	assert.NotContains(t, signed, "192.0.2.1")
+
+	request := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest("GET", signed, nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	assert.NoError(t, signer.VerifyForRequest(request("192.0.2.1:1234")))
+	assert.NoError(t, signer.VerifyForRequest(request("[::ffff:192.0.2.1]:1234")))
+	assert.ErrorIs(t, signer.VerifyForRequest(request("192.0.2.2:1234")), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.VerifyForRequest(request("bogus")), ErrInvalidClient)
+
+	// not valid without the client
+	assert.ErrorIs(t, signer.Verify(signed), ErrInvalidSignature)
+
+	t.Run("unbound", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		r := httptest.NewRequest("GET", signed, nil)
+		assert.ErrorIs(t, signer.VerifyForRequest(r), ErrInvalidSignature)
+	})
+
+	t.Run("empty client", func(t *testing.T) {
+		_, err := signer.SignForClient("https://example.com/a/b/c", time.Now().Add(time.Minute), "")
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("client func", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithClientFunc(func(r *http.Request) (string, error) {
+			return r.Header.Get("X-Device-ID"), nil
+		}))
+		signed, err := signer.SignForClient("https://example.com/a/b/c", time.Now().Add(time.Minute), "device-1")
+		require.NoError(t, err)
+
+		r := httptest.NewRequest("GET", signed, nil)
+		r.Header.Set("X-Device-ID", "device-1")
+		assert.NoError(t, signer.VerifyForRequest(r))
+
+		r.Header.Set("X-Device-ID", "device-2")
+		assert.ErrorIs(t, signer.VerifyForRequest(r), ErrInvalidSignature)
+
+		r.Header.Del("X-Device-ID")
+		assert.ErrorIs(t, signer.VerifyForRequest(r), ErrInvalidClient)
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		handler := signer.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), BindClient())
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, request("192.0.2.1:1234"))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, request("192.0.2.2:1234"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,74 @@
+package surl
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudFront(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer := NewCloudFront("K2JCJMDEHXQW5F", key)
+
+	signed, err := signer.Sign("https://d111111abcdef8.cloudfront.net/a/b/c?foo=bar", time.Unix(3507595200, 0))
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u.RawQuery, "foo=bar&Expires=3507595200&Signature="))
+	assert.True(t, strings.HasSuffix(u.RawQuery, "&Key-Pair-Id=K2JCJMDEHXQW5F"))
+
+	t.Run("canned policy", func(t *testing.T) {
+		policy := `{"Statement":[{"Resource":"https://d111111abcdef8.cloudfront.net/a/b/c?foo=bar","Condition":{"DateLessThan":{"AWS:EpochTime":3507595200}}}]}`
+		encodedSig := u.Query().Get("Signature")
+		assert.NotContains(t, encodedSig, "+")
+		assert.NotContains(t, encodedSig, "/")
+		assert.NotContains(t, encodedSig, "=")
+
+		sig, err := base64.StdEncoding.DecodeString(cloudFrontSignatureDecoding.Replace(encodedSig))
+		require.NoError(t, err)
+		digest := sha1.Sum([]byte(policy))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, digest[:], sig))
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(signed))
+		assert.NoError(t, NewCloudFrontVerifier("K2JCJMDEHXQW5F", &key.PublicKey).Verify(signed))
+
+		unsigned, err := signer.Unsign(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "https://d111111abcdef8.cloudfront.net/a/b/c?foo=bar", unsigned)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := strings.Replace(signed, "foo=bar", "foo=baz", 1)
+		assert.ErrorIs(t, signer.Verify(tampered), ErrInvalidSignature)
+	})
+
+	t.Run("different key pair", func(t *testing.T) {
+		verifier := NewCloudFrontVerifier("APKAEIBAERJR2EXAMPLE", &key.PublicKey)
+		assert.ErrorIs(t, verifier.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignFor("https://d111111abcdef8.cloudfront.net/a/b/c", -time.Minute)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		got := signer.Config()
+		assert.Equal(t, "rsa-sha1", got.Algorithm)
+		assert.Equal(t, "cloudfront", got.Formatter)
+		assert.Equal(t, "decimal", got.ExpiryEncoding)
+	})
+}
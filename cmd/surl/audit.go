@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/leg100/surl/v2/accesslog"
+)
+
+func runAudit(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		sf        signerFlags
+		format    string
+		base      string
+		urlField  string
+		timeField string
+	)
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: surl audit [flags] [file...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Audits access logs, read from the files or else stdin, reporting counts of requests made with valid, expired and forged signed URLs.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	sf.register(fs)
+	fs.StringVar(&format, "format", "combined", "log format: common, combined or json")
+	fs.StringVar(&base, "base", "", "scheme and host against which request URLs are resolved, e.g. https://example.com")
+	fs.StringVar(&urlField, "url-field", "url", "name of the field holding the request URL in json logs")
+	fs.StringVar(&timeField, "time-field", "time", "name of the field holding the request time in json logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	signer, err := sf.signer()
+	if err != nil {
+		return err
+	}
+	auditor := &accesslog.Auditor{
+		Signer:    signer,
+		URLField:  urlField,
+		TimeField: timeField,
+	}
+	if auditor.Format, err = accesslog.ParseFormat(format); err != nil {
+		return err
+	}
+	if base != "" {
+		if auditor.Base, err = url.Parse(base); err != nil {
+			return err
+		}
+	}
+
+	var logs io.Reader = stdin
+	if fs.NArg() > 0 {
+		readers := make([]io.Reader, fs.NArg())
+		for i, path := range fs.Args() {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			readers[i] = f
+		}
+		logs = io.MultiReader(readers...)
+	}
+
+	report, err := auditor.Audit(logs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "lines:    %d\n", report.Lines)
+	fmt.Fprintf(stdout, "skipped:  %d\n", report.Skipped)
+	fmt.Fprintf(stdout, "unsigned: %d\n", report.Unsigned)
+	fmt.Fprintf(stdout, "valid:    %d\n", report.Valid)
+	fmt.Fprintf(stdout, "expired:  %d\n", report.Expired)
+	fmt.Fprintf(stdout, "forged:   %d\n", report.Forged)
+	for _, u := range report.ForgedURLs {
+		fmt.Fprintf(stdout, "  %s\n", u)
+	}
+	return nil
+}
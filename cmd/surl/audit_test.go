@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	t.Setenv("SURL_KEY", "abc123")
+
+	signed, err := surl.New([]byte("abc123")).Sign("https://example.com/a/b", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+
+	logs := fmt.Sprintf("127.0.0.1 - - [%s] \"GET %s HTTP/1.1\" 200 2326\n", time.Now().Format("02/Jan/2006:15:04:05 -0700"), u.RequestURI())
+
+	var out strings.Builder
+	err = run([]string{"audit", "-format", "common", "-base", "https://example.com"}, strings.NewReader(logs), &out)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "valid:    1\n")
+	assert.Contains(t, out.String(), "forged:   0\n")
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	err := run([]string{"foo"}, nil, &strings.Builder{})
+	assert.Error(t, err)
+}
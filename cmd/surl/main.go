@@ -0,0 +1,86 @@
+// Command surl works with signed URLs from the command line.
+//
+// Usage:
+//
+//	surl <command> [flags]
+//
+// The commands are:
+//
+//	sign	sign URLs
+//	verify	verify signed URLs
+//	audit	audit access logs for requests made with signed URLs
+//
+// The signing key is given by the -key flag, read from the file given by the
+// -key-file flag or, if neither is given, read from the SURL_KEY environment
+// variable.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a subcommand of surl.
+type command struct {
+	name  string
+	short string
+	run   func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []command{
+	{name: "sign", short: "sign URLs", run: runSign},
+	{name: "verify", short: "verify signed URLs", run: runVerify},
+	{name: "audit", short: "audit access logs for requests made with signed URLs", run: runAudit},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "surl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usage(stdout)
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout)
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		return usage(stdout)
+	}
+	return fmt.Errorf("unknown command: %s", args[0])
+}
+
+func usage(w io.Writer) error {
+	fmt.Fprintln(w, "Usage: surl <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.short)
+	}
+	return nil
+}
+
+// urls returns the URLs given as arguments or, if there are none, read from
+// stdin, one per line.
+func urls(fs *flag.FlagSet, stdin io.Reader) ([]string, error) {
+	if fs.NArg() > 0 {
+		return fs.Args(), nil
+	}
+	var urls []string
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			urls = append(urls, line)
+		}
+	}
+	return urls, scanner.Err()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+)
+
+func runSign(args []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		sf     signerFlags
+		ttl    time.Duration
+		expiry string
+	)
+	fs := flag.NewFlagSet("sign", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: surl sign [flags] [url...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Signs the URLs, or else URLs read from stdin one per line, printing the signed URLs one per line.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	sf.register(fs)
+	fs.DurationVar(&ttl, "ttl", time.Hour, "lifespan of the signed URLs")
+	fs.StringVar(&expiry, "expiry", "", "expiry of the signed URLs in RFC 3339 format, overriding -ttl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	signer, err := sf.signer()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(ttl)
+	if expiry != "" {
+		if expiresAt, err = time.Parse(time.RFC3339, expiry); err != nil {
+			return fmt.Errorf("invalid expiry: %w", err)
+		}
+	}
+	unsigned, err := urls(fs, stdin)
+	if err != nil {
+		return err
+	}
+	for _, u := range unsigned {
+		signed, err := signer.Sign(u, expiresAt)
+		if err != nil {
+			return fmt.Errorf("signing %s: %w", u, err)
+		}
+		fmt.Fprintln(stdout, signed)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	t.Run("arguments", func(t *testing.T) {
+		var out strings.Builder
+		err := run([]string{"sign", "-key", "abc123", "-formatter", "path", "https://example.com/a", "https://example.com/b"}, nil, &out)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+		for _, signed := range lines {
+			assert.NoError(t, surl.New([]byte("abc123"), surl.WithPathFormatter()).Verify(signed))
+		}
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		t.Setenv("SURL_KEY", "abc123")
+
+		var out strings.Builder
+		err := run([]string{"sign", "-algorithm", "hmac-sha256", "-ttl", "1m"}, strings.NewReader("https://example.com/a\n\n"), &out)
+		require.NoError(t, err)
+
+		assert.NoError(t, surl.New([]byte("abc123"), surl.WithHMACSHA256()).Verify(strings.TrimSpace(out.String())))
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		expiry := time.Now().Add(-time.Minute).Format(time.RFC3339)
+
+		var out strings.Builder
+		err := run([]string{"sign", "-key", "abc123", "-expiry", expiry, "https://example.com/a"}, nil, &out)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, surl.New([]byte("abc123")).Verify(strings.TrimSpace(out.String())), surl.ErrExpired)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		t.Setenv("SURL_KEY", "")
+
+		err := run([]string{"sign", "https://example.com/a"}, nil, &strings.Builder{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/leg100/surl/v2"
+)
+
+// signerFlags are the flags that configure a signer, shared by all commands.
+type signerFlags struct {
+	key            string
+	keyFile        string
+	algorithm      string
+	formatter      string
+	encoding       string
+	prefix         string
+	purpose        string
+	skipQuery      bool
+	skipScheme     bool
+	skipHost       bool
+	selfDescribing bool
+}
+
+func (f *signerFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.key, "key", "", "signing key (default $SURL_KEY)")
+	fs.StringVar(&f.keyFile, "key-file", "", "path to file containing the signing key")
+	fs.StringVar(&f.algorithm, "algorithm", "blake2b-256", "signature algorithm: blake2b-256, hmac-sha256 or hmac-sha512")
+	fs.StringVar(&f.formatter, "formatter", "query", "format of signed URLs: query, short-query, path, compact, suffix or host")
+	fs.StringVar(&f.encoding, "expiry-encoding", "decimal", "encoding of expiries: decimal, base58, base64 or timestamp")
+	fs.StringVar(&f.prefix, "prefix", "", "path prefix of signed URLs")
+	fs.StringVar(&f.purpose, "purpose", "", "purpose from which the key is derived")
+	fs.BoolVar(&f.skipQuery, "skip-query", false, "skip the query when computing signatures")
+	fs.BoolVar(&f.skipScheme, "skip-scheme", false, "skip the scheme when computing signatures")
+	fs.BoolVar(&f.skipHost, "skip-host", false, "skip the host when computing signatures")
+	fs.BoolVar(&f.selfDescribing, "self-describing", false, "embed a descriptor of the configuration in signatures")
+}
+
+// signer constructs a signer according to the flags.
+func (f *signerFlags) signer() (*surl.Signer, error) {
+	key := []byte(os.Getenv("SURL_KEY"))
+	if f.key != "" {
+		key = []byte(f.key)
+	} else if f.keyFile != "" {
+		var err error
+		if key, err = os.ReadFile(f.keyFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(key) == 0 {
+		return nil, errors.New("no key: set -key, -key-file or $SURL_KEY")
+	}
+
+	var opts []surl.Option
+	switch f.algorithm {
+	case "blake2b-256":
+	case "hmac-sha256":
+		opts = append(opts, surl.WithHMACSHA256())
+	case "hmac-sha512":
+		opts = append(opts, surl.WithHMACSHA512())
+	default:
+		return nil, fmt.Errorf("unknown algorithm: %s", f.algorithm)
+	}
+	if f.purpose != "" {
+		opts = append(opts, surl.WithPurpose(f.purpose))
+	}
+	switch f.formatter {
+	case "query":
+		opts = append(opts, surl.WithQueryFormatter())
+	case "short-query":
+		opts = append(opts, surl.WithShortQueryFormatter())
+	case "path":
+		opts = append(opts, surl.WithPathFormatter())
+	case "compact":
+		opts = append(opts, surl.WithCompactFormatter())
+	case "suffix":
+		opts = append(opts, surl.WithSuffixFormatter())
+	case "host":
+		opts = append(opts, surl.WithHostFormatter())
+	default:
+		return nil, fmt.Errorf("unknown formatter: %s", f.formatter)
+	}
+	switch f.encoding {
+	case "decimal":
+		opts = append(opts, surl.WithDecimalExpiry())
+	case "base58":
+		opts = append(opts, surl.WithBase58Expiry())
+	case "base64":
+		opts = append(opts, surl.WithBase64Expiry())
+	case "timestamp":
+		opts = append(opts, surl.WithTimestampExpiry())
+	default:
+		return nil, fmt.Errorf("unknown expiry encoding: %s", f.encoding)
+	}
+	if f.prefix != "" {
+		opts = append(opts, surl.PrefixPath(f.prefix))
+	}
+	if f.skipQuery {
+		opts = append(opts, surl.SkipQuery())
+	}
+	if f.skipScheme {
+		opts = append(opts, surl.SkipScheme())
+	}
+	if f.skipHost {
+		opts = append(opts, surl.SkipHost())
+	}
+	if f.selfDescribing {
+		opts = append(opts, surl.SelfDescribing())
+	}
+	return surl.New(key, opts...), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runVerify(args []string, stdin io.Reader, stdout io.Writer) error {
+	var sf signerFlags
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: surl verify [flags] [url...]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Verifies the signed URLs, or else signed URLs read from stdin one per line, printing the outcome for each URL. Fails if any URL fails verification.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	sf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	signer, err := sf.signer()
+	if err != nil {
+		return err
+	}
+	signed, err := urls(fs, stdin)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for _, u := range signed {
+		if err := signer.Verify(u); err != nil {
+			fmt.Fprintf(stdout, "%s: %s\n", u, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: valid\n", u)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d URLs failed verification", failed, len(signed))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	signer := surl.New([]byte("abc123"), surl.WithPurpose("invite"))
+	valid, err := signer.Sign("https://example.com/a", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	expired, err := signer.Sign("https://example.com/a", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		var out strings.Builder
+		err := run([]string{"verify", "-key", "abc123", "-purpose", "invite", valid}, nil, &out)
+		require.NoError(t, err)
+
+		assert.Equal(t, valid+": valid\n", out.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var out strings.Builder
+		err := run([]string{"verify", "-key", "abc123", "-purpose", "invite"}, strings.NewReader(valid+"\n"+expired+"\n"), &out)
+		assert.EqualError(t, err, "1 of 2 URLs failed verification")
+
+		assert.Equal(t, valid+": valid\n"+expired+": "+surl.ErrExpired.Error()+"\n", out.String())
+	})
+}
@@ -0,0 +1,66 @@
+package surl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// compactParam is the query parameter in which the compact formatter stores
+// the expiry and signature.
+const compactParam = "token"
+
+// compactFormatter stores the expiry and signature in a single query
+// parameter, separated by a dot. The expiry is omitted from URLs that never
+// expire.
+type compactFormatter struct{}
+
+func (f *compactFormatter) addExpiry(unsigned *url.URL, expiry string) {
+	appendQueryParam(unsigned, compactParam, expiry)
+}
+
+func (f *compactFormatter) buildPayload(u url.URL, opts payloadOptions) string {
+	if opts.skipQuery {
+		// Remove all query params other than expiry
+		expiry := u.Query().Get(compactParam)
+		u.RawQuery = url.Values{compactParam: []string{expiry}}.Encode()
+	}
+	if opts.skipScheme {
+		u.Scheme = ""
+	}
+	if opts.skipHost {
+		u.Host = ""
+	}
+	return u.String()
+}
+
+func (f *compactFormatter) addSignature(payload *url.URL, sig string) {
+	// removing the expiry cannot fail because it was added unescaped
+	expiry, _ := removeQueryParam(payload, compactParam)
+	appendQueryParam(payload, compactParam, expiry+"."+sig)
+}
+
+func (f *compactFormatter) extractSignature(u *url.URL) (string, error) {
+	token, err := removeQueryParam(u, compactParam)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFormat, u.String())
+	}
+	// the signature never contains a dot, unlike the expiry
+	i := strings.LastIndex(token, ".")
+	if i < 0 || i == len(token)-1 {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFormat, u.String())
+	}
+	if expiry := token[:i]; expiry != "" {
+		// leave the expiry in place for the payload
+		appendQueryParam(u, compactParam, expiry)
+	}
+	return token[i+1:], nil
+}
+
+func (f *compactFormatter) extractExpiry(u *url.URL) (string, error) {
+	expiry, err := removeQueryParam(u, compactParam)
+	if err != nil || expiry == "" {
+		return "", ErrInvalidFormat
+	}
+	return expiry, nil
+}
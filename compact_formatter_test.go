@@ -0,0 +1,84 @@
+package surl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactFormatter(t *testing.T) {
+	f := &compactFormatter{}
+	u := &url.URL{RawQuery: "foo=bar"}
+
+	f.addExpiry(u, "3507595200")
+	assert.Equal(t, "foo=bar&token=3507595200", u.RawQuery)
+
+	f.addSignature(u, "abcdef")
+	assert.Equal(t, "foo=bar&token=3507595200.abcdef", u.RawQuery)
+
+	sig, err := f.extractSignature(u)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", sig)
+	assert.Equal(t, "foo=bar&token=3507595200", u.RawQuery)
+
+	got, err := f.extractExpiry(u)
+	require.NoError(t, err)
+	assert.Equal(t, "3507595200", got)
+	assert.Equal(t, "foo=bar", u.RawQuery)
+}
+
+func TestCompactFormatter_Errors(t *testing.T) {
+	f := &compactFormatter{}
+	for _, query := range []string{"", "foo=bar", "token=3507595200", "token=3507595200."} {
+		_, err := f.extractSignature(&url.URL{RawQuery: query})
+		assert.ErrorIs(t, err, ErrInvalidFormat, query)
+	}
+}
+
+func TestWithCompactFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"millisecond expiry", []Option{WithMillisecondExpiry()}},
+		{"skip query", []Option{SkipQuery()}},
+		{"self-describing", []Option{SelfDescribing()}},
+		{"no expiry", []Option{WithNoExpiry()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), append([]Option{WithCompactFormatter()}, tt.opts...)...)
+			expiry := time.Now().Add(time.Minute)
+			if signer.noExpiry {
+				expiry = time.Time{}
+			}
+
+			signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", expiry)
+			require.NoError(t, err)
+			assert.Contains(t, signed, "?foo=bar&token=")
+			assert.NoError(t, signer.Verify(signed))
+
+			unsigned, err := signer.Unsign(signed)
+			require.NoError(t, err)
+			assert.Equal(t, "https://example.com/a/b/c?foo=bar", unsigned)
+		})
+	}
+
+	t.Run("parameters after token", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithCompactFormatter())
+		signed, err := signer.SignForMethods("https://example.com/a/b/c", time.Now().Add(time.Minute), "GET")
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithCompactFormatter())
+		signed, err := signer.SignFor("https://example.com/a/b/c", -time.Minute)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+}
@@ -0,0 +1,61 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// Components are the encoded signature and expiry of a signed URL.
+type Components struct {
+	// Signature is the encoded signature.
+	Signature string
+	// Expiry is the expiry, encoded using the signer's expiry encoding.
+	Expiry string
+}
+
+// SignComponents computes the signature and encoded expiry for an unsigned
+// URL, without adding them to the URL. This permits callers to transport the
+// components by other means, e.g. in headers or a JSON payload. The components
+// are identical to those Sign adds to the URL, and they can be verified with
+// VerifyComponents.
+func (s *Signer) SignComponents(unsigned string, expiry time.Time) (Components, error) {
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return Components{}, err
+	}
+	encodedExpiry := s.encodeExpiry(s.roundExpiry(expiry))
+	s.addExpiry(u, encodedExpiry)
+
+	sig, err := s.signURLPayload(context.Background(), *u, "")
+	if err != nil {
+		return Components{}, err
+	}
+	return Components{
+		Signature: sig,
+		Expiry:    encodedExpiry,
+	}, nil
+}
+
+// VerifyComponents verifies the components produced by SignComponents for
+// the unsigned URL, validating the signature and ensuring it is unexpired.
+func (s *Signer) VerifyComponents(unsigned string, c Components) error {
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return err
+	}
+	s.addExpiry(u, c.Expiry)
+
+	if err := s.compareURLSignature(context.Background(), *u, "", c.Signature); err != nil {
+		return err
+	}
+
+	expiry, err := s.decodeExpiry(c.Expiry)
+	if err != nil {
+		return err
+	}
+	if time.Now().After(expiry) {
+		return &ExpiredError{ExpiredAt: expiry}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package surl
+
+import (
+	"net/url"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Components(t *testing.T) {
+	unsigned := "https://example.com/a/b/c?foo=bar"
+
+	for _, f := range formatters {
+		for _, enc := range encoders {
+			signer := New([]byte("abc123"), f.formatter, enc.encoder)
+
+			t.Run(path.Join(f.name, enc.name), func(t *testing.T) {
+				c, err := signer.SignComponents(unsigned, time.Now().Add(time.Minute))
+				require.NoError(t, err)
+
+				err = signer.VerifyComponents(unsigned, c)
+				require.NoError(t, err)
+
+				t.Run("components match signed url", func(t *testing.T) {
+					u, err := url.Parse(unsigned)
+					require.NoError(t, err)
+
+					signer.addExpiry(u, c.Expiry)
+					signer.addSignature(u, c.Signature)
+
+					err = signer.Verify(u.String())
+					require.NoError(t, err)
+				})
+			})
+		}
+	}
+
+	signer := New([]byte("abc123"))
+
+	t.Run("different url", func(t *testing.T) {
+		c, err := signer.SignComponents(unsigned, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = signer.VerifyComponents("https://example.com/a/b/d?foo=bar", c)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("different expiry", func(t *testing.T) {
+		c, err := signer.SignComponents(unsigned, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		c.Expiry = signer.Encode(time.Now().Add(time.Hour).Unix())
+
+		err = signer.VerifyComponents(unsigned, c)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c, err := signer.SignComponents(unsigned, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		err = signer.VerifyComponents(unsigned, c)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("expiry options", func(t *testing.T) {
+		for _, opt := range []Option{
+			WithExpiryGranularity(time.Hour),
+			WithMillisecondExpiry(),
+			WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		} {
+			signer := New([]byte("abc123"), opt)
+			expiry := time.Now().Add(time.Minute)
+
+			c, err := signer.SignComponents(unsigned, expiry)
+			require.NoError(t, err)
+			require.NoError(t, signer.VerifyComponents(unsigned, c))
+
+			signed, err := signer.Sign(unsigned, expiry)
+			require.NoError(t, err)
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			assert.Equal(t, u.Query().Get("expiry"), c.Expiry)
+		}
+	})
+}
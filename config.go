@@ -0,0 +1,191 @@
+package surl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Config describes the configuration of a Signer. It contains no secrets, and
+// is intended for logging and comparing configurations across services when
+// chasing verification mismatches.
+type Config struct {
+	// Algorithm is the name of the signature algorithm: blake2b-256,
+	// hmac-sha256, hmac-sha512, ed25519, ecdsa-sha256, ecdsa-sha384,
+	// ecdsa-sha512, rsa-sha256, rsa-sha1 for CloudFront, or custom for a
+	// SignFunc.
+	Algorithm string
+	// Formatter is the name of the formatter: query, short-query, path,
+	// compact, suffix, host or cloudfront.
+	Formatter string
+	// ExpiryEncoding is the name of the expiry encoding: decimal, base58,
+	// base64 or timestamp.
+	ExpiryEncoding string
+	// Epoch is the epoch from which expiries are encoded, or the zero time
+	// for the Unix epoch.
+	Epoch time.Time
+	// Prefix is the path prefix.
+	Prefix string
+	// Scope is the scope of a scoped signer.
+	Scope string
+	// Purpose is the purpose from which the key is derived.
+	Purpose        string
+	SkipQuery      bool
+	SkipScheme     bool
+	SkipHost       bool
+	SelfDescribing bool
+	// V1Compat is whether URLs signed by version 1 of the module are
+	// accepted.
+	V1Compat bool
+	// SignatureLength is the length to which signatures are truncated, or
+	// zero if they are not.
+	SignatureLength int
+	// WebhookTolerance is the maximum age of a webhook signature.
+	WebhookTolerance time.Duration
+	// KeyFingerprint identifies the key without revealing it.
+	KeyFingerprint string
+	// FallbackKeyFingerprints identify the fallback keys, if any, without
+	// revealing them.
+	FallbackKeyFingerprints []string
+	// OverrideKeyFingerprint identifies the override key, if any, without
+	// revealing it.
+	OverrideKeyFingerprint string
+}
+
+// Config returns the configuration of the signer.
+func (s *Signer) Config() Config {
+	c := Config{
+		Algorithm:        algorithmName(s.alg),
+		Formatter:        formatterName(s.formatter),
+		ExpiryEncoding:   encodingName(s.ExpiryEncoding),
+		Prefix:           s.prefix,
+		Scope:            s.scope,
+		Purpose:          s.purpose,
+		SkipQuery:        s.skipQuery,
+		SkipScheme:       s.skipScheme,
+		SkipHost:         s.skipHost,
+		SelfDescribing:   s.selfDescribing,
+		V1Compat:         s.v1Compat,
+		SignatureLength:  s.signatureLength,
+		WebhookTolerance: s.webhookTolerance,
+		KeyFingerprint:   fingerprint(s.alg),
+	}
+	if s.epoch != 0 {
+		c.Epoch = time.Unix(s.epoch, 0).UTC()
+	}
+	for _, fb := range s.fallbacks {
+		c.FallbackKeyFingerprints = append(c.FallbackKeyFingerprints, fingerprint(fb.alg))
+	}
+	if s.override != nil {
+		c.OverrideKeyFingerprint = fingerprint(s.override.alg)
+	}
+	return c
+}
+
+// String returns the configuration as space-separated key=value pairs.
+func (c Config) String() string {
+	pairs := []string{
+		"algorithm=" + c.Algorithm,
+		"formatter=" + c.Formatter,
+		"expiry_encoding=" + c.ExpiryEncoding,
+		fmt.Sprintf("prefix=%q", c.Prefix),
+		fmt.Sprintf("scope=%q", c.Scope),
+		fmt.Sprintf("purpose=%q", c.Purpose),
+		fmt.Sprintf("skip_query=%t", c.SkipQuery),
+		fmt.Sprintf("skip_scheme=%t", c.SkipScheme),
+		fmt.Sprintf("skip_host=%t", c.SkipHost),
+		fmt.Sprintf("self_describing=%t", c.SelfDescribing),
+		"webhook_tolerance=" + c.WebhookTolerance.String(),
+		"key_fingerprint=" + c.KeyFingerprint,
+	}
+	if !c.Epoch.IsZero() {
+		pairs = append(pairs, "epoch="+c.Epoch.Format(time.RFC3339))
+	}
+	if c.V1Compat {
+		pairs = append(pairs, "v1_compat=true")
+	}
+	if c.SignatureLength > 0 {
+		pairs = append(pairs, fmt.Sprintf("signature_length=%d", c.SignatureLength))
+	}
+	if len(c.FallbackKeyFingerprints) > 0 {
+		pairs = append(pairs, "fallback_key_fingerprints="+strings.Join(c.FallbackKeyFingerprints, ","))
+	}
+	if c.OverrideKeyFingerprint != "" {
+		pairs = append(pairs, "override_key_fingerprint="+c.OverrideKeyFingerprint)
+	}
+	return strings.Join(pairs, " ")
+}
+
+// fingerprint identifies a key by using it to sign a fixed message. The
+// message cannot be confused with a URL or token, and the result is truncated,
+// so the fingerprint is useless as a signature. Algorithms may instead provide
+// their own fingerprint, e.g. of their public key. An empty string is returned
+// if there is no fingerprint.
+func fingerprint(alg algorithm) string {
+	var sum []byte
+	if f, ok := alg.(interface{ fingerprint() []byte }); ok {
+		sum = f.fingerprint()
+	} else {
+		sum, _ = alg.sign(bind("", "fingerprint"))
+	}
+	if len(sum) < 8 {
+		return ""
+	}
+	return hex.EncodeToString(sum[:8])
+}
+
+// algorithmName names a signature algorithm, returning "custom" if it is
+// unknown.
+func algorithmName(a algorithm) string {
+	switch v := a.(type) {
+	case *keyedHash:
+		return v.name
+	case *publicKeyAlgorithm:
+		return v.name
+	case *fileKey:
+		return v.name()
+	}
+	return "custom"
+}
+
+// formatterName names a formatter, returning "custom" if it is unknown.
+func formatterName(f formatter) string {
+	switch v := f.(type) {
+	case *queryFormatter:
+		switch *v {
+		case *newQueryFormatter():
+			return "query"
+		case *newShortQueryFormatter():
+			return "short-query"
+		}
+	case *pathFormatter:
+		return "path"
+	case *compactFormatter:
+		return "compact"
+	case *suffixFormatter:
+		return "suffix"
+	case *hostFormatter:
+		return "host"
+	case *cloudFrontFormatter:
+		return "cloudfront"
+	}
+	return "custom"
+}
+
+// encodingName names an expiry encoding, returning "custom" if it is unknown.
+func encodingName(e ExpiryEncoding) string {
+	switch v := e.(type) {
+	case stdIntEncoding:
+		if v == 10 {
+			return "decimal"
+		}
+	case base58Encoding, *base58Encoding:
+		return "base58"
+	case base64Encoding, *base64Encoding:
+		return "base64"
+	case timestampEncoding:
+		return "timestamp"
+	}
+	return "custom"
+}
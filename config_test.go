@@ -0,0 +1,55 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_Config(t *testing.T) {
+	signer := New([]byte("abc123"), WithPathFormatter(), WithBase58Expiry(), PrefixPath("/signed"), SkipQuery())
+
+	got := signer.Config()
+	assert.Equal(t, "path", got.Formatter)
+	assert.Equal(t, "base58", got.ExpiryEncoding)
+	assert.Equal(t, "/signed", got.Prefix)
+	assert.True(t, got.SkipQuery)
+	assert.False(t, got.SkipScheme)
+	assert.Equal(t, DefaultWebhookTolerance, got.WebhookTolerance)
+	assert.Len(t, got.KeyFingerprint, 16)
+	assert.Empty(t, got.OverrideKeyFingerprint)
+
+	t.Run("fingerprint depends on key alone", func(t *testing.T) {
+		assert.Equal(t, got.KeyFingerprint, New([]byte("abc123")).Config().KeyFingerprint)
+		assert.NotEqual(t, got.KeyFingerprint, New([]byte("xyz789")).Config().KeyFingerprint)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		want := `algorithm=blake2b-256 formatter=path expiry_encoding=base58 prefix="/signed" scope="" purpose="" skip_query=true skip_scheme=false skip_host=false self_describing=false webhook_tolerance=5m0s key_fingerprint=` + got.KeyFingerprint
+		assert.Equal(t, want, got.String())
+		assert.NotContains(t, got.String(), "abc123")
+	})
+
+	t.Run("signature length", func(t *testing.T) {
+		got := New([]byte("abc123"), WithSignatureLength(16)).Config()
+		assert.Equal(t, 16, got.SignatureLength)
+		assert.Contains(t, got.String(), "signature_length=16")
+	})
+
+	t.Run("v1 compat", func(t *testing.T) {
+		got := New([]byte("abc123"), WithV1Compat()).Config()
+		assert.True(t, got.V1Compat)
+		assert.Contains(t, got.String(), "v1_compat=true")
+	})
+
+	t.Run("override", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithOverrideKey([]byte("xyz789"), nil), WithWebhookTolerance(time.Minute))
+
+		got := signer.Config()
+		assert.Equal(t, "query", got.Formatter)
+		assert.Equal(t, "decimal", got.ExpiryEncoding)
+		assert.Equal(t, time.Minute, got.WebhookTolerance)
+		assert.Equal(t, New([]byte("xyz789")).Config().KeyFingerprint, got.OverrideKeyFingerprint)
+	})
+}
@@ -0,0 +1,74 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Cookie(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	t.Run("valid", func(t *testing.T) {
+		c := &http.Cookie{Name: "session", Value: "user=42", Path: "/"}
+		require.NoError(t, signer.SignCookie(c, time.Now().Add(time.Minute)))
+		assert.NotEqual(t, "user=42", c.Value)
+		assert.NoError(t, c.Valid())
+
+		// round trip through a response and request
+		w := httptest.NewRecorder()
+		http.SetCookie(w, c)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Cookie", w.Header().Get("Set-Cookie"))
+		got, err := r.Cookie("session")
+		require.NoError(t, err)
+
+		value, err := signer.VerifyCookie(got)
+		require.NoError(t, err)
+		assert.Equal(t, "user=42", value)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c := &http.Cookie{Name: "session", Value: "user=42"}
+		require.NoError(t, signer.SignCookie(c, time.Now().Add(-time.Minute)))
+
+		_, err := signer.VerifyCookie(c)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		c := &http.Cookie{Name: "session", Value: "user=42"}
+		require.NoError(t, signer.SignCookie(c, time.Now().Add(time.Minute)))
+		c.Name = "admin"
+
+		_, err := signer.VerifyCookie(c)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("token is not accepted", func(t *testing.T) {
+		token, err := signer.SignBytes([]byte("user=42"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyCookie(&http.Cookie{Name: "session", Value: token})
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("rotated key", func(t *testing.T) {
+		c := &http.Cookie{Name: "session", Value: "user=42"}
+		require.NoError(t, New([]byte("old")).SignCookie(c, time.Now().Add(time.Minute)))
+
+		rotated := New([]byte("new"), WithFallbackKeys([]byte("old")))
+		value, err := rotated.VerifyCookie(c)
+		require.NoError(t, err)
+		assert.Equal(t, "user=42", value)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := signer.VerifyCookie(&http.Cookie{Name: "session", Value: "user=42"})
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+}
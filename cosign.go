@@ -0,0 +1,121 @@
+package surl
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// cosignatureParam is the query parameter that carries a co-signature.
+const cosignatureParam = "cosignature"
+
+// ErrInsufficientCosignatures is returned when a signed URL lacks the required
+// number of valid co-signatures.
+var ErrInsufficientCosignatures = errors.New("insufficient co-signatures")
+
+// Cosign adds a co-signature to a URL already signed by another party, e.g.
+// an approving service adding its signature to a URL issued by another
+// service. The co-signature is identified by id, and covers the signed URL
+// excluding any other co-signatures, so that co-signatures are independent of
+// one another. Co-signatures are carried in query parameters regardless of
+// the signer's formatter.
+func (s *Signer) Cosign(signed, id string) (string, error) {
+	if id == "" || strings.Contains(id, ".") {
+		return "", fmt.Errorf("invalid co-signature id: %q", id)
+	}
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return "", err
+	}
+	base, _ := splitCosignatures(*u)
+	sig, err := s.sign(context.Background(), bind(base.String(), cosignatureParam+":"+id))
+	if err != nil {
+		return "", err
+	}
+
+	appendQueryParam(u, cosignatureParam, id+"."+base64.RawURLEncoding.EncodeToString(sig))
+	return u.String(), nil
+}
+
+// Cosigned verifies URLs that require co-signatures in addition to the
+// signature of the issuer.
+type Cosigned struct {
+	// Issuer verifies the signature and expiry of the URL.
+	Issuer *Signer
+	// Cosigners maps co-signature IDs to the signers that verify them.
+	Cosigners map[string]*Signer
+	// Threshold is the number of valid co-signatures required. Zero requires
+	// a valid co-signature from every cosigner.
+	Threshold int
+}
+
+// Verify verifies the issuer's signature on the URL, and that it carries the
+// required number of valid co-signatures.
+func (c *Cosigned) Verify(signed string) error {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	base, cosigs := splitCosignatures(*u)
+
+	if err := c.Issuer.Verify(base.String()); err != nil {
+		return err
+	}
+
+	valid := make(map[string]bool)
+	for _, cosig := range cosigs {
+		id, encodedSig, found := strings.Cut(cosig, ".")
+		if !found {
+			return fmt.Errorf("%w: invalid co-signature: %s", ErrInvalidFormat, cosig)
+		}
+		cosigner, ok := c.Cosigners[id]
+		if !ok {
+			continue
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+		if err != nil {
+			continue
+		}
+		err = cosigner.verify(context.Background(), bind(base.String(), cosignatureParam+":"+id), sig)
+		if err == nil {
+			valid[id] = true
+		} else if !errors.Is(err, ErrInvalidSignature) {
+			return err
+		}
+	}
+
+	threshold := c.Threshold
+	if threshold == 0 {
+		threshold = len(c.Cosigners)
+	}
+	if len(valid) < threshold {
+		return fmt.Errorf("%w: got %d, want %d", ErrInsufficientCosignatures, len(valid), threshold)
+	}
+	return nil
+}
+
+// splitCosignatures removes co-signatures from a URL, returning the URL and the
+// co-signatures. The remainder of the query is left untouched.
+func splitCosignatures(u url.URL) (url.URL, []string) {
+	if u.RawQuery == "" {
+		return u, nil
+	}
+	var (
+		params []string
+		cosigs []string
+	)
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		if value, found := strings.CutPrefix(param, cosignatureParam+"="); found {
+			if unescaped, err := url.QueryUnescape(value); err == nil {
+				cosigs = append(cosigs, unescaped)
+				continue
+			}
+		}
+		params = append(params, param)
+	}
+	u.RawQuery = strings.Join(params, "&")
+	return u, cosigs
+}
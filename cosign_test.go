@@ -0,0 +1,93 @@
+package surl
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosign(t *testing.T) {
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			issuer := New([]byte("issuer"), f.formatter)
+			approver := New([]byte("approver"))
+			auditor := New([]byte("auditor"))
+
+			signed, err := issuer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			approved, err := approver.Cosign(signed, "approver")
+			require.NoError(t, err)
+			audited, err := auditor.Cosign(approved, "auditor")
+			require.NoError(t, err)
+
+			tests := []struct {
+				name      string
+				signed    string
+				threshold int
+				want      error
+			}{
+				{name: "all co-signatures", signed: audited},
+				{name: "one of two co-signatures", signed: approved, threshold: 1},
+				{name: "missing co-signature", signed: approved, want: ErrInsufficientCosignatures},
+				{name: "no co-signatures", signed: signed, threshold: 1, want: ErrInsufficientCosignatures},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					cosigned := &Cosigned{
+						Issuer: issuer,
+						Cosigners: map[string]*Signer{
+							"approver": approver,
+							"auditor":  auditor,
+						},
+						Threshold: tt.threshold,
+					}
+					err := cosigned.Verify(tt.signed)
+					assert.Truef(t, errors.Is(err, tt.want), "got error: %v", err)
+				})
+			}
+		})
+	}
+
+	t.Run("forged co-signature", func(t *testing.T) {
+		issuer := New([]byte("issuer"))
+		approver := New([]byte("approver"))
+
+		signed, err := issuer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		forged, err := New([]byte("forger")).Cosign(signed, "approver")
+		require.NoError(t, err)
+
+		cosigned := &Cosigned{
+			Issuer:    issuer,
+			Cosigners: map[string]*Signer{"approver": approver},
+		}
+		err = cosigned.Verify(forged)
+		assert.True(t, errors.Is(err, ErrInsufficientCosignatures), "got error: %v", err)
+	})
+
+	t.Run("co-signature moved to another url", func(t *testing.T) {
+		issuer := New([]byte("issuer"))
+		approver := New([]byte("approver"))
+
+		signed, err := issuer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		approved, err := approver.Cosign(signed, "approver")
+		require.NoError(t, err)
+		other, err := issuer.Sign("https://example.com/x/y/z", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		u, err := url.Parse(approved)
+		require.NoError(t, err)
+		_, cosigs := splitCosignatures(*u)
+		cosigned := &Cosigned{
+			Issuer:    issuer,
+			Cosigners: map[string]*Signer{"approver": approver},
+		}
+		err = cosigned.Verify(other + "&cosignature=" + cosigs[0])
+		assert.True(t, errors.Is(err, ErrInsufficientCosignatures), "got error: %v", err)
+	})
+}
@@ -0,0 +1,19 @@
+package surl
+
+import "time"
+
+// cursorPurpose distinguishes cursor tokens from other tokens.
+const cursorPurpose = "cursor"
+
+// SignCursor signs an opaque pagination cursor, returning a tamper-proof token
+// that is safe to hand to API clients, e.g. in a next page link. The cursor
+// itself is not encrypted.
+func (s *Signer) SignCursor(cursor []byte, expiry time.Time) (string, error) {
+	return s.signToken(cursorPurpose, cursor, expiry)
+}
+
+// VerifyCursor verifies a token produced by SignCursor, returning the
+// cursor.
+func (s *Signer) VerifyCursor(token string) ([]byte, error) {
+	return s.verifyToken(cursorPurpose, token)
+}
@@ -0,0 +1,64 @@
+package surl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Cursor(t *testing.T) {
+	for _, enc := range encoders {
+		t.Run(enc.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), enc.encoder)
+
+			token, err := signer.SignCursor([]byte(`{"after":1234}`), time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			got, err := signer.VerifyCursor(token)
+			require.NoError(t, err)
+			assert.Equal(t, `{"after":1234}`, string(got))
+		})
+	}
+
+	signer := New([]byte("abc123"))
+
+	t.Run("empty cursor", func(t *testing.T) {
+		token, err := signer.SignCursor(nil, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		got, err := signer.VerifyCursor(token)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("tampered cursor", func(t *testing.T) {
+		token, err := signer.SignCursor([]byte("1234"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		forged, err := signer.SignCursor([]byte("5678"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		// splice data from one token into another
+		_, rest, _ := strings.Cut(token, ".")
+		data, _, _ := strings.Cut(forged, ".")
+
+		_, err = signer.VerifyCursor(data + "." + rest)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := signer.SignCursor([]byte("1234"), time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyCursor(token)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := signer.VerifyCursor("garbage")
+		assert.True(t, errors.Is(err, ErrInvalidFormat), "got error: %v", err)
+	})
+}
@@ -0,0 +1,99 @@
+package surl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// dataParam is the query parameter that carries the data embedded in a signed
+// URL.
+const dataParam = "signature_data"
+
+// SignWithData is like Sign but embeds the data in the signed URL, covered by
+// the signature, e.g. a user ID or file ID, which is retrieved with
+// VerifyData. The data is encoded rather than encrypted, so it is readable by
+// anyone holding the URL.
+func (s *Signer) SignWithData(unsigned string, expiry time.Time, data map[string]string) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return s.signData(unsigned, expiry, encoded)
+}
+
+// VerifyData verifies a signed URL, as Verify does, returning the data
+// embedded with SignWithData, or nil if it has none.
+func (s *Signer) VerifyData(signed string) (map[string]string, error) {
+	encoded, err := s.verifyData(signed)
+	if err != nil || encoded == nil {
+		return nil, err
+	}
+	var data map[string]string
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, fmt.Errorf("%w: invalid data: %s", ErrInvalidFormat, err.Error())
+	}
+	return data, nil
+}
+
+// signData signs the URL, embedding the JSON-encoded data.
+func (s *Signer) signData(unsigned string, expiry time.Time, data []byte) (string, error) {
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	withData := *s
+	withData.data = data
+	if err := withData.signURL(context.Background(), u, expiry, ""); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// verifyData verifies the signed URL, returning its JSON-encoded data.
+func (s *Signer) verifyData(signed string) ([]byte, error) {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.verifyURL(context.Background(), u, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// encodeData encodes, and if configured encrypts, the data of a signed URL.
+func (s *Signer) encodeData(data []byte) (string, error) {
+	if s.encryptData {
+		var err error
+		if data, err = encryptData(s.key, data); err != nil {
+			return "", err
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// dataBinding binds the encoded data of a signed URL, along with any other
+// binding, to its signature.
+func dataBinding(data, binding string) string {
+	return tagBinding("data", data, binding)
+}
+
+// extractData removes the data from the query of a signed URL, returning
+// both its encoded and decoded forms, or empty values if it has none. The
+// decoded data is not decrypted.
+func extractData(u *url.URL) (string, []byte, error) {
+	encoded, err := removeQueryParam(u, dataParam)
+	if err != nil || encoded == "" {
+		return "", nil, err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: invalid data", ErrInvalidFormat)
+	}
+	return encoded, data, nil
+}
@@ -0,0 +1,73 @@
+package surl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithData(t *testing.T) {
+	signer := New([]byte("abc123"))
+	data := map[string]string{"user": "bob", "file": "42"}
+
+	signed, err := signer.SignWithData("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute), data)
+	require.NoError(t, err)
+
+	got, err := signer.VerifyData(signed)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	// also verifies as an ordinary signed URL
+	assert.NoError(t, signer.Verify(signed))
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered, err := signer.SignWithData("https://example.com/a/b/c", time.Now().Add(time.Minute), map[string]string{"user": "alice"})
+		require.NoError(t, err)
+		tamperedURL, err := url.Parse(tampered)
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(dataParam, tamperedURL.Query().Get(dataParam))
+		u.RawQuery = q.Encode()
+
+		_, err = signer.VerifyData(u.String())
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		_, err := signer.VerifyData(signed + "!")
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		got, err := signer.VerifyData(signed)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignWithData("https://example.com/a/b/c", time.Now().Add(-time.Minute), data)
+		require.NoError(t, err)
+
+		_, err = signer.VerifyData(signed)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("skip query", func(t *testing.T) {
+		signer := New([]byte("abc123"), SkipQuery())
+		signed, err := signer.SignWithData("https://example.com/a/b/c", time.Now().Add(time.Minute), data)
+		require.NoError(t, err)
+
+		got, err := signer.VerifyData(signed)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+}
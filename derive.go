@@ -0,0 +1,18 @@
+package surl
+
+import (
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// deriveKey derives a 32-byte key from a parent key with HKDF-SHA256, with
+// info distinguishing keys derived for different uses.
+func deriveKey(parent []byte, info string) []byte {
+	key := make([]byte, 32)
+	kdf := hkdf.New(sha256.New, parent, nil, []byte(info))
+	// Reading from HKDF only errors when more than 255 blocks are read.
+	_, _ = io.ReadFull(kdf, key)
+	return key
+}
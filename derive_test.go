@@ -0,0 +1,15 @@
+package surl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveKey(t *testing.T) {
+	key := deriveKey([]byte("abc123"), "surl purpose invite")
+	assert.Len(t, key, 32)
+	assert.Equal(t, key, deriveKey([]byte("abc123"), "surl purpose invite"))
+	assert.NotEqual(t, key, deriveKey([]byte("abc123"), "surl purpose reset"))
+	assert.NotEqual(t, key, deriveKey([]byte("xyz789"), "surl purpose invite"))
+}
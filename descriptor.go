@@ -0,0 +1,152 @@
+package surl
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// descriptorSeparator separates the descriptor from the signature in a
+// self-describing signed URL. It is not part of the base64 URL alphabet.
+const descriptorSeparator = "~"
+
+// errUndescribable is returned when a signer is configured with a formatter
+// or expiry encoding that lacks an identifier, or with a custom epoch or
+// millisecond expiries, which the descriptor does not record.
+var errUndescribable = errors.New("signer configuration cannot be self-described")
+
+// algorithmIDs identifies signature algorithms.
+var algorithmIDs = map[byte]string{
+	'b': "blake2b-256",
+	'h': "hmac-sha256",
+	'H': "hmac-sha512",
+	'e': "ed25519",
+	'E': "ecdsa-sha256",
+	'R': "rsa-sha256",
+}
+
+// macIDs constructs the MACs identified by algorithm IDs from a key. A signer
+// holding a key verifies URLs described with any of them.
+var macIDs = map[byte]func(key []byte) *keyedHash{
+	'b': newKeyedHash,
+	'h': func(key []byte) *keyedHash { return newHMAC("hmac-sha256", sha256.New, key) },
+	'H': func(key []byte) *keyedHash { return newHMAC("hmac-sha512", sha512.New, key) },
+}
+
+// formatterIDs identifies formatters, in the order in which they are tried
+// when detecting the formatter of a self-describing URL.
+var formatterIDs = []struct {
+	id   byte
+	name string
+	new  func() formatter
+}{
+	{'q', "query", func() formatter { return newQueryFormatter() }},
+	{'s', "short-query", func() formatter { return newShortQueryFormatter() }},
+	{'p', "path", func() formatter { return &pathFormatter{} }},
+	{'c', "compact", func() formatter { return &compactFormatter{} }},
+	{'x', "suffix", func() formatter { return &suffixFormatter{} }},
+}
+
+// encodingIDs identifies expiry encodings.
+var encodingIDs = []struct {
+	id       byte
+	name     string
+	encoding ExpiryEncoding
+}{
+	{'d', "decimal", stdIntEncoding(10)},
+	{'5', "base58", base58Encoding{}},
+	{'6', "base64", base64Encoding{}},
+	{'t', "timestamp", timestampEncoding{}},
+}
+
+// SelfDescribing instructs Signer to embed a compact descriptor of its
+// signature algorithm, formatter, and expiry encoding in the signature of signed URLs.
+// When verifying, a self-describing Signer reads the descriptor and verifies
+// the URL accordingly, regardless of its own formatter and expiry encoding,
+// and, if it holds a key, regardless of which of BLAKE2b-256, HMAC-SHA256 or
+// HMAC-SHA512 computed the signature with the key. Other algorithms, e.g.
+// Ed25519, must match the signer's own. This permits a single Signer to verify
+// URLs with heterogeneous formats, allowing formats and MACs to be changed
+// incrementally. The descriptor is covered by the signature.
+//
+// The descriptor does not record a custom epoch or millisecond expiries, so
+// signers configured with WithEpoch or WithMillisecondExpiry cannot sign
+// self-describing URLs.
+func SelfDescribing() Option {
+	return func(s *Signer) {
+		s.selfDescribing = true
+	}
+}
+
+// describe returns the descriptor for the signer's configuration.
+func (s *Signer) describe() (string, error) {
+	var a, f, e byte
+	for id, name := range algorithmIDs {
+		if name == algorithmName(s.alg) {
+			a = id
+		}
+	}
+	for _, d := range formatterIDs {
+		if d.name == formatterName(s.formatter) {
+			f = d.id
+		}
+	}
+	for _, d := range encodingIDs {
+		if d.name == encodingName(s.ExpiryEncoding) {
+			e = d.id
+		}
+	}
+	if a == 0 || f == 0 || e == 0 || s.epoch != 0 || s.millisecondExpiry {
+		return "", errUndescribable
+	}
+	return string([]byte{a, f, e}), nil
+}
+
+// described detects the descriptor in a self-describing signed URL and
+// returns a copy of the signer configured according to the descriptor.
+func (s *Signer) described(u *url.URL) (*Signer, error) {
+	for _, d := range formatterIDs {
+		f := d.new()
+
+		// extract signature from a copy to leave the URL intact
+		c := *u
+		sig, err := f.extractSignature(&c)
+		if err != nil {
+			continue
+		}
+		desc, _, found := strings.Cut(sig, descriptorSeparator)
+		if !found || len(desc) != 3 || desc[1] != d.id {
+			continue
+		}
+		alg, ok := algorithmIDs[desc[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown algorithm: %c", ErrInvalidFormat, desc[0])
+		}
+		clone := *s
+		// a signer without an algorithm only parses URLs
+		if s.alg != nil && alg != algorithmName(s.alg) {
+			mac, ok := macIDs[desc[0]]
+			if _, keyed := s.alg.(*keyedHash); !ok || !keyed {
+				return nil, fmt.Errorf("%w: unsupported algorithm: %s", ErrInvalidSignature, alg)
+			}
+			withHash(mac)(&clone)
+		}
+		clone.epoch = 0
+		clone.millisecondExpiry = false
+		clone.formatter = f
+		clone.ExpiryEncoding = nil
+		for _, e := range encodingIDs {
+			if e.id == desc[2] {
+				clone.ExpiryEncoding = e.encoding
+			}
+		}
+		if clone.ExpiryEncoding == nil {
+			return nil, fmt.Errorf("%w: unknown expiry encoding: %c", ErrInvalidFormat, desc[2])
+		}
+		return &clone, nil
+	}
+	return nil, fmt.Errorf("%w: missing descriptor", ErrInvalidFormat)
+}
@@ -0,0 +1,97 @@
+package surl
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SelfDescribing(t *testing.T) {
+	// verifier uses the default formatter and encoding
+	verifier := New([]byte("abc123"), SelfDescribing())
+
+	for _, f := range formatters {
+		for _, enc := range encoders {
+			signer := New([]byte("abc123"), SelfDescribing(), f.formatter, enc.encoder)
+
+			t.Run(path.Join(f.name, enc.name), func(t *testing.T) {
+				signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+				require.NoError(t, err)
+
+				err = verifier.Verify(signed)
+				require.NoError(t, err)
+			})
+		}
+	}
+
+	t.Run("tampered descriptor", func(t *testing.T) {
+		signer := New([]byte("abc123"), SelfDescribing(), WithBase58Expiry())
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Contains(t, signed, "bq5~")
+
+		err = verifier.Verify(strings.Replace(signed, "bq5~", "bqd~", 1))
+		assert.Truef(t, errors.Is(err, ErrInvalidSignature), "got error: %v", err)
+	})
+
+	t.Run("mac", func(t *testing.T) {
+		for _, opt := range []Option{WithHMACSHA256(), WithHMACSHA512()} {
+			signer := New([]byte("abc123"), SelfDescribing(), opt)
+			signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			err = verifier.Verify(signed)
+			require.NoError(t, err)
+
+			// and vice versa
+			signed, err = verifier.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			err = signer.Verify(signed)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("mismatched algorithm", func(t *testing.T) {
+		_, private, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		signed, err := NewEd25519(private, SelfDescribing()).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = verifier.Verify(signed)
+		assert.Truef(t, errors.Is(err, ErrInvalidSignature), "got error: %v", err)
+	})
+
+	t.Run("custom epoch", func(t *testing.T) {
+		epoch := New([]byte("abc123"), SelfDescribing(), WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+		// a self-describing URL is verified with the standard epoch...
+		signed, err := verifier.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, epoch.Verify(signed))
+
+		// ...and so a custom epoch cannot be described
+		_, err = epoch.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, errUndescribable)
+	})
+
+	t.Run("millisecond expiry", func(t *testing.T) {
+		_, err := New([]byte("abc123"), SelfDescribing(), WithMillisecondExpiry()).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, errUndescribable)
+	})
+
+	t.Run("missing descriptor", func(t *testing.T) {
+		signed, err := New([]byte("abc123")).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = verifier.Verify(signed)
+		assert.Truef(t, errors.Is(err, ErrInvalidFormat), "got error: %v", err)
+	})
+}
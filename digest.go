@@ -0,0 +1,203 @@
+package surl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// digestParam is the query parameter that carries the expected SHA-256
+// digest of the content of a signed URL.
+const digestParam = "signature_digest"
+
+// ErrDigestMismatch is returned when content does not match the digest of its
+// signed URL.
+var ErrDigestMismatch = errors.New("content does not match digest")
+
+// SignWithDigest is like Sign but includes the expected SHA-256 digest of the
+// content uploaded to, or downloaded from, the URL. The digest is added to the
+// URL and covered by the signature, and is checked by middleware configured
+// with CheckDigest, guaranteeing the integrity of the content end to end.
+func (s *Signer) SignWithDigest(unsigned string, expiry time.Time, digest []byte) (string, error) {
+	if len(digest) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 digest length: %d", len(digest))
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	withDigest := *s
+	withDigest.digest = digest
+	if err := withDigest.signURL(context.Background(), u, expiry, ""); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// DefaultDigestUploadLimit is the default maximum length of uploads checked by
+// CheckDigest; see DigestUploadLimit.
+const DefaultDigestUploadLimit = 32 << 20 // 32 MiB
+
+// CheckDigest checks content against the digest of URLs signed with
+// SignWithDigest.
+//
+// The body of a request with a body, i.e. an upload, is read and checked
+// before it is passed to the next handler, so that the handler never sees
+// content that does not match. Uploads must therefore have a known length,
+// which must not exceed the limit set with DigestUploadLimit; otherwise they
+// receive a 411 Length Required or a 413 Request Entity Too Large response.
+// Uploads that do not match receive a 400 Bad Request response.
+//
+// Otherwise the response, i.e. a download, is streamed and checked as it is
+// written, withholding its final byte until the whole of it has been checked.
+// Should a successful response not match, the connection is aborted without
+// the final byte, so that the client never receives content that appears
+// complete, or, if no content has been sent yet, a 500 Internal Server Error
+// response is sent instead. Range requests are served in full so that the
+// whole content is checked.
+func CheckDigest() MiddlewareOption {
+	return func(m *middleware) {
+		m.checkDigest = true
+	}
+}
+
+// DigestUploadLimit sets the maximum length, in bytes, of uploads checked by
+// CheckDigest, which are held in memory until they have been checked. The
+// default is DefaultDigestUploadLimit.
+func DigestUploadLimit(n int64) MiddlewareOption {
+	return func(m *middleware) {
+		m.digestUploadLimit = n
+	}
+}
+
+// serveDigest serves the request, checking content against the digest.
+// Uploads longer than the limit are rejected.
+func serveDigest(next http.Handler, w http.ResponseWriter, r *http.Request, digest []byte, limit int64) {
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		if r.ContentLength < 0 {
+			http.Error(w, "upload length required to check digest", http.StatusLengthRequired)
+			return
+		}
+		if r.ContentLength > limit {
+			http.Error(w, "upload exceeds digest limit", http.StatusRequestEntityTooLarge)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, r.ContentLength))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sum := sha256.Sum256(body)
+		if subtle.ConstantTimeCompare(sum[:], digest) != 1 {
+			http.Error(w, ErrDigestMismatch.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+		return
+	}
+	if r.Method == http.MethodHead {
+		next.ServeHTTP(w, r)
+		return
+	}
+	r = r.Clone(r.Context())
+	r.Header.Del("Range")
+	r.Header.Del("If-Range")
+	dw := &digestResponseWriter{ResponseWriter: w, hash: sha256.New(), checking: true}
+	next.ServeHTTP(dw, r)
+	dw.finish(digest)
+}
+
+// digestResponseWriter checks a successful response against a digest as it is
+// written, withholding its final byte until the response is finished.
+type digestResponseWriter struct {
+	http.ResponseWriter
+	hash     hash.Hash
+	checking bool // whether the response is being checked
+	flushed  bool // whether any content has been written
+	pending  []byte
+}
+
+func (d *digestResponseWriter) WriteHeader(status int) {
+	if status != http.StatusOK {
+		// only successful responses are checked
+		d.checking = false
+		d.ResponseWriter.WriteHeader(status)
+	}
+	// a successful status is written along with the content, if it matches
+}
+
+func (d *digestResponseWriter) Write(p []byte) (int, error) {
+	if !d.checking {
+		return d.ResponseWriter.Write(p)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	d.hash.Write(p)
+	if err := d.write(d.pending); err != nil {
+		return 0, err
+	}
+	if err := d.write(p[:len(p)-1]); err != nil {
+		return 0, err
+	}
+	d.pending = append(d.pending[:0], p[len(p)-1])
+	return len(p), nil
+}
+
+// write writes content to the underlying response.
+func (d *digestResponseWriter) write(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+	d.flushed = true
+	_, err := d.ResponseWriter.Write(p)
+	return err
+}
+
+// finish checks the response against the digest, writing the final byte if it
+// matches, and aborting the response otherwise.
+func (d *digestResponseWriter) finish(digest []byte) {
+	if !d.checking {
+		return
+	}
+	if subtle.ConstantTimeCompare(d.hash.Sum(nil), digest) == 1 {
+		d.write(d.pending)
+		return
+	}
+	if d.flushed {
+		panic(http.ErrAbortHandler)
+	}
+	d.Header().Del("Content-Length")
+	d.Header().Del("Content-Encoding")
+	http.Error(d.ResponseWriter, ErrDigestMismatch.Error(), http.StatusInternalServerError)
+}
+
+// digestBinding binds the digest of a signed URL, along with any other
+// binding, to its signature.
+func digestBinding(digest []byte, binding string) string {
+	return tagBinding("digest", base64.RawURLEncoding.EncodeToString(digest), binding)
+}
+
+// extractDigest removes the digest from the query of a signed URL, returning
+// nil if it has none.
+func extractDigest(u *url.URL) ([]byte, error) {
+	encoded, err := removeQueryParam(u, digestParam)
+	if err != nil || encoded == "" {
+		return nil, err
+	}
+	digest, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(digest) != sha256.Size {
+		return nil, fmt.Errorf("%w: invalid digest: %s", ErrInvalidFormat, encoded)
+	}
+	return digest, nil
+}
@@ -0,0 +1,112 @@
+package surl
+
+import (
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithDigest(t *testing.T) {
+	signer := New([]byte("abc123"))
+	content := "hello world"
+	digest := sha256.Sum256([]byte(content))
+
+	signed, err := signer.SignWithDigest("https://example.com/files/a.txt", time.Now().Add(time.Minute), digest[:])
+	require.NoError(t, err)
+	assert.Contains(t, signed, "signature_digest=")
+	assert.NoError(t, signer.Verify(signed))
+
+	t.Run("invalid digest", func(t *testing.T) {
+		_, err := signer.SignWithDigest("https://example.com/files/a.txt", time.Now().Add(time.Minute), []byte("abc"))
+		assert.Error(t, err)
+	})
+
+	t.Run("download", func(t *testing.T) {
+		files := fstest.MapFS{"a.txt": {Data: []byte(content)}}
+		handler := signer.Middleware(http.StripPrefix("/files", http.FileServer(http.FS(files))), CheckDigest())
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, content, w.Body.String())
+
+		// served in full despite the range
+		r := httptest.NewRequest("GET", signed, nil)
+		r.Header.Set("Range", "bytes=0-1")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, content, w.Body.String())
+
+		// the connection is aborted before the final byte is sent
+		files["a.txt"].Data = []byte("tampered")
+		srv := httptest.NewTLSServer(handler)
+		defer srv.Close()
+		r = httptest.NewRequest("GET", srv.URL+strings.TrimPrefix(signed, "https://example.com"), nil)
+		r.RequestURI = ""
+		r.Host = "example.com"
+		resp, err := srv.Client().Do(r)
+		if err == nil {
+			_, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		assert.Error(t, err)
+	})
+
+	t.Run("download mismatch before content sent", func(t *testing.T) {
+		handler := signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), CheckDigest())
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		var got []byte
+		handler := signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = io.ReadAll(r.Body)
+		}), CheckDigest(), DigestUploadLimit(16))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("PUT", signed, strings.NewReader(content)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, content, string(got))
+
+		// the handler is not called with content that does not match
+		got = nil
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("PUT", signed, strings.NewReader("tampered")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, got)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("PUT", signed, strings.NewReader(strings.Repeat("a", 17))))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		r := httptest.NewRequest("PUT", signed, strings.NewReader(content))
+		r.ContentLength = -1
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusLengthRequired, w.Code)
+	})
+
+	t.Run("without digest", func(t *testing.T) {
+		unchecked, err := signer.Sign("https://example.com/files/a.txt", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		handler := signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("anything"))
+		}), CheckDigest())
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", unchecked, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
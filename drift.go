@@ -0,0 +1,94 @@
+package surl
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDriftWindow is the default window within which a lapsed expiry is
+// considered a sign of clock drift.
+const DefaultDriftWindow = 5 * time.Second
+
+// Drift is an anomaly observed by a DriftDetector.
+type Drift struct {
+	// ExpiresAt is the expiry of the signed URL.
+	ExpiresAt time.Time
+	// Offset is the time remaining until the expiry at the time of
+	// verification. It is negative if the URL had expired.
+	Offset time.Duration
+}
+
+// DriftStats are counts of verifications observed by a DriftDetector.
+type DriftStats struct {
+	// Verifications is the number of verifications with a valid signature.
+	Verifications int64
+	// RecentlyExpired is the number of verifications that failed because
+	// the URL had expired within the window.
+	RecentlyExpired int64
+	// FarFuture is the number of verifications that succeeded with an
+	// expiry beyond the horizon.
+	FarFuture int64
+}
+
+// DriftDetector observes verifications for signs of clock drift between the
+// machines signing URLs and those verifying them, e.g. because NTP has failed.
+// A high proportion of URLs that have only just expired suggests the
+// verifier's clock is ahead of the signer's, whereas URLs that expire
+// further ahead than any issued suggest the signer's clock is ahead.
+type DriftDetector struct {
+	// Window is the window within which a lapsed expiry is counted as
+	// recently expired. If zero, DefaultDriftWindow is used.
+	Window time.Duration
+	// Horizon is the furthest ahead an expiry is expected to be, typically
+	// the longest lifespan of any URL issued. Successful verifications with
+	// an expiry beyond it are counted as far future. If zero, far future
+	// expiries are not detected.
+	Horizon time.Duration
+	// Report, if non-nil, is called with each anomaly, e.g. to log it or to
+	// increment a metric.
+	Report func(Drift)
+
+	verifications   atomic.Int64
+	recentlyExpired atomic.Int64
+	farFuture       atomic.Int64
+}
+
+// WithDriftDetector instructs Signer to report verifications of URLs with a
+// valid signature to the drift detector.
+func WithDriftDetector(d *DriftDetector) Option {
+	return func(s *Signer) {
+		s.drift = d
+	}
+}
+
+// Stats returns the counts of verifications observed so far.
+func (d *DriftDetector) Stats() DriftStats {
+	return DriftStats{
+		Verifications:   d.verifications.Load(),
+		RecentlyExpired: d.recentlyExpired.Load(),
+		FarFuture:       d.farFuture.Load(),
+	}
+}
+
+// observe observes the verification at the given time of a URL with a valid
+// signature and the given expiry.
+func (d *DriftDetector) observe(expiresAt, now time.Time) {
+	d.verifications.Add(1)
+
+	window := d.Window
+	if window == 0 {
+		window = DefaultDriftWindow
+	}
+	offset := expiresAt.Sub(now)
+	switch {
+	case offset < 0 && offset >= -window:
+		d.recentlyExpired.Add(1)
+	case d.Horizon > 0 && offset > d.Horizon:
+		d.farFuture.Add(1)
+	default:
+		return
+	}
+	if d.Report != nil {
+		d.Report(Drift{ExpiresAt: expiresAt, Offset: offset})
+	}
+}
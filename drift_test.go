@@ -0,0 +1,45 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftDetector(t *testing.T) {
+	var reported []Drift
+	detector := &DriftDetector{
+		Horizon: time.Hour,
+		Report:  func(d Drift) { reported = append(reported, d) },
+	}
+	signer := New([]byte("abc123"), WithDriftDetector(detector))
+
+	sign := func(expiry time.Time) string {
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+		return signed
+	}
+
+	// normal
+	assert.NoError(t, signer.Verify(sign(time.Now().Add(time.Minute))))
+	// only just expired
+	assert.ErrorIs(t, signer.Verify(sign(time.Now().Add(-2*time.Second))), ErrExpired)
+	// long expired
+	assert.ErrorIs(t, signer.Verify(sign(time.Now().Add(-time.Hour))), ErrExpired)
+	// beyond horizon
+	assert.NoError(t, signer.Verify(sign(time.Now().Add(2*time.Hour))))
+	// invalid signature is not observed
+	assert.ErrorIs(t, New([]byte("def456"), WithDriftDetector(detector)).Verify(sign(time.Now())), ErrInvalidSignature)
+
+	assert.Equal(t, DriftStats{
+		Verifications:   4,
+		RecentlyExpired: 1,
+		FarFuture:       1,
+	}, detector.Stats())
+
+	require.Len(t, reported, 2)
+	assert.Less(t, reported[0].Offset, time.Duration(0))
+	assert.Greater(t, reported[1].Offset, time.Hour)
+}
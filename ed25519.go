@@ -0,0 +1,61 @@
+package surl
+
+import (
+	"crypto/ed25519"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NewEd25519 constructs a signer that signs URLs with an Ed25519 private key.
+// Unlike a signer constructed with New, the URLs it signs can be verified by
+// a Verifier holding only the public key, permitting URLs to be signed in a
+// trusted backend and verified in services that cannot be trusted with the
+// secret.
+//
+// The signer holds no symmetric key, and so it cannot be scoped, nor used with
+// options that derive keys from it, e.g. WithPurpose.
+func NewEd25519(key ed25519.PrivateKey, opts ...Option) *Signer {
+	alg := &publicKeyAlgorithm{name: "ed25519", signer: key, public: key.Public()}
+	return newSigner(nil, alg, opts...)
+}
+
+// Verifier verifies signed URLs but cannot sign them.
+type Verifier struct {
+	signer *Signer
+}
+
+// NewEd25519Verifier constructs a verifier of URLs signed by a signer
+// constructed with NewEd25519. The options must match those of the signer.
+func NewEd25519Verifier(key ed25519.PublicKey, opts ...Option) *Verifier {
+	alg := &publicKeyAlgorithm{name: "ed25519", public: key}
+	return &Verifier{signer: newSigner(nil, alg, opts...)}
+}
+
+// Verify verifies a signed URL, validating its signature and ensuring it is
+// unexpired.
+func (v *Verifier) Verify(signed string) error {
+	return v.signer.Verify(signed)
+}
+
+// VerifyURL is like Verify but operates on a parsed URL. The URL is not
+// modified.
+func (v *Verifier) VerifyURL(signed *url.URL) error {
+	return v.signer.VerifyURL(signed)
+}
+
+// VerifyAt verifies a signed URL as if at the given time.
+func (v *Verifier) VerifyAt(signed string, t time.Time) error {
+	return v.signer.VerifyAt(signed, t)
+}
+
+// VerifySignatureAt is like VerifyAt but skips revocation, override auditing
+// and drift detection. See Signer.VerifySignatureAt.
+func (v *Verifier) VerifySignatureAt(signed string, t time.Time) error {
+	return v.signer.VerifySignatureAt(signed, t)
+}
+
+// VerifyRequest verifies the URL of a server request.
+func (v *Verifier) VerifyRequest(r *http.Request) error {
+	return v.signer.VerifyRequest(r)
+}
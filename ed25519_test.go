@@ -0,0 +1,78 @@
+package surl
+
+import (
+	"crypto/ed25519"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEd25519(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	for _, f := range formatters {
+		for _, opt := range opts {
+			options := append(opt.options, f.formatter)
+			signer := NewEd25519(private, options...)
+			verifier := NewEd25519Verifier(public, options...)
+
+			t.Run(path.Join(f.name, opt.name), func(t *testing.T) {
+				signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+				require.NoError(t, err)
+
+				assert.NoError(t, verifier.Verify(signed))
+				assert.NoError(t, signer.Verify(signed))
+			})
+		}
+	}
+
+	signer := NewEd25519(private)
+	verifier := NewEd25519Verifier(public)
+
+	t.Run("tampered", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = verifier.Verify(signed[:len("https://example.com/a/b/")] + "d" + signed[len("https://example.com/a/b/c"):])
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		otherPublic, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, NewEd25519Verifier(otherPublic).Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, verifier.Verify(signed), ErrExpired)
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		_, err := signer.Scoped("/a").Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrNoKey)
+	})
+
+	t.Run("purpose", func(t *testing.T) {
+		_, err := signer.With(WithPurpose("invite")).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrNoKey)
+
+		_, err = NewEd25519(private, WithEncryptedData()).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrNoKey)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		assert.Equal(t, "ed25519", signer.Config().Algorithm)
+		assert.Equal(t, signer.Config().KeyFingerprint, verifier.signer.Config().KeyFingerprint)
+	})
+}
@@ -0,0 +1,65 @@
+package surl
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// WithEncryptedData instructs Signer to encrypt the data embedded in the URLs
+// it signs, with SignWithData or SignClaims, so that it is confidential as
+// well as authenticated. The data is encrypted with XChaCha20-Poly1305, using
+// a key derived from the signer's key, and the signer verifying the URLs must
+// also be configured with WithEncryptedData. Data encrypted with a fallback
+// key is decrypted with that key. Signers without a symmetric key fail with
+// ErrNoKey.
+func WithEncryptedData() Option {
+	return func(s *Signer) {
+		if s.key == nil {
+			s.fail(fmt.Errorf("%w: WithEncryptedData", ErrNoKey))
+			return
+		}
+		s.encryptData = true
+	}
+}
+
+// encryptData encrypts the data with a key derived from the parent key,
+// returning the nonce followed by the ciphertext.
+func encryptData(parent, data []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(deriveKey(parent, "surl data encryption"))
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// decryptData decrypts data encrypted by encryptData, trying the signer's key
+// followed by its fallback keys.
+func (s *Signer) decryptData(ciphertext []byte) ([]byte, error) {
+	keys := [][]byte{s.key}
+	for _, fb := range s.fallbacks {
+		keys = append(keys, fb.key)
+	}
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		aead, err := chacha20poly1305.NewX(deriveKey(key, "surl data encryption"))
+		if err != nil {
+			return nil, err
+		}
+		if len(ciphertext) < aead.NonceSize() {
+			break
+		}
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+		if data, err := aead.Open(nil, nonce, sealed, nil); err == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: cannot decrypt data", ErrInvalidFormat)
+}
@@ -0,0 +1,79 @@
+package surl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEncryptedData(t *testing.T) {
+	signer := New([]byte("abc123"), WithEncryptedData())
+	data := map[string]string{"user": "bob"}
+	expiry := time.Now().Add(time.Minute)
+
+	signed, err := signer.SignWithData("https://example.com/a/b/c", expiry, data)
+	require.NoError(t, err)
+	assert.NotContains(t, signed, "bob")
+
+	got, err := signer.VerifyData(signed)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	t.Run("distinct ciphertexts", func(t *testing.T) {
+		again, err := signer.SignWithData("https://example.com/a/b/c", expiry, data)
+		require.NoError(t, err)
+		assert.NotEqual(t, signed, again)
+	})
+
+	t.Run("claims", func(t *testing.T) {
+		want := testClaims{UserID: "bob", FileID: 42}
+		signed, err := SignClaims(signer, "https://example.com/a/b/c", expiry, want)
+		require.NoError(t, err)
+
+		got, err := VerifyClaims[testClaims](signer, signed)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("plaintext verifier", func(t *testing.T) {
+		_, err := New([]byte("abc123")).VerifyData(signed)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("plaintext data", func(t *testing.T) {
+		plain, err := New([]byte("abc123")).SignWithData("https://example.com/a/b/c", expiry, data)
+		require.NoError(t, err)
+
+		_, err = signer.VerifyData(plain)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("fallback key", func(t *testing.T) {
+		rotated := New([]byte("xyz789"), WithFallbackKeys([]byte("abc123")), WithEncryptedData())
+
+		got, err := rotated.VerifyData(signed)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(dataParam, strings.Repeat("A", len(q.Get(dataParam))))
+		u.RawQuery = q.Encode()
+
+		_, err = signer.VerifyData(u.String())
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		signer := NewFromSignFunc(func(data []byte) ([]byte, error) { return data, nil }, WithEncryptedData())
+		_, err := signer.SignWithData("https://example.com/a/b/c", time.Now().Add(time.Minute), map[string]string{"x": "y"})
+		assert.ErrorIs(t, err, ErrNoKey)
+	})
+}
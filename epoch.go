@@ -0,0 +1,37 @@
+package surl
+
+import "time"
+
+// WithEpoch instructs Signer to encode expiries as the number of seconds since
+// the given epoch rather than since the Unix epoch, e.g. 2024-01-01, yielding
+// shorter URLs, particularly with WithBase58Expiry. Times before the epoch
+// cannot be encoded compactly, so choose an epoch no later than the earliest
+// expiry. The signer verifying URLs must be configured with the same epoch.
+func WithEpoch(epoch time.Time) Option {
+	return func(s *Signer) {
+		s.epoch = epoch.Unix()
+	}
+}
+
+// encodeTime encodes a time, such as an expiry, relative to the epoch.
+func (s *Signer) encodeTime(t time.Time) string {
+	return s.Encode(t.Unix() - s.epochOffset())
+}
+
+// decodeTime decodes a time encoded by encodeTime.
+func (s *Signer) decodeTime(encoded string) (time.Time, error) {
+	i, err := s.Decode(encoded)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(i+s.epochOffset(), 0), nil
+}
+
+// epochOffset returns the number of seconds between the Unix epoch and the
+// epoch, which is ignored by timestamps.
+func (s *Signer) epochOffset() int64 {
+	if _, ok := s.ExpiryEncoding.(timestampEncoding); ok {
+		return 0
+	}
+	return s.epoch
+}
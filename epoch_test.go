@@ -0,0 +1,46 @@
+package surl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEpoch(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer := New([]byte("abc123"), WithEpoch(epoch), WithBase58Expiry())
+	expiry := time.Now().Add(time.Hour)
+
+	signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(signed))
+
+	unixSigned, err := New([]byte("abc123"), WithBase58Expiry()).Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+	assert.Less(t, len(signed), len(unixSigned))
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+
+	t.Run("different epoch", func(t *testing.T) {
+		other := New([]byte("abc123"), WithEpoch(epoch.AddDate(-1, 0, 0)), WithBase58Expiry())
+		assert.Error(t, other.Verify(signed))
+	})
+
+	t.Run("window", func(t *testing.T) {
+		signed, err := signer.SignWindow("https://example.com/a/b/c", time.Now().Add(time.Hour), expiry.Add(time.Hour))
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrNotYetValid)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		assert.Equal(t, epoch, signer.Config().Epoch)
+		assert.True(t, strings.Contains(signer.Config().String(), "epoch=2024-01-01T00:00:00Z"))
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+func main() {
+	signer := surl.New([]byte("secret_key"), surl.WithPathFormatter(), surl.PrefixPath("/signed"))
+
+	// Only requests with valid signed URLs are routed. The signature and expiry
+	// are removed from the path before routing, so patterns match the path as
+	// it was before it was signed.
+	mux := surl.NewServeMux(signer)
+	mux.HandleFunc("GET /signed/downloads/{file...}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "downloading %s", r.PathValue("file"))
+	})
+
+	// Create a signed URL that expires in one hour.
+	signed, _ := signer.Sign("https://example.com/downloads/reports/q1.pdf", time.Now().Add(time.Hour))
+	fmt.Println(signed)
+	// Outputs something like:
+	// https://example.com/signed/PaMIbZQ6wxPdHXVLfIGwZBULo-FSTdt7-bCLZjBPPUE.1669574162/downloads/reports/q1.pdf
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+	fmt.Println(w.Body.String())
+	// Outputs:
+	// downloading reports/q1.pdf
+}
@@ -0,0 +1,57 @@
+package surl
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// ExpiredError is returned when a signed URL, or token, with a valid signature
+// has expired. It matches ErrExpired with errors.Is, and carries the time at
+// which it expired, e.g. so that handlers can tell users the link expired
+// three days ago.
+type ExpiredError struct {
+	// ExpiredAt is the time at which the signed URL expired.
+	ExpiredAt time.Time
+}
+
+// Error returns the message of ErrExpired.
+func (e *ExpiredError) Error() string {
+	return ErrExpired.Error()
+}
+
+// Is reports whether the target is ErrExpired.
+func (e *ExpiredError) Is(target error) bool {
+	return target == ErrExpired
+}
+
+// ExpiredHandler responds to a request with a signed URL that has a valid
+// signature but has expired. The result carries the original URL, which can
+// be trusted because the signature is valid, e.g. to offer to renew the link.
+type ExpiredHandler func(w http.ResponseWriter, r *http.Request, result *Result)
+
+// DefaultExpiredPage is the template rendered by ExpiredPage when it is given
+// a nil template.
+var DefaultExpiredPage = template.Must(template.New("expired").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Link expired</title></head>
+<body>
+<h1>Link expired</h1>
+<p>This link expired at {{ .ExpiresAt.UTC.Format "2006-01-02 15:04:05 MST" }}.</p>
+</body>
+</html>
+`))
+
+// ExpiredPage returns an ExpiredHandler that renders a page using the template,
+// with a 410 Gone status. The template is executed with the *Result. If tmpl is
+// nil then DefaultExpiredPage is used.
+func ExpiredPage(tmpl *template.Template) ExpiredHandler {
+	if tmpl == nil {
+		tmpl = DefaultExpiredPage
+	}
+	return func(w http.ResponseWriter, r *http.Request, result *Result) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusGone)
+		tmpl.Execute(w, result)
+	}
+}
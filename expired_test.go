@@ -0,0 +1,52 @@
+package surl
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredPage(t *testing.T) {
+	signer := New([]byte("abc123"))
+	tmpl := template.Must(template.New("").Parse(`{{ .OriginalURL.Path }} has expired`))
+
+	mux := NewServeMux(signer)
+	mux.Expired = ExpiredPage(tmpl)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Equal(t, "/a/b/c has expired", w.Body.String())
+	})
+
+	t.Run("expired and tampered", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", strings.Replace(signed, "/a/b/c", "/x/y/z", 1), nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.NotContains(t, w.Body.String(), "/x/y/z has expired")
+	})
+
+	t.Run("default page", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		ExpiredPage(nil)(w, httptest.NewRequest("GET", signed, nil), &Result{ExpiresAt: time.Unix(0, 0)})
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Contains(t, w.Body.String(), "This link expired at 1970-01-01 00:00:00 UTC.")
+	})
+}
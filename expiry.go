@@ -0,0 +1,32 @@
+package surl
+
+import "net/url"
+
+// WithNoExpiry permits signing URLs that never expire, e.g. unsubscribe links,
+// by passing the zero time as the expiry, in which case the expiry is omitted
+// from the URL entirely. It also permits verifying such URLs, which are
+// otherwise rejected, as are URLs signed with the zero time by signers without
+// the option, which expired long ago. The absence of an expiry is covered by
+// the signature, so that an expiring URL cannot be made permanent.
+//
+// A permanent URL can only be invalidated by changing the key, or revoking it
+// with WithRevocationChecker. Stores of single-use nonces, use counts and
+// revocations retain the entries of permanent URLs indefinitely.
+func WithNoExpiry() Option {
+	return func(s *Signer) {
+		s.noExpiry = true
+	}
+}
+
+// hasExpiry reports whether a URL, from which the signature has been
+// extracted, has an expiry.
+func (s *Signer) hasExpiry(u url.URL) bool {
+	expiry, err := s.extractExpiry(&u)
+	return err == nil && expiry != ""
+}
+
+// noExpiryBinding binds the absence of an expiry, along with any other
+// binding, to a signature.
+func noExpiryBinding(binding string) string {
+	return "noexpiry:" + binding
+}
@@ -0,0 +1,107 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithNoExpiry(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"query formatter", nil},
+		{"path formatter", []Option{WithPathFormatter()}},
+		{"prefix", []Option{PrefixPath("/signed")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), append(tt.opts, WithNoExpiry())...)
+
+			signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Time{})
+			require.NoError(t, err)
+			assert.NotContains(t, signed, "expiry=")
+
+			assert.NoError(t, signer.Verify(signed))
+			assert.NoError(t, signer.VerifyAt(signed, time.Now().AddDate(100, 0, 0)))
+
+			// expiring URLs are still verified
+			expiring, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			assert.NoError(t, signer.Verify(expiring))
+
+			// not verified without the option
+			assert.Error(t, New([]byte("abc123"), tt.opts...).Verify(signed))
+		})
+	}
+
+	signer := New([]byte("abc123"), WithNoExpiry())
+
+	t.Run("result", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Time{})
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+
+		result, err := signer.verifyURL(context.Background(), u, "")
+		require.NoError(t, err)
+		assert.True(t, result.ExpiresAt.IsZero())
+	})
+
+	t.Run("expiry removed", func(t *testing.T) {
+		// an expiring URL cannot be made permanent
+		for _, opts := range [][]Option{nil, {WithPathFormatter()}} {
+			signer := New([]byte("abc123"), append(opts, WithNoExpiry())...)
+			expiring, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+			require.NoError(t, err)
+
+			u, err := url.Parse(expiring)
+			require.NoError(t, err)
+			if opts == nil {
+				q := u.Query()
+				q.Del("expiry")
+				u.RawQuery = q.Encode()
+			} else {
+				sig, rest, _ := strings.Cut(u.Path, ".")
+				_, rest, _ = strings.Cut(rest, "/")
+				u.Path = sig + "./" + rest
+			}
+			assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+		}
+	})
+
+	t.Run("zero time without option", func(t *testing.T) {
+		signer := New([]byte("abc123"))
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Time{})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+
+	t.Run("subtree", func(t *testing.T) {
+		signed, err := signer.SignSubtree("https://example.com/videos/", time.Time{})
+		require.NoError(t, err)
+		segment, err := SubtreeURL(signed, "/videos/a.ts")
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(segment))
+	})
+
+	t.Run("single use", func(t *testing.T) {
+		now := time.Now()
+		store := &MemoryNonceStore{now: func() time.Time { return now }}
+		signer := New([]byte("abc123"), WithNoExpiry(), WithNonceStore(store))
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Time{})
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		now = now.AddDate(1, 0, 0)
+		assert.ErrorIs(t, signer.Verify(signed), ErrUsed)
+	})
+}
@@ -0,0 +1,106 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Explanation reports how the verification of a signed URL proceeded, and at
+// which step it failed, if any. See Signer.Explain.
+type Explanation struct {
+	// URL is the signed URL.
+	URL string
+	// Step is the step at which verification failed: parse, prefix, format,
+	// expiry, signature, scope, expired or not-before; or empty if the URL is
+	// valid.
+	Step string
+	// Err is the error with which verification failed, or nil if the URL is
+	// valid.
+	Err error
+	// Parsed is the metadata of the signed URL, or nil if it could not be
+	// parsed.
+	Parsed *SignedURL
+	// Payload is the payload, as computed by the signer, whose signature is
+	// compared with that of the URL, or empty if verification failed before
+	// it was computed. A signature mismatch between services is usually
+	// explained by comparing the payloads computed by each.
+	Payload string
+}
+
+// String returns a multi-line report of the explanation.
+func (e *Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "url:      %s\n", e.URL)
+	if e.Err == nil {
+		fmt.Fprintf(&b, "result:   valid\n")
+	} else {
+		fmt.Fprintf(&b, "result:   %s failed: %s\n", e.Step, e.Err)
+	}
+	if e.Parsed != nil {
+		fmt.Fprintf(&b, "unsigned: %s\n", e.Parsed.URL)
+		if !e.Parsed.Expiry.IsZero() {
+			fmt.Fprintf(&b, "expiry:   %s\n", e.Parsed.Expiry.UTC().Format(time.RFC3339))
+		}
+	}
+	if e.Payload != "" {
+		fmt.Fprintf(&b, "payload:  %q\n", e.Payload)
+	}
+	return b.String()
+}
+
+// Explain verifies a signed URL, as with Verify, but reports exactly which step
+// failed, e.g. a prefix mismatch, an unparsable expiry, or a signature
+// mismatch along with the payload whose signature was computed, to make
+// debugging integrations between services tractable. It checks only the
+// format, signature, scope and validity period of the URL; it does not consult
+// revocation checkers, nonce stores or use counters, nor does it verify URLs
+// signed with a binding. The explanation may reveal the payload, so it should
+// not be shown to untrusted users.
+func (s *Signer) Explain(signed string) *Explanation {
+	e := &Explanation{URL: signed}
+	fail := func(step string, err error) *Explanation {
+		e.Step, e.Err = step, err
+		return e
+	}
+
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return fail("parse", err)
+	}
+	if _, ok := s.matchPrefix(u.Path); !ok {
+		return fail("prefix", fmt.Errorf("%w: path %s does not begin with prefix %s", ErrInvalidFormat, u.Path, s.prefix))
+	}
+	if e.Parsed, err = s.parse(signed); err != nil {
+		if errors.Is(err, errInvalidExpiry) {
+			return fail("expiry", err)
+		}
+		return fail("format", err)
+	}
+
+	explained := *s
+	explained.explanation = e
+	result, err := explained.verifySignature(context.Background(), u, "")
+	switch {
+	case errors.Is(err, ErrOutOfScope):
+		return fail("scope", err)
+	case errors.Is(err, ErrInvalidSignature):
+		return fail("signature", err)
+	case errors.Is(err, errInvalidExpiry):
+		return fail("expiry", err)
+	case err != nil:
+		return fail("format", err)
+	}
+
+	now := time.Now()
+	if !result.permanent && now.After(result.ExpiresAt) {
+		return fail("expired", &ExpiredError{ExpiredAt: result.ExpiresAt})
+	}
+	if now.Before(result.NotBefore) {
+		return fail("not-before", ErrNotYetValid)
+	}
+	return e
+}
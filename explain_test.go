@@ -0,0 +1,95 @@
+package surl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Explain(t *testing.T) {
+	signer := New([]byte("abc123"), PrefixPath("/signed"))
+	valid, err := signer.SignFor("https://example.com/a/b/c?foo=bar", time.Hour)
+	require.NoError(t, err)
+	expired, err := signer.SignFor("https://example.com/a/b/c?foo=bar", -time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		signer *Signer
+		signed string
+		step   string
+		want   error
+	}{
+		{
+			name:   "valid",
+			signed: valid,
+		},
+		{
+			name:   "unparsable",
+			signed: "a/b/c",
+			step:   "parse",
+		},
+		{
+			name:   "prefix mismatch",
+			signer: New([]byte("abc123"), PrefixPath("/other")),
+			signed: valid,
+			step:   "prefix",
+			want:   ErrInvalidFormat,
+		},
+		{
+			name:   "missing signature",
+			signed: "https://example.com/signed/a/b/c",
+			step:   "format",
+			want:   ErrInvalidFormat,
+		},
+		{
+			name:   "unparsable expiry",
+			signed: strings.Replace(valid, "expiry=", "expiry=x", 1),
+			step:   "expiry",
+			want:   ErrInvalidFormat,
+		},
+		{
+			name:   "signature mismatch",
+			signer: New([]byte("xyz789"), PrefixPath("/signed")),
+			signed: valid,
+			step:   "signature",
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "expired",
+			signed: expired,
+			step:   "expired",
+			want:   ErrExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := signer
+			if tt.signer != nil {
+				s = tt.signer
+			}
+			e := s.Explain(tt.signed)
+			assert.Equal(t, tt.step, e.Step)
+			if tt.step == "" {
+				assert.NoError(t, e.Err)
+			} else {
+				assert.Error(t, e.Err)
+			}
+			if tt.want != nil {
+				assert.ErrorIs(t, e.Err, tt.want)
+			}
+		})
+	}
+
+	t.Run("payload", func(t *testing.T) {
+		e := New([]byte("xyz789"), PrefixPath("/signed")).Explain(valid)
+		require.NotNil(t, e.Parsed)
+		assert.Equal(t, "https://example.com/a/b/c?foo=bar", e.Parsed.URL.String())
+		assert.True(t, strings.HasPrefix(e.Payload, "https://example.com/a/b/c?foo=bar&expiry="), e.Payload)
+		assert.Contains(t, e.String(), "result:   signature failed: invalid signature")
+		assert.Contains(t, e.String(), "payload:  ")
+	})
+}
@@ -0,0 +1,75 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// ErrOverrideNotRenewable is returned when extending a URL signed with the
+// override key, which would otherwise be re-signed with the signer's key,
+// erasing its provenance and escaping the auditing of its later uses.
+var ErrOverrideNotRenewable = errors.New("URL signed with the override key cannot be renewed")
+
+// Extend verifies a signed URL and returns a fresh signed URL with the new
+// expiry, preserving its other components, e.g. its data, methods and not
+// before time. Links can therefore be renewed without reconstructing the
+// original unsigned URL. The URL must be valid and unexpired, but, unlike
+// Verify, extending a URL neither consumes its nonce nor counts a use.
+//
+// The renewed URL is given a new ID, so uses and nonces counted against the
+// original do not carry over to it, and revoking the original does not revoke
+// it. URLs signed with a binding, e.g. with SignForClient or SignWithBinding,
+// cannot be extended, because the binding is not recoverable from the URL.
+// Nor can URLs signed with the override key; see WithOverrideKey.
+func (s *Signer) Extend(signed string, expiry time.Time) (string, error) {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return "", err
+	}
+	scope := u.Query().Get(scopeParam)
+
+	ctx := context.Background()
+	result, err := s.verifyURLAt(ctx, u, "", time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	renewed, err := s.renew(ctx, result, scope, expiry)
+	if err != nil {
+		return "", err
+	}
+	return renewed.String(), nil
+}
+
+// renew signs a verified URL afresh with the new expiry, preserving its other
+// components. The scope is that of the URL, if any.
+func (s *Signer) renew(ctx context.Context, result *Result, scope string, expiry time.Time) (*url.URL, error) {
+	if result.Override {
+		return nil, ErrOverrideNotRenewable
+	}
+	renewed := *s
+	if scope != "" && s.scope == "" {
+		renewed = *s.Scoped(scope)
+	}
+	renewed.maxUses = result.MaxUses
+	renewed.data = result.Data
+	renewed.methods = result.Methods
+	renewed.upload = result.Upload
+	renewed.digest = result.Digest
+	renewed.headers = result.Headers
+	renewed.notBefore = result.NotBefore
+
+	unsigned := *result.OriginalURL
+	if result.Subtree != "" {
+		// sign the root of the subtree rather than the path beneath it
+		renewed.subtree = true
+		unsigned.Path = result.Subtree
+		unsigned.RawPath = ""
+	}
+	if err := renewed.signURL(ctx, &unsigned, expiry, ""); err != nil {
+		return nil, err
+	}
+	return &unsigned, nil
+}
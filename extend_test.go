@@ -0,0 +1,106 @@
+package surl
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Extend(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignFor("https://example.com/a/b/c?foo=bar", time.Minute)
+	require.NoError(t, err)
+
+	extended, err := signer.Extend(signed, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.NoError(t, signer.VerifyAt(extended, time.Now().Add(59*time.Minute)))
+	assert.ErrorIs(t, signer.VerifyAt(signed, time.Now().Add(59*time.Minute)), ErrExpired)
+
+	u, err := url.Parse(extended)
+	require.NoError(t, err)
+	result, err := signer.verifyURL(context.Background(), u, "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a/b/c?foo=bar", result.OriginalURL.String())
+
+	t.Run("preserves components", func(t *testing.T) {
+		signed, err := signer.SignForMethods("https://example.com/a/b/c", time.Now().Add(time.Minute), http.MethodPut)
+		require.NoError(t, err)
+
+		extended, err := signer.Extend(signed, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		u, err := url.Parse(extended)
+		require.NoError(t, err)
+		result, err := signer.verifyURL(context.Background(), u, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{http.MethodPut}, result.Methods)
+	})
+
+	t.Run("data", func(t *testing.T) {
+		signed, err := signer.SignWithData("https://example.com/a/b/c", time.Now().Add(time.Minute), map[string]string{"user": "bob"})
+		require.NoError(t, err)
+
+		extended, err := signer.Extend(signed, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		data, err := signer.VerifyData(extended)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"user": "bob"}, data)
+	})
+
+	t.Run("subtree", func(t *testing.T) {
+		signed, err := signer.SignSubtree("https://example.com/files/", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		target, err := SubtreeURL(signed, "/files/report.pdf")
+		require.NoError(t, err)
+
+		extended, err := signer.Extend(target, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		target, err = SubtreeURL(extended, "/files/other.pdf")
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(target))
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		signed, err := signer.Scoped("/tenants/1").SignFor("https://example.com/tenants/1/a", time.Minute)
+		require.NoError(t, err)
+
+		extended, err := signer.Extend(signed, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Contains(t, extended, scopeParam)
+		assert.NoError(t, signer.Scoped("/tenants/1").Verify(extended))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.Extend(signed, time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		signed, err := New([]byte("xyz789")).SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		_, err = signer.Extend(signed, time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("does not consume nonce", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNonceStore(&MemoryNonceStore{}))
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		_, err = signer.Extend(signed, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+	})
+}
@@ -0,0 +1,14 @@
+package surl
+
+import "net/http"
+
+// FileServer returns a handler that serves files from root only for requests
+// with valid, unexpired, signed URLs, e.g. signed URLs for downloads. Requests
+// that fail verification receive a 403 Forbidden response, or a 410 Gone
+// response if the URL has expired, with the reason in the body. Files are
+// looked up using the path of the URL as it was before it was signed, without
+// the prefix. Options configure the verifying middleware.
+func (s *Signer) FileServer(root http.FileSystem, opts ...MiddlewareOption) http.Handler {
+	files := http.StripPrefix(s.prefix, http.FileServer(root))
+	return s.Middleware(files, append([]MiddlewareOption{StripSignature()}, opts...)...)
+}
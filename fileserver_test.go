@@ -0,0 +1,78 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileServer(t *testing.T) {
+	root := http.FS(fstest.MapFS{
+		"reports/2024.csv": {Data: []byte("a,b,c")},
+	})
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), f.formatter, PrefixPath("/downloads"))
+			handler := signer.FileServer(root)
+
+			tests := []struct {
+				name     string
+				unsigned string
+				expiry   time.Time
+				tamper   func(string) string
+				want     int
+				body     string
+			}{
+				{
+					name:     "valid",
+					unsigned: "https://example.com/reports/2024.csv",
+					expiry:   time.Now().Add(time.Minute),
+					want:     http.StatusOK,
+					body:     "a,b,c",
+				},
+				{
+					name:     "not found",
+					unsigned: "https://example.com/reports/2025.csv",
+					expiry:   time.Now().Add(time.Minute),
+					want:     http.StatusNotFound,
+				},
+				{
+					name:     "expired",
+					unsigned: "https://example.com/reports/2024.csv",
+					expiry:   time.Now().Add(-time.Minute),
+					want:     http.StatusGone,
+					body:     ErrExpired.Error(),
+				},
+				{
+					name:     "tampered",
+					unsigned: "https://example.com/reports/2024.csv",
+					expiry:   time.Now().Add(time.Minute),
+					tamper: func(signed string) string {
+						return signed + "x"
+					},
+					want: http.StatusForbidden,
+				},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					signed, err := signer.Sign(tt.unsigned, tt.expiry)
+					require.NoError(t, err)
+					if tt.tamper != nil {
+						signed = tt.tamper(signed)
+					}
+
+					w := httptest.NewRecorder()
+					handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+
+					assert.Equal(t, tt.want, w.Code)
+					assert.Contains(t, w.Body.String(), tt.body)
+				})
+			}
+		})
+	}
+}
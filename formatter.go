@@ -23,4 +23,5 @@ type formatter interface {
 type payloadOptions struct {
 	skipQuery  bool
 	skipScheme bool
+	skipHost   bool
 }
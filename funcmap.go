@@ -0,0 +1,29 @@
+package surl
+
+import (
+	"html/template"
+	"time"
+)
+
+// FuncMap returns functions for signing URLs in templates:
+//
+//   - sign signs a URL that expires after a duration parsed by
+//     time.ParseDuration, e.g. {{ sign "/download/report.pdf" "1h" }}
+//   - signUntil signs a URL that expires at a time, e.g.
+//     {{ signUntil "/download/report.pdf" .ExpiresAt }}
+//
+// The functions may be used with both html/template and text/template.
+func (s *Signer) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"sign": func(unsigned, ttl string) (string, error) {
+			d, err := time.ParseDuration(ttl)
+			if err != nil {
+				return "", err
+			}
+			return s.SignFor(unsigned, d)
+		},
+		"signUntil": func(unsigned string, expiry time.Time) (string, error) {
+			return s.Sign(unsigned, expiry)
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package surl
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncMap(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	tmpl := template.Must(template.New("").Funcs(signer.FuncMap()).Parse(
+		`<a href="{{ sign "/download/report.pdf" "1h" }}">report</a>` +
+			`<a href="{{ signUntil "/download/invoice.pdf" .ExpiresAt }}">invoice</a>`,
+	))
+	var b strings.Builder
+	err := tmpl.Execute(&b, struct{ ExpiresAt time.Time }{time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	links := regexp.MustCompile(`href="([^"]+)"`).FindAllStringSubmatch(b.String(), -1)
+	require.Len(t, links, 2)
+	for _, link := range links {
+		signed := strings.ReplaceAll(link[1], "&amp;", "&")
+		assert.NoError(t, signer.Verify(signed))
+	}
+
+	t.Run("invalid duration", func(t *testing.T) {
+		tmpl := template.Must(template.New("").Funcs(signer.FuncMap()).Parse(`{{ sign "/a" "forever" }}`))
+		assert.Error(t, tmpl.Execute(&strings.Builder{}, nil))
+	})
+}
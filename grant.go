@@ -0,0 +1,82 @@
+package surl
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+)
+
+// grantPurpose distinguishes grant tokens from other tokens.
+const grantPurpose = "grant"
+
+// ErrNotGranted is returned when a path is not matched by a grant.
+var ErrNotGranted = errors.New("path not granted")
+
+// Grant kinds, prefixed to the pattern in a grant token.
+const (
+	globGrant   = "g:"
+	regexpGrant = "r:"
+)
+
+// SignGlobGrant signs a grant to access any path matching the glob pattern,
+// using the syntax of path.Match, e.g. /users/*/reports/*.pdf. The pattern is
+// not encrypted.
+func (s *Signer) SignGlobGrant(pattern string, expiry time.Time) (string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid glob pattern: %w", err)
+	}
+	return s.signToken(grantPurpose, []byte(globGrant+pattern), expiry)
+}
+
+// SignRegexpGrant signs a grant to access any path matching the regular
+// expression, e.g. /users/\d+/reports/\d+\.pdf. The expression is anchored at
+// both ends, so it must match the whole path. The expression is not encrypted.
+func (s *Signer) SignRegexpGrant(expr string, expiry time.Time) (string, error) {
+	if _, err := compileAnchored(expr); err != nil {
+		return "", fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return s.signToken(grantPurpose, []byte(regexpGrant+expr), expiry)
+}
+
+// VerifyGrant verifies a grant token produced by SignGlobGrant or
+// SignRegexpGrant, and checks the grant matches the path. Paths with . or ..
+// segments are never granted, lest they escape the pattern once cleaned.
+func (s *Signer) VerifyGrant(token, p string) error {
+	data, err := s.verifyToken(grantPurpose, token)
+	if err != nil {
+		return err
+	}
+	if hasDotSegment(p) {
+		return fmt.Errorf("%w: %s", ErrNotGranted, p)
+	}
+	kind, pattern := string(data[:min(2, len(data))]), string(data[min(2, len(data)):])
+
+	var matched bool
+	switch kind {
+	case globGrant:
+		matched, err = path.Match(pattern, p)
+	case regexpGrant:
+		var re *regexp.Regexp
+		re, err = compileAnchored(pattern)
+		if err == nil {
+			matched = re.MatchString(p)
+		}
+	default:
+		return fmt.Errorf("%w: unknown grant: %s", ErrInvalidFormat, data)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	if !matched {
+		return fmt.Errorf("%w: %s", ErrNotGranted, p)
+	}
+	return nil
+}
+
+// compileAnchored compiles the regular expression such that it must match the
+// whole of a string.
+func compileAnchored(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
@@ -0,0 +1,68 @@
+package surl
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrant(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	t.Run("glob", func(t *testing.T) {
+		token, err := signer.SignGlobGrant("/users/*/reports/*.pdf", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.VerifyGrant(token, "/users/alice/reports/2024.pdf"))
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/alice/reports/2024.csv"), ErrNotGranted)
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/alice/private/reports/2024.pdf"), ErrNotGranted)
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/../reports/x.pdf"), ErrNotGranted)
+	})
+
+	t.Run("regexp", func(t *testing.T) {
+		token, err := signer.SignRegexpGrant(`/users/\d+/reports/\d+\.pdf`, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.VerifyGrant(token, "/users/123/reports/2024.pdf"))
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/bob/reports/2024.pdf"), ErrNotGranted)
+		// expression is anchored
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/evil/users/123/reports/2024.pdf"), ErrNotGranted)
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/123/reports/2024.pdf.exe"), ErrNotGranted)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := signer.SignGlobGrant("/users/[", time.Now().Add(time.Minute))
+		assert.Error(t, err)
+
+		_, err = signer.SignRegexpGrant("/users/(", time.Now().Add(time.Minute))
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := signer.SignGlobGrant("/users/*", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/alice"), ErrExpired)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		token, err := signer.SignGlobGrant("/users/alice", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		// widen the pattern
+		_, rest, _ := strings.Cut(token, ".")
+		tampered := base64.RawURLEncoding.EncodeToString([]byte("g:/users/*")) + "." + rest
+		assert.ErrorIs(t, signer.VerifyGrant(tampered, "/users/bob"), ErrInvalidSignature)
+	})
+
+	t.Run("wrong purpose", func(t *testing.T) {
+		token, err := signer.SignBytes([]byte("g:/users/*"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.VerifyGrant(token, "/users/alice"), ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,28 @@
+package surl
+
+import "time"
+
+// WithExpiryGranularity instructs Signer to round the expiry of the URLs it
+// signs up to a multiple of the given duration since the zero time, e.g. to
+// the next hour. Signing the same URL repeatedly within the same window then
+// yields the same signed URL, so that CDNs and browsers can cache its
+// response, at the cost of URLs living up to the given duration longer than
+// requested. Options that add random IDs to URLs, e.g. WithNonceStore, defeat
+// the purpose. The rounded expiry is subject to WithMaxLifetime.
+func WithExpiryGranularity(d time.Duration) Option {
+	return func(s *Signer) {
+		s.granularity = d
+	}
+}
+
+// roundExpiry rounds the expiry up to the expiry granularity.
+func (s *Signer) roundExpiry(expiry time.Time) time.Time {
+	if s.granularity <= 0 {
+		return expiry
+	}
+	rounded := expiry.Truncate(s.granularity)
+	if rounded.Before(expiry) {
+		rounded = rounded.Add(s.granularity)
+	}
+	return rounded
+}
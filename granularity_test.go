@@ -0,0 +1,39 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExpiryGranularity(t *testing.T) {
+	signer := New([]byte("abc123"), WithExpiryGranularity(time.Hour))
+
+	hour := time.Now().Add(2 * time.Hour).Truncate(time.Hour)
+	first, err := signer.Sign("https://example.com/a/b/c", hour.Add(time.Second))
+	require.NoError(t, err)
+	second, err := signer.Sign("https://example.com/a/b/c", hour.Add(59*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	u, err := url.Parse(first)
+	require.NoError(t, err)
+	result, err := signer.verifyURL(context.Background(), u, "")
+	require.NoError(t, err)
+	assert.Equal(t, hour.Add(time.Hour).Unix(), result.ExpiresAt.Unix())
+
+	t.Run("already rounded", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", hour)
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		result, err := signer.verifyURL(context.Background(), u, "")
+		require.NoError(t, err)
+		assert.Equal(t, hour.Unix(), result.ExpiresAt.Unix())
+	})
+}
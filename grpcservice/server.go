@@ -0,0 +1,119 @@
+// Package grpcservice exposes a Signer over gRPC, so that services on
+// platforms standardised on gRPC can sign and verify URLs compatible with this
+// package. It implements the Signer service defined in surlpb/surl.proto:
+//
+//	s := grpc.NewServer()
+//	surlpb.RegisterSignerServer(s, &grpcservice.Server{Signer: signer, MaxTTL: 24 * time.Hour})
+//
+// The deadline and cancellation of each call are passed to the signer, and
+// so on to any key function or service computing signatures, and a call that
+// runs out of time fails with the DeadlineExceeded code. A call to sign a URL
+// may select a key from the signer's keyring, or one looked up by its key
+// function, by its ID.
+//
+// The server does not authenticate calls, and anyone able to reach it is able
+// to sign URLs. Serve it on an internal address, or add authentication with an
+// interceptor.
+//
+// The package is a separate module, so that only applications using it depend
+// on gRPC.
+package grpcservice
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative surlpb/surl.proto
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/leg100/surl/v2/grpcservice/surlpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the Signer service.
+type Server struct {
+	surlpb.UnimplementedSignerServer
+
+	// Signer signs and verifies URLs.
+	Signer *surl.Signer
+	// MaxTTL, if non-zero, is the maximum lifespan of a signed URL. Calls to
+	// sign URLs expiring later are rejected.
+	MaxTTL time.Duration
+}
+
+// Sign signs a URL, with the key selected by the key ID, if any.
+func (s *Server) Sign(ctx context.Context, req *surlpb.SignRequest) (*surlpb.SignResponse, error) {
+	var expiry time.Time
+	switch {
+	case req.GetTtl() != nil:
+		if err := req.GetTtl().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %s", err)
+		}
+		expiry = time.Now().Add(req.GetTtl().AsDuration())
+	case req.GetExpiry() != nil:
+		if err := req.GetExpiry().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expiry: %s", err)
+		}
+		expiry = req.GetExpiry().AsTime()
+	default:
+		return nil, status.Error(codes.InvalidArgument, "missing ttl or expiry")
+	}
+	if s.MaxTTL > 0 && time.Until(expiry) > s.MaxTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "expiry exceeds maximum ttl of %s", s.MaxTTL)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	var (
+		signed string
+		err    error
+	)
+	if req.GetKeyId() != "" {
+		signed, err = s.Signer.SignWithKeyID(ctx, req.GetKeyId(), req.GetUrl(), expiry)
+	} else {
+		signed, err = s.Signer.SignContext(ctx, req.GetUrl(), expiry)
+	}
+	if err != nil {
+		return nil, signError(ctx, err)
+	}
+	return &surlpb.SignResponse{
+		Url:    signed,
+		Expiry: timestamppb.New(s.Signer.SignedExpiry(expiry).UTC()),
+	}, nil
+}
+
+// Verify verifies a signed URL. An invalid URL is reported in the response
+// rather than as an error, which is reserved for the call itself failing,
+// e.g. because its deadline is exceeded.
+func (s *Server) Verify(ctx context.Context, req *surlpb.VerifyRequest) (*surlpb.VerifyResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if err := s.Signer.VerifyContext(ctx, req.GetUrl()); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return &surlpb.VerifyResponse{Error: err.Error()}, nil
+	}
+	return &surlpb.VerifyResponse{Valid: true}, nil
+}
+
+// signError maps an error signing a URL to a gRPC status error.
+func signError(ctx context.Context, err error) error {
+	var urlErr *url.Error
+	switch {
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	case errors.Is(err, surl.ErrUnknownKey):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &urlErr), errors.Is(err, surl.ErrLifetimeExceeded):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "signing URL: %s", err)
+	}
+}
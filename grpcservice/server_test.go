@@ -0,0 +1,193 @@
+package grpcservice
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/leg100/surl/v2/grpcservice/surlpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newClient serves the server over an in-memory connection and returns a
+// client connected to it.
+func newClient(t *testing.T, srv *Server) surlpb.SignerClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	surlpb.RegisterSignerServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return surlpb.NewSignerClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	signer := surl.New([]byte("abc123"))
+	client := newClient(t, &Server{Signer: signer, MaxTTL: time.Hour})
+	ctx := context.Background()
+
+	t.Run("sign with ttl", func(t *testing.T) {
+		resp, err := client.Sign(ctx, &surlpb.SignRequest{
+			Url: "https://example.com/a/b/c",
+			Ttl: durationpb.New(time.Minute),
+		})
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(resp.Url))
+		assert.WithinDuration(t, time.Now().Add(time.Minute), resp.Expiry.AsTime(), 2*time.Second)
+	})
+
+	t.Run("sign with expiry", func(t *testing.T) {
+		expiry := time.Now().Add(time.Minute).Truncate(time.Second)
+		resp, err := client.Sign(ctx, &surlpb.SignRequest{
+			Url:    "https://example.com/a/b/c",
+			Expiry: timestamppb.New(expiry),
+		})
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(resp.Url))
+		assert.Equal(t, expiry.UTC(), resp.Expiry.AsTime())
+	})
+
+	t.Run("sign with granularity", func(t *testing.T) {
+		signer := surl.New([]byte("abc123"), surl.WithExpiryGranularity(time.Minute), surl.WithMillisecondExpiry())
+		client := newClient(t, &Server{Signer: signer})
+
+		expiry := time.Date(2030, 1, 1, 0, 0, 30, 0, time.UTC)
+		resp, err := client.Sign(ctx, &surlpb.SignRequest{
+			Url:    "https://example.com/a/b/c",
+			Expiry: timestamppb.New(expiry),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expiry.Add(30*time.Second), resp.Expiry.AsTime())
+	})
+
+	t.Run("sign errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			req  *surlpb.SignRequest
+			want codes.Code
+		}{
+			{"missing ttl", &surlpb.SignRequest{Url: "https://example.com/a"}, codes.InvalidArgument},
+			{"exceeds max ttl", &surlpb.SignRequest{Url: "https://example.com/a", Ttl: durationpb.New(2 * time.Hour)}, codes.InvalidArgument},
+			{"invalid url", &surlpb.SignRequest{Url: "not a url", Ttl: durationpb.New(time.Minute)}, codes.InvalidArgument},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := client.Sign(ctx, tt.req)
+				assert.Equal(t, tt.want, status.Code(err), err)
+			})
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		valid, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		expired, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		resp, err := client.Verify(ctx, &surlpb.VerifyRequest{Url: valid})
+		require.NoError(t, err)
+		assert.True(t, resp.Valid)
+		assert.Empty(t, resp.Error)
+
+		resp, err = client.Verify(ctx, &surlpb.VerifyRequest{Url: expired})
+		require.NoError(t, err)
+		assert.False(t, resp.Valid)
+		assert.Equal(t, "URL has expired", resp.Error)
+
+		resp, err = client.Verify(ctx, &surlpb.VerifyRequest{Url: valid + "&foo=bar"})
+		require.NoError(t, err)
+		assert.False(t, resp.Valid)
+		assert.Equal(t, "invalid signature", resp.Error)
+	})
+}
+
+func TestServer_KeyID(t *testing.T) {
+	keyring, err := surl.NewKeyring("a", map[string][]byte{
+		"a": []byte("abc123"),
+		"b": []byte("xyz789"),
+	})
+	require.NoError(t, err)
+	signer := surl.New([]byte("abc123"), surl.WithKeyring(keyring))
+	client := newClient(t, &Server{Signer: signer})
+	ctx := context.Background()
+
+	resp, err := client.Sign(ctx, &surlpb.SignRequest{
+		Url:   "https://example.com/a/b/c",
+		Ttl:   durationpb.New(time.Minute),
+		KeyId: "b",
+	})
+	require.NoError(t, err)
+	u, err := url.Parse(resp.Url)
+	require.NoError(t, err)
+	assert.Equal(t, "b", u.Query().Get("signature_kid"))
+
+	verified, err := client.Verify(ctx, &surlpb.VerifyRequest{Url: resp.Url})
+	require.NoError(t, err)
+	assert.True(t, verified.Valid)
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := client.Sign(ctx, &surlpb.SignRequest{
+			Url:   "https://example.com/a/b/c",
+			Ttl:   durationpb.New(time.Minute),
+			KeyId: "c",
+		})
+		assert.Equal(t, codes.NotFound, status.Code(err), err)
+	})
+}
+
+// blockingMACService blocks until the context is done.
+type blockingMACService struct{}
+
+func (blockingMACService) GenerateMAC(ctx context.Context, data []byte) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingMACService) VerifyMAC(ctx context.Context, data, mac []byte) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestServer_Deadline(t *testing.T) {
+	client := newClient(t, &Server{Signer: surl.NewFromMACService(blockingMACService{})})
+
+	t.Run("sign", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Sign(ctx, &surlpb.SignRequest{
+			Url: "https://example.com/a/b/c",
+			Ttl: durationpb.New(time.Minute),
+		})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err), err)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Verify(ctx, &surlpb.VerifyRequest{
+			Url: "https://example.com/a/b/c?expiry=9999999999&signature=abc",
+		})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err), err)
+	})
+}
@@ -0,0 +1,61 @@
+package surl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+)
+
+// WithHMACSHA256 instructs Signer to compute signatures using HMAC-SHA256
+// rather than the default, keyed BLAKE2b-256. This is useful for
+// interoperating with other services that expect HMAC-SHA256 signatures, or
+// for complying with a policy mandating its use. Keys are not truncated.
+//
+// It has no effect on a signer constructed with NewEd25519.
+func WithHMACSHA256() Option {
+	return withHash(func(key []byte) *keyedHash {
+		return newHMAC("hmac-sha256", sha256.New, key)
+	})
+}
+
+// WithHMACSHA512 instructs Signer to compute signatures using HMAC-SHA512
+// rather than the default, keyed BLAKE2b-256. Signatures are 64 bytes long,
+// twice the length of the default. Keys are not truncated.
+//
+// It has no effect on a signer constructed with NewEd25519.
+func WithHMACSHA512() Option {
+	return withHash(func(key []byte) *keyedHash {
+		return newHMAC("hmac-sha512", sha512.New, key)
+	})
+}
+
+// withHash instructs Signer to construct keyed hashes using the function,
+// replacing those already constructed for the signer's key, fallback keys and
+// override key.
+func withHash(fn func(key []byte) *keyedHash) Option {
+	return func(s *Signer) {
+		s.hash = fn
+		if _, ok := s.alg.(*keyedHash); ok {
+			s.alg = fn(s.key)
+		}
+		if len(s.fallbacks) > 0 {
+			// copy to avoid modifying signers sharing the fallbacks
+			fallbacks := make([]fallbackKey, len(s.fallbacks))
+			for i, fb := range s.fallbacks {
+				fallbacks[i] = fallbackKey{key: fb.key, alg: fn(fb.key)}
+			}
+			s.fallbacks = fallbacks
+		}
+		if s.override != nil {
+			// copy to avoid modifying signers sharing the override
+			o := *s.override
+			o.alg = fn(o.key)
+			s.override = &o
+		}
+	}
+}
+
+func newHMAC(name string, h func() hash.Hash, key []byte) *keyedHash {
+	return &keyedHash{name: name, hash: hmac.New(h, key)}
+}
@@ -0,0 +1,91 @@
+package surl
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	hashes := []struct {
+		name   string
+		option Option
+	}{
+		{"hmac-sha256", WithHMACSHA256()},
+		{"hmac-sha512", WithHMACSHA512()},
+	}
+	for _, h := range hashes {
+		for _, f := range formatters {
+			for _, opt := range opts {
+				options := append(opt.options, f.formatter, h.option)
+				signer := New([]byte("abc123"), options...)
+
+				t.Run(path.Join(h.name, f.name, opt.name), func(t *testing.T) {
+					signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+					require.NoError(t, err)
+
+					assert.NoError(t, signer.Verify(signed))
+					err = New([]byte("abc123"), append(opt.options, f.formatter)...).Verify(signed)
+					if opt.name == "self describing" {
+						// the descriptor selects the hash
+						assert.NoError(t, err)
+					} else {
+						// not verified using the default hash
+						assert.ErrorIs(t, err, ErrInvalidSignature)
+					}
+				})
+			}
+		}
+		t.Run(path.Join(h.name, "config"), func(t *testing.T) {
+			assert.Equal(t, h.name, New([]byte("abc123"), h.option).Config().Algorithm)
+		})
+	}
+
+	t.Run("interoperable", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithHMACSHA256())
+
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Unix(1700000000, 0))
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte("abc123"))
+		mac.Write([]byte("https://example.com/a/b/c?expiry=1700000000"))
+		want := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, want, u.Query().Get("signature"))
+	})
+
+	t.Run("override key", func(t *testing.T) {
+		parent := New([]byte("abc123"), WithOverrideKey([]byte("xyz789"), func(*Result) error { return nil }))
+		signer := parent.With(WithHMACSHA256())
+
+		signed, err := New([]byte("xyz789"), WithHMACSHA256()).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		// parent is unaffected
+		assert.ErrorIs(t, parent.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithHMACSHA256())
+
+		assert.Equal(t, "hmac-sha256", signer.Scoped("/a").Config().Algorithm)
+	})
+
+	t.Run("ed25519 unaffected", func(t *testing.T) {
+		_, private, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "ed25519", NewEd25519(private, WithHMACSHA256()).Config().Algorithm)
+	})
+}
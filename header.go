@@ -0,0 +1,67 @@
+package surl
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// RequestSignatureHeader is the HTTP header carrying the signature of a
+	// request signed with SignRequest.
+	RequestSignatureHeader = "X-Surl-Signature"
+	// RequestExpiresHeader is the HTTP header carrying the expiry of a request
+	// signed with SignRequest.
+	RequestExpiresHeader = "X-Surl-Expires"
+)
+
+// SignRequest signs the URL of an outgoing request, e.g. an API request, as
+// with Sign, but adds the signature and expiry to the X-Surl-Signature and
+// X-Surl-Expires headers rather than to the URL, so that URLs that are logged
+// do not contain them. Any prefix, and any other parameters added when signing,
+// are added to the URL as usual. VerifyRequest, and the handlers and
+// middleware in this package, verify such requests. The expiry is encoded
+// according to the signer's options, and the header is omitted from requests
+// that never expire.
+func (s *Signer) SignRequest(r *http.Request, expiry time.Time) error {
+	q := s.headerSigner()
+	u := *r.URL
+	if err := q.signURL(r.Context(), &u, expiry, ""); err != nil {
+		return err
+	}
+	sig, err := q.extractSignature(&u)
+	if err != nil {
+		return err
+	}
+	r.Header.Set(RequestSignatureHeader, sig)
+	if !q.noExpiry || !expiry.IsZero() {
+		encodedExpiry, err := q.extractExpiry(&u)
+		if err != nil {
+			return err
+		}
+		r.Header.Set(RequestExpiresHeader, encodedExpiry)
+	}
+	r.URL = &u
+	return nil
+}
+
+// headerSigner returns a copy of the signer that computes signatures for
+// requests signed with SignRequest, whose payloads are those of URLs in the
+// query format.
+func (s *Signer) headerSigner() *Signer {
+	q := *s
+	q.formatter = newQueryFormatter()
+	return &q
+}
+
+// headerSignedURL adds the signature and expiry in the headers of a request
+// signed with SignRequest to its reconstructed URL, returning the signer with
+// which to verify it.
+func (s *Signer) headerSignedURL(u *url.URL, h http.Header) *Signer {
+	q := s.headerSigner()
+	if expiry := h.Get(RequestExpiresHeader); expiry != "" {
+		q.addExpiry(u, expiry)
+	}
+	q.addSignature(u, h.Get(RequestSignatureHeader))
+	return q
+}
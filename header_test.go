@@ -0,0 +1,84 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_SignRequest(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	newRequest := func(t *testing.T, expiry time.Time) *http.Request {
+		r := httptest.NewRequest("GET", "https://example.com/a/b/c?foo=bar", nil)
+		require.NoError(t, signer.SignRequest(r, expiry))
+		return r
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		r := newRequest(t, time.Now().Add(time.Minute))
+		assert.Equal(t, "https://example.com/a/b/c?foo=bar", r.URL.String())
+		assert.NotEmpty(t, r.Header.Get(RequestSignatureHeader))
+		assert.NotEmpty(t, r.Header.Get(RequestExpiresHeader))
+
+		assert.NoError(t, signer.VerifyRequest(r))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		r := newRequest(t, time.Now().Add(-time.Minute))
+		assert.ErrorIs(t, signer.VerifyRequest(r), ErrExpired)
+	})
+
+	t.Run("tampered expiry", func(t *testing.T) {
+		r := newRequest(t, time.Now().Add(time.Minute))
+		r.Header.Set(RequestExpiresHeader, signer.encodeExpiry(time.Now().Add(time.Hour)))
+		assert.ErrorIs(t, signer.VerifyRequest(r), ErrInvalidSignature)
+	})
+
+	t.Run("tampered URL", func(t *testing.T) {
+		r := newRequest(t, time.Now().Add(time.Minute))
+		r.URL.RawQuery = "foo=baz"
+		assert.ErrorIs(t, signer.VerifyRequest(r), ErrInvalidSignature)
+	})
+
+	t.Run("webhook to query-signed URL", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		r := httptest.NewRequest("POST", signed, nil)
+		r.Header.Set(WebhookSignatureHeader, "t=1,v1=abc")
+		assert.NoError(t, signer.VerifyRequest(r))
+	})
+
+	t.Run("path formatter", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithPathFormatter(), PrefixPath("/signed"))
+		r := httptest.NewRequest("GET", "https://example.com/a/b/c", nil)
+		require.NoError(t, signer.SignRequest(r, time.Now().Add(time.Minute)))
+		assert.Equal(t, "https://example.com/signed/a/b/c", r.URL.String())
+
+		assert.NoError(t, signer.VerifyRequest(r))
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNoExpiry())
+		r := httptest.NewRequest("GET", "https://example.com/a/b/c?foo=bar", nil)
+		require.NoError(t, signer.SignRequest(r, time.Time{}))
+		assert.Equal(t, "https://example.com/a/b/c?foo=bar", r.URL.String())
+		assert.Empty(t, r.Header.Get(RequestExpiresHeader))
+
+		assert.NoError(t, signer.VerifyRequest(r))
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		r := newRequest(t, time.Now().Add(time.Minute))
+		w := httptest.NewRecorder()
+		signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		})).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,70 @@
+package surl
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// hostEncoding encodes signatures in subdomain labels, which are
+// case-insensitive and limited to letters, digits and hyphens.
+var hostEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// hostFormatter stores the signature and expiry in a subdomain label
+// prepended to the host of a signed URL, e.g.
+// https://<sig>-<expiry>.dl.example.com/a/b/c, leaving the path and query
+// intact. The signature is re-encoded from base64 to lowercase base32.
+type hostFormatter struct{}
+
+func (f *hostFormatter) addExpiry(unsigned *url.URL, expiry string) {
+	unsigned.Host = expiry + "." + unsigned.Host
+}
+
+func (f *hostFormatter) buildPayload(u url.URL, opts payloadOptions) string {
+	if opts.skipQuery {
+		u.RawQuery = ""
+	}
+	if opts.skipScheme {
+		u.Scheme = ""
+	}
+	if opts.skipHost {
+		// Remove all labels other than expiry
+		u.Host, _, _ = strings.Cut(u.Host, ".")
+	}
+	return u.String()
+}
+
+func (f *hostFormatter) addSignature(payload *url.URL, sig string) {
+	if b, err := base64.RawURLEncoding.DecodeString(sig); err == nil {
+		sig = hostEncoding.EncodeToString(b)
+	}
+	payload.Host = sig + "-" + payload.Host
+}
+
+func (f *hostFormatter) extractSignature(u *url.URL) (string, error) {
+	label, rest, found := strings.Cut(u.Host, ".")
+	if !found {
+		return "", ErrInvalidFormat
+	}
+	encodedSig, expiry, found := strings.Cut(label, "-")
+	if !found || encodedSig == "" {
+		return "", ErrInvalidFormat
+	}
+	sig, err := hostEncoding.DecodeString(strings.ToLower(encodedSig))
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid base32: %s", ErrInvalidFormat, encodedSig)
+	}
+	u.Host = expiry + "." + rest
+	return base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (f *hostFormatter) extractExpiry(u *url.URL) (string, error) {
+	expiry, rest, found := strings.Cut(u.Host, ".")
+	if !found || expiry == "" {
+		return "", ErrInvalidFormat
+	}
+	u.Host = rest
+	return expiry, nil
+}
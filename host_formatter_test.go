@@ -0,0 +1,102 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostFormatter(t *testing.T) {
+	f := &hostFormatter{}
+	u := &url.URL{Host: "dl.example.com:8080", Path: "/a/b/c"}
+
+	f.addExpiry(u, "3507595200")
+	assert.Equal(t, "3507595200.dl.example.com:8080", u.Host)
+
+	f.addSignature(u, "abcdefgh")
+	assert.Equal(t, "ng3r26pyee-3507595200.dl.example.com:8080", u.Host)
+
+	sig, err := f.extractSignature(u)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefgh", sig)
+	assert.Equal(t, "3507595200.dl.example.com:8080", u.Host)
+
+	got, err := f.extractExpiry(u)
+	require.NoError(t, err)
+	assert.Equal(t, "3507595200", got)
+	assert.Equal(t, "dl.example.com:8080", u.Host)
+	assert.Equal(t, "/a/b/c", u.Path)
+}
+
+func TestHostFormatter_Errors(t *testing.T) {
+	f := &hostFormatter{}
+	for _, host := range []string{"", "localhost", "dl.example.com", "-3507595200.dl.example.com", "a!b-3507595200.dl.example.com"} {
+		_, err := f.extractSignature(&url.URL{Host: host})
+		assert.ErrorIs(t, err, ErrInvalidFormat, host)
+	}
+}
+
+func TestWithHostFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"skip host", []Option{SkipHost()}},
+		{"skip query", []Option{SkipQuery()}},
+		{"hmac-sha256", []Option{WithHMACSHA256()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), append([]Option{WithHostFormatter()}, tt.opts...)...)
+
+			signed, err := signer.SignFor("https://dl.example.com/a/b/c?foo=bar", time.Minute)
+			require.NoError(t, err)
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			assert.Equal(t, "/a/b/c", u.Path)
+			assert.Equal(t, "foo=bar", u.RawQuery)
+			label, _, _ := strings.Cut(u.Host, ".")
+			assert.LessOrEqual(t, len(label), 63)
+			assert.NoError(t, signer.Verify(signed))
+
+			// hosts are case-insensitive
+			u.Host = strings.ToUpper(label) + ".dl.example.com"
+			assert.NoError(t, signer.Verify(u.String()))
+
+			unsigned, err := signer.Unsign(signed)
+			require.NoError(t, err)
+			assert.Equal(t, "https://dl.example.com/a/b/c?foo=bar", unsigned)
+		})
+	}
+
+	t.Run("request", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithHostFormatter())
+		signed, err := signer.SignFor("http://dl.example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, signed, nil)
+		r.URL.Scheme, r.URL.Host = "", ""
+		assert.NoError(t, signer.VerifyRequest(r))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithHostFormatter())
+		signed, err := signer.SignFor("https://dl.example.com/a/b/c", -time.Minute)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, opt := range []Option{WithNoExpiry(), WithBase58Expiry(), WithMillisecondExpiry()} {
+			_, err := NewWithValidation([]byte("abc123"), WithHostFormatter(), opt)
+			assert.ErrorIs(t, err, ErrInvalidOption)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package surl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownHost is returned when there is no signer for the host of a URL.
+var ErrUnknownHost = errors.New("unknown host")
+
+// HostMux routes signing and verification to different signers according to
+// the host of the URL, e.g. for platforms serving many customer domains, each
+// with its own key and options.
+type HostMux struct {
+	mu        sync.RWMutex
+	exact     map[string]*Signer
+	wildcards map[string]*Signer
+}
+
+// NewHostMux constructs an empty HostMux.
+func NewHostMux() *HostMux {
+	return &HostMux{
+		exact:     make(map[string]*Signer),
+		wildcards: make(map[string]*Signer),
+	}
+}
+
+// Handle registers the signer for the host. The host is either an exact host,
+// e.g. example.com, or a wildcard, e.g. *.example.com, which matches any
+// subdomain of example.com. An exact host takes precedence over a wildcard,
+// and a longer wildcard takes precedence over a shorter one. A port is ignored
+// when matching.
+func (m *HostMux) Handle(host string, signer *Signer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	host = strings.ToLower(host)
+	if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		m.wildcards[suffix] = signer
+	} else {
+		m.exact[host] = signer
+	}
+}
+
+// Signer returns the signer for the host.
+func (m *HostMux) Signer(host string) (*Signer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	host = strings.ToLower(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if signer, ok := m.exact[host]; ok {
+		return signer, nil
+	}
+	// Try successively shorter parent domains.
+	for domain := host; ; {
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		if signer, ok := m.wildcards[parent]; ok {
+			return signer, nil
+		}
+		domain = parent
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownHost, host)
+}
+
+// Sign generates a signed URL using the signer for the URL's host.
+func (m *HostMux) Sign(unsigned string, expiry time.Time) (string, error) {
+	signer, err := m.signerFor(unsigned)
+	if err != nil {
+		return "", err
+	}
+	return signer.Sign(unsigned, expiry)
+}
+
+// Verify verifies a signed URL using the signer for the URL's host.
+func (m *HostMux) Verify(signed string) error {
+	signer, err := m.signerFor(signed)
+	if err != nil {
+		return err
+	}
+	return signer.Verify(signed)
+}
+
+func (m *HostMux) signerFor(rawURL string) (*Signer, error) {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return m.Signer(u.Host)
+}
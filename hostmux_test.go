@@ -0,0 +1,57 @@
+package surl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostMux(t *testing.T) {
+	acme := New([]byte("acme"))
+	acmeAPI := New([]byte("acme-api"))
+	widgets := New([]byte("widgets"))
+	widgetsCDN := New([]byte("widgets-cdn"))
+
+	mux := NewHostMux()
+	mux.Handle("acme.com", acme)
+	mux.Handle("api.acme.com", acmeAPI)
+	mux.Handle("*.widgets.io", widgets)
+	mux.Handle("*.cdn.widgets.io", widgetsCDN)
+
+	tests := []struct {
+		host string
+		want *Signer
+	}{
+		{"acme.com", acme},
+		{"ACME.com:8443", acme},
+		{"api.acme.com", acmeAPI},
+		{"eu.widgets.io", widgets},
+		{"a.b.widgets.io", widgets},
+		{"eu.cdn.widgets.io", widgetsCDN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got, err := mux.Signer(tt.host)
+			require.NoError(t, err)
+			assert.Same(t, tt.want, got)
+		})
+	}
+
+	t.Run("unknown host", func(t *testing.T) {
+		for _, host := range []string{"www.acme.com", "widgets.io", "example.com"} {
+			_, err := mux.Signer(host)
+			assert.Truef(t, errors.Is(err, ErrUnknownHost), "%s: got error: %v", host, err)
+		}
+	})
+
+	t.Run("sign and verify", func(t *testing.T) {
+		signed, err := mux.Sign("https://eu.widgets.io/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		require.NoError(t, widgets.Verify(signed))
+		require.NoError(t, mux.Verify(signed))
+	})
+}
@@ -0,0 +1,117 @@
+package surl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SignedURL is the metadata of a signed URL, as extracted by Parse. None of it
+// is verified.
+type SignedURL struct {
+	// URL is the URL as it was before it was signed, i.e. the signed URL with
+	// the prefix, signature, expiry and any other parameters added when
+	// signing removed.
+	URL *url.URL
+	// Signature is the encoded signature.
+	Signature string
+	// Expiry is the time at which the URL expires, or the zero time if it
+	// has no expiry.
+	Expiry time.Time
+	// NotBefore is the time from which the URL is valid, or the zero time if
+	// it has none. See SignWindow.
+	NotBefore time.Time
+	// KeyID is the ID of the key with which the URL was signed, or empty if
+	// it has none. See WithKeyring.
+	KeyID string
+	// Scope is the scope of the signer that signed the URL, or empty if it
+	// is unscoped. See Signer.Scoped.
+	Scope string
+	// ID is the unique ID of the URL, or empty if it has none.
+	ID string
+	// MaxUses is the maximum number of uses of the URL, or zero if it is
+	// unlimited. See SignWithMaxUses.
+	MaxUses int
+	// Methods are the HTTP methods permitted for the URL, or nil if any
+	// method is permitted. See SignForMethods.
+	Methods []string
+	// Subtree is the path beneath which the URL grants access, or empty if
+	// it grants access to itself alone. See SignSubtree.
+	Subtree string
+	// Network is the network to which the URL is bound, or empty if it is
+	// not bound to one. See SignForNetwork.
+	Network string
+}
+
+// Parse extracts the metadata of a signed URL without verifying it, e.g. for
+// tooling, logging and admin UIs that display the expiry of links. The options
+// must describe the format of the URL, e.g. WithPathFormatter and PrefixPath,
+// as they would for the signer that signed it; no key is required. Since the
+// signature is not checked, the metadata must not be trusted.
+func Parse(signed string, opts ...Option) (*SignedURL, error) {
+	return newSigner(nil, nil, opts...).parse(signed)
+}
+
+// parse extracts the metadata of a signed URL without verifying it.
+func (s *Signer) parse(signed string) (*SignedURL, error) {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return nil, err
+	}
+	prefix, ok := s.matchPrefix(u.Path)
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+	u.Path = u.Path[len(prefix):]
+	u.RawPath = strings.TrimPrefix(u.RawPath, prefix)
+
+	scope, err := s.extractScope(u)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := removeQueryParam(u, keyIDParam)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid key ID", ErrInvalidFormat)
+	}
+	if s.selfDescribing {
+		if s, err = s.described(u); err != nil {
+			return nil, err
+		}
+	}
+	network, err := removeQueryParam(u, networkParam)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid network", ErrInvalidFormat)
+	}
+	result, _, err := s.extractComponents(u, "")
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.extractSignature(u)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiry time.Time
+	stripped := *u
+	if encoded, err := s.extractExpiry(&stripped); err == nil && encoded != "" {
+		if expiry, err = s.decodeExpiry(encoded); err != nil {
+			return nil, err
+		}
+		u = &stripped
+	}
+
+	return &SignedURL{
+		URL:       u,
+		Signature: sig,
+		Expiry:    expiry,
+		NotBefore: result.NotBefore,
+		KeyID:     kid,
+		Scope:     scope,
+		ID:        result.ID,
+		MaxUses:   result.MaxUses,
+		Methods:   result.Methods,
+		Subtree:   result.Subtree,
+		Network:   network,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package surl
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+
+	t.Run("query formatter", func(t *testing.T) {
+		signed, err := New([]byte("abc123")).Sign("https://example.com/a/b/c?foo=bar", expiry)
+		require.NoError(t, err)
+
+		got, err := Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/a/b/c?foo=bar", got.URL.String())
+		assert.Equal(t, expiry.Unix(), got.Expiry.Unix())
+		assert.NotEmpty(t, got.Signature)
+	})
+
+	t.Run("path formatter", func(t *testing.T) {
+		opts := []Option{WithPathFormatter(), WithBase58Expiry(), PrefixPath("/signed")}
+		signed, err := New([]byte("abc123"), opts...).Sign("https://example.com/a/b/c?foo=bar", expiry)
+		require.NoError(t, err)
+
+		got, err := Parse(signed, opts...)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/a/b/c?foo=bar", got.URL.String())
+		assert.Equal(t, expiry.Unix(), got.Expiry.Unix())
+	})
+
+	t.Run("components", func(t *testing.T) {
+		keyring, err := NewKeyring("2024-01", map[string][]byte{"2024-01": []byte("abc123")})
+		require.NoError(t, err)
+		signer := New([]byte("abc123"), WithKeyring(keyring)).Scoped("/a")
+
+		signed, err := signer.SignForMethods("https://example.com/a/b/c", expiry, http.MethodGet)
+		require.NoError(t, err)
+
+		got, err := Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/a/b/c", got.URL.String())
+		assert.Equal(t, "2024-01", got.KeyID)
+		assert.Equal(t, "/a", got.Scope)
+		assert.Equal(t, []string{http.MethodGet}, got.Methods)
+	})
+
+	t.Run("network", func(t *testing.T) {
+		signed, err := New([]byte("abc123")).SignForNetwork("https://example.com/a/b/c", expiry, netip.MustParsePrefix("10.0.0.0/8"))
+		require.NoError(t, err)
+
+		got, err := Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/8", got.Network)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		signed, err := New([]byte("abc123"), WithNoExpiry()).Sign("https://example.com/a/b/c", time.Time{})
+		require.NoError(t, err)
+
+		got, err := Parse(signed)
+		require.NoError(t, err)
+		assert.True(t, got.Expiry.IsZero())
+		assert.Equal(t, "https://example.com/a/b/c", got.URL.String())
+	})
+
+	t.Run("self-describing", func(t *testing.T) {
+		signed, err := New([]byte("abc123"), SelfDescribing(), WithPathFormatter()).Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		got, err := Parse(signed, SelfDescribing())
+		require.NoError(t, err)
+		assert.Equal(t, expiry.Unix(), got.Expiry.Unix())
+		assert.Equal(t, "https://example.com/a/b/c", got.URL.String())
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := Parse("https://example.com/a/b/c")
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+}
@@ -3,13 +3,18 @@ package surl
 import (
 	"encoding/base64"
 	"encoding/binary"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/itchyny/base58-go"
 )
 
-// intEncoding encodes integers into strings, and decodes strings into integers
-type intEncoding interface {
+// ExpiryEncoding encodes expiries, as seconds since the epoch, into strings,
+// and decodes strings into expiries. Encoded expiries must be URL-safe and
+// must not contain a dot, which separates them from the signature in several
+// formats.
+type ExpiryEncoding interface {
 	Encode(int64) string
 	Decode(string) (int64, error)
 }
@@ -51,5 +56,27 @@ func (base64Encoding) Decode(s string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	if len(bytes) != 8 {
+		return 0, fmt.Errorf("invalid base64 expiry length: %d", len(bytes))
+	}
 	return int64(binary.BigEndian.Uint64(bytes)), nil
 }
+
+// timestampLayout is the layout of expiries encoded by timestampEncoding.
+const timestampLayout = "20060102T150405Z"
+
+// timestampEncoding encodes integers, which are Unix times, as human-readable
+// UTC timestamps.
+type timestampEncoding struct{}
+
+func (timestampEncoding) Encode(i int64) string {
+	return time.Unix(i, 0).UTC().Format(timestampLayout)
+}
+
+func (timestampEncoding) Decode(s string) (int64, error) {
+	t, err := time.Parse(timestampLayout, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
@@ -2,6 +2,7 @@ package surl
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,7 +11,7 @@ import (
 func TestIntEncoding(t *testing.T) {
 	tests := []struct {
 		name     string
-		encoding intEncoding
+		encoding ExpiryEncoding
 		input    int64
 		want     string
 	}{
@@ -38,6 +39,12 @@ func TestIntEncoding(t *testing.T) {
 			input:    3507595200,
 			want:     "AAAAANERp8A",
 		},
+		{
+			name:     "timestamp",
+			encoding: timestampEncoding{},
+			input:    3507595200,
+			want:     "20810224T040000Z",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,3 +56,77 @@ func TestIntEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestBase64Encoding_ShortInput(t *testing.T) {
+	_, err := base64Encoding{}.Decode("AAAA")
+	assert.Error(t, err)
+
+	signer := New([]byte("abc123"), WithBase64Expiry())
+	err = signer.Verify("https://example.com/a?expiry=AAAA&signature=abc&signature_not_before=AAAA")
+	assert.Error(t, err)
+}
+
+func TestWithTimestampExpiry(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	signer := New([]byte("abc123"), WithTimestampExpiry())
+
+	signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+	assert.Contains(t, signed, "expiry="+expiry.UTC().Format("20060102T150405Z"))
+	assert.NoError(t, signer.Verify(signed))
+
+	t.Run("ignores epoch", func(t *testing.T) {
+		epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		signer := New([]byte("abc123"), WithTimestampExpiry(), WithEpoch(epoch))
+
+		got, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+		assert.Equal(t, signed, got)
+		assert.NoError(t, signer.Verify(got))
+	})
+
+	t.Run("self-describing", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithTimestampExpiry(), SelfDescribing())
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		assert.NoError(t, New([]byte("abc123"), SelfDescribing()).Verify(signed))
+	})
+}
+
+// hexEncoding is a custom expiry encoding.
+type hexEncoding struct{}
+
+func (hexEncoding) Encode(i int64) string { return stdIntEncoding(16).Encode(i) }
+
+func (hexEncoding) Decode(s string) (int64, error) { return stdIntEncoding(16).Decode(s) }
+
+func TestWithExpiryEncoding(t *testing.T) {
+	signer := New([]byte("abc123"), WithExpiryEncoding(hexEncoding{}))
+
+	signed, err := signer.Sign("https://example.com/a/b/c", time.Unix(3507595200, 0))
+	require.NoError(t, err)
+	assert.Contains(t, signed, "expiry=d111a7c0")
+	assert.NoError(t, signer.Verify(signed))
+	assert.Equal(t, "custom", signer.Config().ExpiryEncoding)
+
+	t.Run("base64", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithBase64Expiry())
+
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Unix(3507595200, 0))
+		require.NoError(t, err)
+		assert.Contains(t, signed, "expiry=AAAAANERp8A")
+		assert.NoError(t, signer.Verify(signed))
+		assert.Equal(t, "base64", signer.Config().ExpiryEncoding)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		_, err := NewWithValidation([]byte("abc123"), WithExpiryEncoding(nil))
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+
+	t.Run("self-describing", func(t *testing.T) {
+		_, err := NewWithValidation([]byte("abc123"), WithExpiryEncoding(hexEncoding{}), SelfDescribing())
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+}
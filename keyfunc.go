@@ -0,0 +1,51 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// KeyFunc looks up the key with the ID, e.g. the key of a tenant. It should
+// return an error wrapping ErrUnknownKey if there is no such key.
+type KeyFunc func(ctx context.Context, keyID string) ([]byte, error)
+
+// WithKeyFunc instructs Signer to look up keys at the time of signing and
+// verification, rather than holding them from construction. This suits
+// signing URLs for many tenants, each with their own key. URLs are signed
+// with SignWithKeyID, which adds the key ID to the URL in a query parameter,
+// and when verifying, the key is looked up using the ID in the URL. Use
+// VerifyContext or VerifyRequest to pass a context to the function. URLs
+// without a key ID are verified using the signer's own key.
+//
+// The function is called upon every signing and verification, so it should
+// cache keys if looking them up is expensive.
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(s *Signer) {
+		s.keyFunc = fn
+		s.keyring = nil
+	}
+}
+
+// SignWithKeyID generates a signed URL using the key with the ID, looked up
+// using the key function configured with WithKeyFunc, or in the keyring
+// configured with WithKeyring. The key ID is added to the URL in a query
+// parameter.
+func (s *Signer) SignWithKeyID(ctx context.Context, keyID, unsigned string, expiry time.Time) (string, error) {
+	if s.keyring == nil && s.keyFunc == nil {
+		return "", errNoKeySource
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	key, err := s.lookupKey(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.withKey(key).signURL(ctx, u, expiry, ""); err != nil {
+		return "", err
+	}
+	appendQueryParam(u, keyIDParam, keyID)
+	return u.String(), nil
+}
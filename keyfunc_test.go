@@ -0,0 +1,95 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithKeyFunc(t *testing.T) {
+	tenants := map[string][]byte{
+		"tenant-1": []byte("abc123"),
+		"tenant-2": []byte("def456"),
+	}
+	type ctxKey struct{}
+
+	var lookups []string
+	signer := New([]byte("default"), WithKeyFunc(func(ctx context.Context, keyID string) ([]byte, error) {
+		if v, ok := ctx.Value(ctxKey{}).(string); ok {
+			lookups = append(lookups, v)
+		}
+		key, ok := tenants[keyID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+		}
+		return key, nil
+	}))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "traced")
+
+	signed, err := signer.SignWithKeyID(ctx, "tenant-1", "https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	t.Run("embeds key ID", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-1", u.Query().Get("signature_kid"))
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(signed))
+		assert.NoError(t, signer.VerifyContext(ctx, signed))
+		assert.NoError(t, signer.VerifyRequest(httptest.NewRequest("GET", signed, nil).WithContext(ctx)))
+		assert.Equal(t, []string{"traced", "traced", "traced"}, lookups)
+	})
+
+	t.Run("signed with tenant key", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set("signature_kid", "tenant-2")
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		_, err := signer.SignWithKeyID(ctx, "tenant-3", "https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrUnknownKey)
+
+		tenants["tenant-3"] = []byte("xyz789")
+		signed, err := signer.SignWithKeyID(ctx, "tenant-3", "https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		delete(tenants, "tenant-3")
+
+		assert.ErrorIs(t, signer.Verify(signed), ErrUnknownKey)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		unavailable := errors.New("vault unavailable")
+		signer := New([]byte("default"), WithKeyFunc(func(context.Context, string) ([]byte, error) {
+			return nil, unavailable
+		}))
+
+		assert.ErrorIs(t, signer.Verify(signed), unavailable)
+	})
+
+	t.Run("without key ID", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, New([]byte("default")).Verify(signed))
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("without key source", func(t *testing.T) {
+		_, err := New([]byte("default")).SignWithKeyID(ctx, "tenant-1", "https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.Error(t, err)
+	})
+}
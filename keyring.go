@@ -0,0 +1,164 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// keyIDParam is the query parameter that carries the ID of the key that signed
+// a URL.
+const keyIDParam = "signature_kid"
+
+// ErrUnknownKey is returned when a URL is signed with a key that is not in the
+// keyring, e.g. because it has been revoked.
+var ErrUnknownKey = errors.New("unknown key")
+
+// errNoKeySource is returned when signing with a key ID without a keyring or
+// key function.
+var errNoKeySource = errors.New("signer has neither a keyring nor a key function")
+
+// Keyring maps key IDs to keys, one of which is the current key used for
+// signing. It is safe for concurrent use, and keys may be added and removed
+// whilst it is in use.
+type Keyring struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyring constructs a keyring holding the keys, keyed by ID, with the
+// given current key.
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		k.keys[id] = key
+	}
+	if err := k.SetCurrent(current); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Add adds a key to the keyring, replacing any key with the same ID.
+func (k *Keyring) Add(id string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys[id] = key
+}
+
+// Remove removes a key from the keyring, revoking all URLs signed with it.
+// The current key cannot be removed.
+func (k *Keyring) Remove(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if id == k.current {
+		return fmt.Errorf("cannot remove current key: %s", id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+// SetCurrent sets the current key, with which URLs are subsequently signed.
+func (k *Keyring) SetCurrent(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	k.current = id
+	return nil
+}
+
+// currentKey returns the current key and its ID.
+func (k *Keyring) currentKey() (string, []byte) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.current, k.keys[k.current]
+}
+
+// key returns the key with the ID.
+func (k *Keyring) key(id string) ([]byte, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[id]
+	return key, ok
+}
+
+// WithKeyring instructs Signer to sign URLs with the current key of the
+// keyring, adding the ID of the key to the URL in a query parameter. When
+// verifying, the key is looked up using the ID in the URL, and URLs without a
+// key ID are verified using the signer's own key. This permits keys to be
+// rotated gradually, by adding a new key and making it current, and revoked
+// individually, by removing them from the keyring.
+//
+// The keyring only applies to URLs; tokens, webhooks, etc, are signed using
+// the signer's own key.
+func WithKeyring(keyring *Keyring) Option {
+	return func(s *Signer) {
+		s.keyring = keyring
+		s.keyFunc = nil
+	}
+}
+
+// withKey returns a copy of the signer that signs with the key, or a key
+// derived from it if the signer is scoped.
+func (s *Signer) withKey(key []byte) *Signer {
+	c := *s
+	c.key = key
+	if s.purpose != "" {
+		c.key = deriveKey(c.key, "surl purpose "+s.purpose)
+	}
+	if s.scope != "" {
+		c.key = deriveKey(c.key, "surl scope "+s.scope)
+	}
+	c.alg = s.keyedHashFor(c.key)
+	c.fallbacks = nil
+	c.keyring = nil
+	c.keyFunc = nil
+	return &c
+}
+
+// extractKeyID removes the key ID from the query of a signed URL, returning
+// the signer to use for verifying the URL.
+func (s *Signer) extractKeyID(ctx context.Context, u *url.URL) (*Signer, error) {
+	if s.keyring == nil && s.keyFunc == nil {
+		return s, nil
+	}
+	id, err := removeQueryParam(u, keyIDParam)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid key ID", ErrInvalidFormat)
+	}
+	if id == "" {
+		return s, nil
+	}
+	key, err := s.lookupKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withKey(key), nil
+}
+
+// lookupKey looks up the key with the ID in the keyring or using the key
+// function.
+func (s *Signer) lookupKey(ctx context.Context, id string) ([]byte, error) {
+	if s.keyFunc != nil {
+		key, err := s.keyFunc(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("looking up key %s: %w", id, err)
+		}
+		return key, nil
+	}
+	key, ok := s.keyring.key(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %s", ErrInvalidSignature, ErrUnknownKey, id)
+	}
+	return key, nil
+}
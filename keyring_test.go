@@ -0,0 +1,99 @@
+package surl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	keyring, err := NewKeyring("2024-01", map[string][]byte{
+		"2024-01": []byte("abc123"),
+	})
+	require.NoError(t, err)
+
+	signer := New([]byte("legacy"), WithKeyring(keyring))
+
+	sign := func(s *Signer) string {
+		signed, err := s.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		return signed
+	}
+
+	t.Run("embeds key ID", func(t *testing.T) {
+		signed := sign(signer)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01", u.Query().Get("signature_kid"))
+
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("signed with keyring key", func(t *testing.T) {
+		signed := sign(signer)
+
+		// the URL was not signed with the signer's own key
+		assert.ErrorIs(t, New([]byte("legacy")).Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("without key ID verified with signer's key", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(sign(New([]byte("legacy")))))
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		before := sign(signer)
+
+		keyring.Add("2024-02", []byte("def456"))
+		require.NoError(t, keyring.SetCurrent("2024-02"))
+		t.Cleanup(func() { _ = keyring.SetCurrent("2024-01") })
+
+		after := sign(signer)
+		u, err := url.Parse(after)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-02", u.Query().Get("signature_kid"))
+
+		assert.NoError(t, signer.Verify(before))
+		assert.NoError(t, signer.Verify(after))
+	})
+
+	t.Run("revocation", func(t *testing.T) {
+		keyring.Add("compromised", []byte("xyz789"))
+		require.NoError(t, keyring.SetCurrent("compromised"))
+		signed := sign(signer)
+		require.NoError(t, keyring.SetCurrent("2024-01"))
+
+		require.NoError(t, signer.Verify(signed))
+
+		require.NoError(t, keyring.Remove("compromised"))
+		err := signer.Verify(signed)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("cannot remove current key", func(t *testing.T) {
+		assert.Error(t, keyring.Remove("2024-01"))
+	})
+
+	t.Run("unknown current key", func(t *testing.T) {
+		_, err := NewKeyring("missing", nil)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		signed, err := signer.Scoped("/a").Sign("https://example.com/a/b", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		assert.NoError(t, signer.Scoped("/a").Verify(signed))
+	})
+
+	t.Run("path formatter", func(t *testing.T) {
+		signer := signer.With(WithPathFormatter())
+
+		assert.NoError(t, signer.Verify(sign(signer)))
+	})
+}
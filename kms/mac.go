@@ -0,0 +1,233 @@
+// Package kms signs URLs with MAC keys held in a key management service, such
+// as AWS KMS or Google Cloud KMS, so that the secret never enters the process.
+// Construct a MAC and pass it to surl.NewFromMACService:
+//
+//	signer := surl.NewFromMACService(kms.New(client))
+//
+// The package does not depend on the cloud SDKs. Instead, adapt the client of
+// the service to the Client interface, e.g. for AWS KMS:
+//
+//	kms.ClientFuncs{
+//		Generate: func(ctx context.Context, message []byte) ([]byte, error) {
+//			out, err := client.GenerateMac(ctx, &awskms.GenerateMacInput{
+//				KeyId:        aws.String(keyID),
+//				Message:      message,
+//				MacAlgorithm: types.MacAlgorithmSpecHmacSha256,
+//			})
+//			if err != nil {
+//				return nil, err
+//			}
+//			return out.Mac, nil
+//		},
+//		Verify: func(ctx context.Context, message, mac []byte) (bool, error) {
+//			out, err := client.VerifyMac(ctx, &awskms.VerifyMacInput{
+//				KeyId:        aws.String(keyID),
+//				Message:      message,
+//				Mac:          mac,
+//				MacAlgorithm: types.MacAlgorithmSpecHmacSha256,
+//			})
+//			var invalid *types.KMSInvalidMacException
+//			if errors.As(err, &invalid) {
+//				return false, nil
+//			} else if err != nil {
+//				return false, err
+//			}
+//			return out.MacValid, nil
+//		},
+//	}
+//
+// and for Google Cloud KMS:
+//
+//	kms.ClientFuncs{
+//		Generate: func(ctx context.Context, message []byte) ([]byte, error) {
+//			resp, err := client.MacSign(ctx, &kmspb.MacSignRequest{
+//				Name: keyVersion,
+//				Data: message,
+//			})
+//			if err != nil {
+//				return nil, err
+//			}
+//			return resp.Mac, nil
+//		},
+//		Verify: func(ctx context.Context, message, mac []byte) (bool, error) {
+//			resp, err := client.MacVerify(ctx, &kmspb.MacVerifyRequest{
+//				Name: keyVersion,
+//				Data: message,
+//				Mac:  mac,
+//			})
+//			if err != nil {
+//				return false, err
+//			}
+//			return resp.Success, nil
+//		},
+//	}
+package kms
+
+import (
+	"container/list"
+	"context"
+	"crypto/subtle"
+	"sync"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+const (
+	// DefaultTimeout is the default time limit for a call to the service.
+	DefaultTimeout = 5 * time.Second
+	// DefaultCacheSize is the default number of MACs to cache.
+	DefaultCacheSize = 1024
+)
+
+// Client computes and verifies the MAC of a message using a key held in a key
+// management service.
+type Client interface {
+	GenerateMAC(ctx context.Context, message []byte) ([]byte, error)
+	// VerifyMAC reports whether mac is a valid MAC of the message.
+	VerifyMAC(ctx context.Context, message, mac []byte) (bool, error)
+}
+
+// ClientFuncs adapts a pair of functions to a Client.
+type ClientFuncs struct {
+	Generate func(ctx context.Context, message []byte) ([]byte, error)
+	Verify   func(ctx context.Context, message, mac []byte) (bool, error)
+}
+
+// GenerateMAC calls f.Generate(ctx, message).
+func (f ClientFuncs) GenerateMAC(ctx context.Context, message []byte) ([]byte, error) {
+	return f.Generate(ctx, message)
+}
+
+// VerifyMAC calls f.Verify(ctx, message, mac).
+func (f ClientFuncs) VerifyMAC(ctx context.Context, message, mac []byte) (bool, error) {
+	return f.Verify(ctx, message, mac)
+}
+
+// MAC computes and verifies MACs with a Client, implementing
+// surl.MACService, caching the most recently computed and verified MACs. MACs
+// are deterministic, so verifying a URL recently signed or verified does not
+// call the service again. Only MAC algorithms, such as HMAC-SHA256, are
+// supported; asymmetric keys should instead be adapted to a crypto.Signer and
+// passed to surl.NewFromCryptoSigner.
+type MAC struct {
+	client  Client
+	timeout time.Duration
+
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	cache map[string]*list.Element
+}
+
+// Option configures a MAC.
+type Option func(*MAC)
+
+// WithTimeout sets the time limit for a call to the service. The default is
+// DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *MAC) {
+		m.timeout = timeout
+	}
+}
+
+// WithCacheSize sets the number of MACs to cache. Zero disables caching. The
+// default is DefaultCacheSize.
+func WithCacheSize(size int) Option {
+	return func(m *MAC) {
+		m.size = size
+	}
+}
+
+// New constructs a MAC computing MACs with the client.
+func New(client Client, opts ...Option) *MAC {
+	m := &MAC{
+		client:  client,
+		timeout: DefaultTimeout,
+		size:    DefaultCacheSize,
+		order:   list.New(),
+		cache:   make(map[string]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// entry is a cached MAC.
+type entry struct {
+	message string
+	mac     []byte
+}
+
+// GenerateMAC computes the MAC of the data, using the cached MAC if there is
+// one, and otherwise calling the service with the context, limited by the
+// timeout.
+func (m *MAC) GenerateMAC(ctx context.Context, data []byte) ([]byte, error) {
+	if mac, ok := m.get(string(data)); ok {
+		return mac, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	mac, err := m.client.GenerateMAC(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	m.put(string(data), mac)
+	return mac, nil
+}
+
+// VerifyMAC verifies the MAC of the data, returning surl.ErrInvalidSignature
+// if it is invalid. The MAC is compared with the cached MAC if there is one,
+// and otherwise verified by calling the service with the context, limited by
+// the timeout.
+func (m *MAC) VerifyMAC(ctx context.Context, data, mac []byte) error {
+	if cached, ok := m.get(string(data)); ok {
+		if subtle.ConstantTimeCompare(mac, cached) != 1 {
+			return surl.ErrInvalidSignature
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	valid, err := m.client.VerifyMAC(ctx, data, mac)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return surl.ErrInvalidSignature
+	}
+	m.put(string(data), mac)
+	return nil
+}
+
+func (m *MAC) get(message string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.cache[message]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(elem)
+	return elem.Value.(*entry).mac, true
+}
+
+func (m *MAC) put(message string, mac []byte) {
+	if m.size <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.cache[message]; ok {
+		m.order.MoveToFront(elem)
+		return
+	}
+	m.cache[message] = m.order.PushFront(&entry{message: message, mac: mac})
+	for m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.cache, oldest.Value.(*entry).message)
+	}
+}
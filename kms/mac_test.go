@@ -0,0 +1,126 @@
+package kms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+// fakeKMS computes and verifies HMAC-SHA256 MACs, counting calls.
+type fakeKMS struct {
+	key      []byte
+	calls    int
+	verifies int
+	err      error
+	// value of ctxKey in the context of the last call
+	value any
+}
+
+func (f *fakeKMS) GenerateMAC(ctx context.Context, message []byte) ([]byte, error) {
+	f.calls++
+	f.value = ctx.Value(ctxKey{})
+	if f.err != nil {
+		return nil, f.err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, errors.New("missing deadline")
+	}
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+func (f *fakeKMS) VerifyMAC(ctx context.Context, message, mac []byte) (bool, error) {
+	f.verifies++
+	f.value = ctx.Value(ctxKey{})
+	if f.err != nil {
+		return false, f.err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return false, errors.New("missing deadline")
+	}
+	h := hmac.New(sha256.New, f.key)
+	h.Write(message)
+	return hmac.Equal(mac, h.Sum(nil)), nil
+}
+
+func TestMAC(t *testing.T) {
+	client := &fakeKMS{key: []byte("abc123")}
+	signer := surl.NewFromMACService(New(client))
+
+	signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.calls)
+
+	// verification uses the cached MAC
+	require.NoError(t, signer.Verify(signed))
+	assert.Equal(t, 1, client.calls)
+	assert.Equal(t, 0, client.verifies)
+
+	t.Run("without cache", func(t *testing.T) {
+		client := &fakeKMS{key: []byte("abc123")}
+		signer := surl.NewFromMACService(New(client, WithCacheSize(0)))
+
+		require.NoError(t, signer.Verify(signed))
+		require.NoError(t, signer.Verify(signed))
+		assert.Equal(t, 0, client.calls)
+		assert.Equal(t, 2, client.verifies)
+	})
+
+	t.Run("verified MAC is cached", func(t *testing.T) {
+		client := &fakeKMS{key: []byte("abc123")}
+		signer := surl.NewFromMACService(New(client))
+
+		require.NoError(t, signer.Verify(signed))
+		require.NoError(t, signer.Verify(signed))
+		assert.Equal(t, 1, client.verifies)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		client := &fakeKMS{key: []byte("abc123")}
+		signer := surl.NewFromMACService(New(client, WithCacheSize(0)))
+		ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+
+		_, err := signer.SignContext(ctx, "https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, "caller", client.value)
+
+		client.value = nil
+		require.NoError(t, signer.VerifyContext(ctx, signed))
+		assert.Equal(t, "caller", client.value)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		signer := surl.NewFromMACService(New(&fakeKMS{key: []byte("def456")}))
+
+		assert.ErrorIs(t, signer.Verify(signed), surl.ErrInvalidSignature)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		unavailable := errors.New("service unavailable")
+		signer := surl.NewFromMACService(New(&fakeKMS{err: unavailable}))
+
+		assert.ErrorIs(t, signer.Verify(signed), unavailable)
+	})
+}
+
+func TestMAC_Eviction(t *testing.T) {
+	client := &fakeKMS{key: []byte("abc123")}
+	m := New(client, WithCacheSize(2))
+
+	for _, msg := range []string{"a", "b", "a", "c", "a", "b"} {
+		_, err := m.GenerateMAC(context.Background(), []byte(msg))
+		require.NoError(t, err)
+	}
+	// a, b, c miss; b is evicted by c, and misses again.
+	assert.Equal(t, 4, client.calls)
+}
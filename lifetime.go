@@ -0,0 +1,39 @@
+package surl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLifetimeExceeded is returned when a signed URL expires further in the
+// future than the maximum lifetime permits.
+var ErrLifetimeExceeded = errors.New("URL exceeds maximum lifetime")
+
+// WithMaxLifetime instructs Signer to reject URLs that expire more than the
+// given duration into the future, both when signing and when verifying them,
+// returning ErrLifetimeExceeded. This limits the damage done by a compromised
+// signer minting URLs that last for years, and satisfies compliance
+// requirements on the lifetime of links. URLs that never expire, signed with
+// WithNoExpiry, are always rejected. Should the clocks of signers run ahead of
+// those of verifiers, configure verifiers with a slightly longer lifetime.
+func WithMaxLifetime(d time.Duration) Option {
+	return func(s *Signer) {
+		s.maxLifetime = d
+	}
+}
+
+// checkLifetime checks a URL expiring at the given time, or never if it is
+// permanent, does not exceed the maximum lifetime, as of now.
+func (s *Signer) checkLifetime(expiresAt time.Time, permanent bool, now time.Time) error {
+	if s.maxLifetime == 0 {
+		return nil
+	}
+	if permanent {
+		return fmt.Errorf("%w: never expires", ErrLifetimeExceeded)
+	}
+	if lifetime := expiresAt.Sub(now); lifetime > s.maxLifetime {
+		return fmt.Errorf("%w: %s exceeds %s", ErrLifetimeExceeded, lifetime.Round(time.Second), s.maxLifetime)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxLifetime(t *testing.T) {
+	signer := New([]byte("abc123"), WithMaxLifetime(time.Hour))
+
+	signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(signed))
+
+	_, err = signer.Sign("https://example.com/a/b/c", time.Now().Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrLifetimeExceeded)
+
+	t.Run("minted elsewhere", func(t *testing.T) {
+		// a signer without the limit mints a decade-long URL
+		signed, err := New([]byte("abc123")).Sign("https://example.com/a/b/c", time.Now().AddDate(10, 0, 0))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(signed), ErrLifetimeExceeded)
+		// within the limit later on
+		assert.NoError(t, signer.VerifyAt(signed, time.Now().AddDate(10, 0, 0).Add(-time.Minute)))
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNoExpiry(), WithMaxLifetime(time.Hour))
+		_, err := signer.Sign("https://example.com/a/b/c", time.Time{})
+		assert.ErrorIs(t, err, ErrLifetimeExceeded)
+
+		signed, err := New([]byte("abc123"), WithNoExpiry()).Sign("https://example.com/a/b/c", time.Time{})
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrLifetimeExceeded)
+	})
+}
@@ -0,0 +1,77 @@
+package surl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"time"
+)
+
+// manifestPurpose distinguishes manifest tokens from other tokens.
+const manifestPurpose = "manifest"
+
+// manifestHashSize is the size in bytes of each URL hash in a manifest,
+// truncated from SHA-256 to keep manifests for large sets of URLs compact.
+const manifestHashSize = 16
+
+// ErrNotInManifest is returned when a URL is not listed in a manifest.
+var ErrNotInManifest = errors.New("URL not in manifest")
+
+// SignManifest signs a set of URLs, producing a single detached manifest
+// token. The URLs themselves are left unmodified; instead each is verified
+// against the manifest using VerifyManifest. This suits batch exports of many
+// URLs, which would otherwise each carry their own signature. The manifest
+// holds a hash of each URL rather than the URL itself, so its size grows with
+// the number of URLs but not their length.
+func (s *Signer) SignManifest(urls []string, expiry time.Time) (string, error) {
+	hashes := make([][]byte, len(urls))
+	for i, unsigned := range urls {
+		h, err := manifestHash(unsigned)
+		if err != nil {
+			return "", err
+		}
+		hashes[i] = h
+	}
+	slices.SortFunc(hashes, bytes.Compare)
+	hashes = slices.CompactFunc(hashes, bytes.Equal)
+	return s.signToken(manifestPurpose, bytes.Join(hashes, nil), expiry)
+}
+
+// VerifyManifest verifies a manifest token produced by SignManifest, and
+// checks the URL is one of those listed in the manifest.
+func (s *Signer) VerifyManifest(manifest, unsigned string) error {
+	data, err := s.verifyToken(manifestPurpose, manifest)
+	if err != nil {
+		return err
+	}
+	if len(data)%manifestHashSize != 0 {
+		return fmt.Errorf("%w: invalid manifest length: %d", ErrInvalidFormat, len(data))
+	}
+	h, err := manifestHash(unsigned)
+	if err != nil {
+		return err
+	}
+	// hashes are sorted so use a binary search
+	n := len(data) / manifestHashSize
+	i := sort.Search(n, func(i int) bool {
+		return bytes.Compare(data[i*manifestHashSize:(i+1)*manifestHashSize], h) >= 0
+	})
+	if i == n || !bytes.Equal(data[i*manifestHashSize:(i+1)*manifestHashSize], h) {
+		return fmt.Errorf("%w: %s", ErrNotInManifest, unsigned)
+	}
+	return nil
+}
+
+// manifestHash returns the hash of a URL listed in a manifest.
+func manifestHash(unsigned string) ([]byte, error) {
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256([]byte(u.String()))
+	return h[:manifestHashSize], nil
+}
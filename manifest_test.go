@@ -0,0 +1,53 @@
+package surl
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	urls := make([]string, 1000)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/exports/%d.csv", i)
+	}
+	// duplicates are permitted
+	urls = append(urls, urls[0])
+
+	manifest, err := signer.SignManifest(urls, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	t.Run("listed", func(t *testing.T) {
+		for _, u := range urls {
+			assert.NoError(t, signer.VerifyManifest(manifest, u))
+		}
+	})
+
+	t.Run("not listed", func(t *testing.T) {
+		err := signer.VerifyManifest(manifest, "https://example.com/exports/1000.csv")
+		assert.ErrorIs(t, err, ErrNotInManifest)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		manifest, err := signer.SignManifest(urls, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		err = signer.VerifyManifest(manifest, urls[0])
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		err := New([]byte("def456")).VerifyManifest(manifest, urls[0])
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		_, err := signer.SignManifest([]string{"not a url"}, time.Now().Add(time.Minute))
+		assert.Error(t, err)
+	})
+}
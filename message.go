@@ -0,0 +1,276 @@
+package surl
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader is the HTTP header carrying an HTTP message signature.
+	SignatureHeader = "Signature"
+	// SignatureInputHeader is the HTTP header carrying the covered components
+	// and parameters of an HTTP message signature.
+	SignatureInputHeader = "Signature-Input"
+
+	// messageSignatureLabel labels the signature in the signature headers.
+	messageSignatureLabel = "sig1"
+)
+
+// SignMessage signs an HTTP request in accordance with RFC 9421 (HTTP Message
+// Signatures). The signature covers the request method, the target URI, and
+// the values of the given headers, each of which must be present on the
+// request. The signature is added to the request in the Signature and
+// Signature-Input headers.
+//
+// The signature is computed using the signer's key, so it can only be
+// verified with VerifyMessage by a Signer holding the same key.
+func (s *Signer) SignMessage(r *http.Request, expiry time.Time, headers ...string) error {
+	components := []string{"@method", "@target-uri"}
+	for _, h := range headers {
+		if !isSFStringContent(h) {
+			return fmt.Errorf("%w: invalid header name: %q", ErrInvalidFormat, h)
+		}
+		components = append(components, strings.ToLower(h))
+	}
+	params := messageParams{
+		created: time.Now().Unix(),
+		expires: expiry.Unix(),
+	}
+	input := params.serialize(components)
+
+	base, err := signatureBase(r, components, input)
+	if err != nil {
+		return err
+	}
+	sig, err := s.sign(r.Context(), []byte(base))
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(SignatureInputHeader, messageSignatureLabel+"="+input)
+	r.Header.Set(SignatureHeader, messageSignatureLabel+"=:"+base64.StdEncoding.EncodeToString(sig)+":")
+	return nil
+}
+
+// VerifyMessage verifies an HTTP request signed with SignMessage, ensuring the
+// signature is valid and unexpired, and that it covers at least the request
+// method and target URI.
+func (s *Signer) VerifyMessage(r *http.Request) error {
+	input, err := dictionaryMember(r.Header.Get(SignatureInputHeader), messageSignatureLabel)
+	if err != nil {
+		return err
+	}
+	encodedSig, err := dictionaryMember(r.Header.Get(SignatureHeader), messageSignatureLabel)
+	if err != nil {
+		return err
+	}
+	if len(encodedSig) < 2 || encodedSig[0] != ':' || encodedSig[len(encodedSig)-1] != ':' {
+		return fmt.Errorf("%w: %s", ErrInvalidFormat, encodedSig)
+	}
+	sig, err := base64.StdEncoding.DecodeString(encodedSig[1 : len(encodedSig)-1])
+	if err != nil {
+		return fmt.Errorf("%w: invalid base64: %s", ErrInvalidSignature, encodedSig)
+	}
+
+	components, params, err := parseSignatureInput(input)
+	if err != nil {
+		return err
+	}
+	if len(components) < 2 || components[0] != "@method" || components[1] != "@target-uri" {
+		return fmt.Errorf("%w: signature must cover @method and @target-uri", ErrInvalidFormat)
+	}
+
+	base, err := signatureBase(r, components, input)
+	if err != nil {
+		return err
+	}
+	if err := s.verify(r.Context(), []byte(base), sig); err != nil {
+		return err
+	}
+
+	if params.expires == 0 {
+		return fmt.Errorf("%w: missing expires parameter", ErrInvalidFormat)
+	}
+	if expiry := time.Unix(params.expires, 0); time.Now().After(expiry) {
+		return &ExpiredError{ExpiredAt: expiry}
+	}
+	return nil
+}
+
+// messageParams are the signature parameters of an HTTP message signature.
+type messageParams struct {
+	created int64
+	expires int64
+}
+
+// serialize serializes the covered components and parameters as an inner
+// list, the value of the @signature-params component.
+func (p messageParams) serialize(components []string) string {
+	quoted := make([]string, len(components))
+	for i, c := range components {
+		quoted[i] = sfString(c)
+	}
+	return fmt.Sprintf("(%s);created=%d;expires=%d", strings.Join(quoted, " "), p.created, p.expires)
+}
+
+// signatureBase constructs the signature base, the payload for signature
+// computation, from the covered components of the request.
+func signatureBase(r *http.Request, components []string, input string) (string, error) {
+	var b strings.Builder
+	for _, c := range components {
+		var value string
+		switch c {
+		case "@method":
+			value = r.Method
+		case "@target-uri":
+			value = requestURL(r).String()
+		default:
+			// trim a copy, lest the request's own headers are modified
+			values := r.Header.Values(c)
+			if len(values) == 0 {
+				return "", fmt.Errorf("%w: missing header: %s", ErrInvalidFormat, c)
+			}
+			trimmed := make([]string, len(values))
+			for i, v := range values {
+				trimmed[i] = strings.TrimSpace(v)
+			}
+			value = strings.Join(trimmed, ", ")
+		}
+		fmt.Fprintf(&b, "%s: %s\n", sfString(c), value)
+	}
+	fmt.Fprintf(&b, "%s: %s", sfString("@signature-params"), input)
+	return b.String(), nil
+}
+
+// parseSignatureInput parses the covered components and parameters from a
+// member of the Signature-Input header.
+func parseSignatureInput(input string) ([]string, messageParams, error) {
+	var params messageParams
+
+	list, rest, found := strings.Cut(input, ")")
+	if !found || !strings.HasPrefix(list, "(") {
+		return nil, params, fmt.Errorf("%w: %s", ErrInvalidFormat, input)
+	}
+	var components []string
+	for _, item := range strings.Fields(list[1:]) {
+		c, err := parseSFString(item)
+		if err != nil {
+			return nil, params, fmt.Errorf("%w: %s", ErrInvalidFormat, input)
+		}
+		components = append(components, c)
+	}
+
+	for _, param := range strings.Split(rest, ";")[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		switch k {
+		case "created", "expires":
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, params, fmt.Errorf("%w: %s", ErrInvalidFormat, input)
+			}
+			if k == "created" {
+				params.created = i
+			} else {
+				params.expires = i
+			}
+		}
+	}
+	return components, params, nil
+}
+
+// sfString serializes a component name as a structured field string, as
+// required by RFC 9421. The name must consist of printable ASCII characters;
+// see isSFStringContent.
+func sfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// parseSFString parses a structured field string, serialized by sfString.
+func parseSFString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("not a string: %s", s)
+	}
+	var b strings.Builder
+	for i := 1; i < len(s)-1; i++ {
+		c := s[i]
+		if c == '\\' {
+			i++
+			if i == len(s)-1 || (s[i] != '"' && s[i] != '\\') {
+				return "", fmt.Errorf("invalid escape: %s", s)
+			}
+			c = s[i]
+		} else if c == '"' || c < 0x20 || c > 0x7e {
+			return "", fmt.Errorf("invalid character: %s", s)
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// isSFStringContent reports whether the string consists only of the printable
+// ASCII characters permitted in a structured field string.
+func isSFStringContent(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// dictionaryMember retrieves the value of the member with the given key from a
+// structured field dictionary.
+func dictionaryMember(dict, key string) (string, error) {
+	if dict == "" {
+		return "", fmt.Errorf("%w: missing message signature", ErrInvalidFormat)
+	}
+	var (
+		start   int
+		inQuote bool
+		depth   int
+	)
+	for i := 0; i <= len(dict); i++ {
+		if i < len(dict) {
+			switch dict[i] {
+			case '"':
+				inQuote = !inQuote
+				continue
+			case '(':
+				if !inQuote {
+					depth++
+				}
+				continue
+			case ')':
+				if !inQuote {
+					depth--
+				}
+				continue
+			case ',':
+				if inQuote || depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		k, v, _ := strings.Cut(strings.TrimSpace(dict[start:i]), "=")
+		if k == key {
+			return v, nil
+		}
+		start = i + 1
+	}
+	return "", fmt.Errorf("%w: missing signature labelled %s", ErrInvalidFormat, key)
+}
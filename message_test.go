@@ -0,0 +1,129 @@
+package surl
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Message(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest("POST", "https://example.com/a/b/c?foo=bar", strings.NewReader(`{}`))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		r := newRequest()
+		err := signer.SignMessage(r, time.Now().Add(time.Minute), "Content-Type")
+		require.NoError(t, err)
+
+		assert.Regexp(t, `^sig1=\("@method" "@target-uri" "content-type"\);created=\d+;expires=\d+$`, r.Header.Get(SignatureInputHeader))
+		assert.Regexp(t, `^sig1=:.+:$`, r.Header.Get(SignatureHeader))
+
+		err = signer.VerifyMessage(r)
+		require.NoError(t, err)
+	})
+
+	t.Run("other signatures present", func(t *testing.T) {
+		r := newRequest()
+		err := signer.SignMessage(r, time.Now().Add(time.Minute), "Content-Type")
+		require.NoError(t, err)
+
+		r.Header.Set(SignatureInputHeader, `sig0=("@method" "@authority");created=1, `+r.Header.Get(SignatureInputHeader))
+		r.Header.Set(SignatureHeader, "sig0=:YWJj:, "+r.Header.Get(SignatureHeader))
+
+		err = signer.VerifyMessage(r)
+		require.NoError(t, err)
+	})
+
+	t.Run("headers not modified", func(t *testing.T) {
+		r := newRequest()
+		r.Header.Set("X-Padded", "  padded  ")
+		err := signer.SignMessage(r, time.Now().Add(time.Minute), "X-Padded")
+		require.NoError(t, err)
+
+		assert.Equal(t, "  padded  ", r.Header.Get("X-Padded"))
+	})
+
+	t.Run("invalid header name", func(t *testing.T) {
+		err := signer.SignMessage(newRequest(), time.Now().Add(time.Minute), "Contént-Type")
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	tests := []struct {
+		name   string
+		tamper func(r *http.Request)
+		want   error
+	}{
+		{
+			name:   "method changed",
+			tamper: func(r *http.Request) { r.Method = "PUT" },
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "target uri changed",
+			tamper: func(r *http.Request) { r.URL.RawQuery = "foo=baz" },
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "covered header changed",
+			tamper: func(r *http.Request) { r.Header.Set("Content-Type", "text/plain") },
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "covered header removed",
+			tamper: func(r *http.Request) { r.Header.Del("Content-Type") },
+			want:   ErrInvalidFormat,
+		},
+		{
+			name:   "signature removed",
+			tamper: func(r *http.Request) { r.Header.Del(SignatureHeader) },
+			want:   ErrInvalidFormat,
+		},
+		{
+			name: "expired",
+			want: ErrExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest()
+			expiry := time.Now().Add(time.Minute)
+			if tt.want == ErrExpired {
+				expiry = time.Now().Add(-time.Minute)
+			}
+			err := signer.SignMessage(r, expiry, "Content-Type")
+			require.NoError(t, err)
+
+			if tt.tamper != nil {
+				tt.tamper(r)
+			}
+			err = signer.VerifyMessage(r)
+			assert.Truef(t, errors.Is(err, tt.want), "got error: %v", err)
+		})
+	}
+}
+
+func TestSFString(t *testing.T) {
+	for _, s := range []string{"content-type", `a"b`, `a\b`, "@signature-params"} {
+		serialized := sfString(s)
+		parsed, err := parseSFString(serialized)
+		require.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+	assert.Equal(t, `"a\"b\\c"`, sfString(`a"b\c`))
+
+	for _, s := range []string{`"a\nb"`, `"a"b"`, `"a\"`, `abc`, "\"\x01\""} {
+		_, err := parseSFString(s)
+		assert.Error(t, err, s)
+	}
+}
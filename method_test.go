@@ -0,0 +1,78 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignForMethods(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignForMethods("https://example.com/uploads/a.txt", time.Now().Add(time.Minute), "put", "POST")
+	require.NoError(t, err)
+	assert.Contains(t, signed, "signature_methods=PUT%2CPOST")
+
+	assert.NoError(t, signer.VerifyRequest(httptest.NewRequest("PUT", signed, nil)))
+	assert.NoError(t, signer.VerifyRequest(httptest.NewRequest("POST", signed, nil)))
+	assert.ErrorIs(t, signer.VerifyRequest(httptest.NewRequest("GET", signed, nil)), ErrMethodNotAllowed)
+
+	// no request
+	assert.NoError(t, signer.Verify(signed))
+
+	t.Run("tampered", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(methodsParam, "GET")
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.VerifyRequest(httptest.NewRequest("GET", u.String(), nil)), ErrInvalidSignature)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Del(methodsParam)
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.VerifyRequest(httptest.NewRequest("GET", u.String(), nil)), ErrInvalidSignature)
+	})
+
+	t.Run("no methods", func(t *testing.T) {
+		_, err := signer.SignForMethods("https://example.com/uploads/a.txt", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrMethodNotAllowed)
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		_, err := signer.SignForMethods("https://example.com/uploads/a.txt", time.Now().Add(time.Minute), "GET,PUT")
+		assert.ErrorIs(t, err, ErrMethodNotAllowed)
+	})
+
+	t.Run("nonce not consumed", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNonceStore(&MemoryNonceStore{}))
+		signed, err := signer.SignForMethods("https://example.com/uploads/a.txt", time.Now().Add(time.Minute), "PUT")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.VerifyRequest(httptest.NewRequest("GET", signed, nil)), ErrMethodNotAllowed)
+		assert.NoError(t, signer.VerifyRequest(httptest.NewRequest("PUT", signed, nil)))
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		handler := signer.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("PUT", signed, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,166 @@
+package surl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MiddlewareOption configures the middleware returned by Signer.Middleware.
+type MiddlewareOption func(*middleware)
+
+// ExpiredStatus sets the status code of responses to requests with signed
+// URLs that have a valid signature but have expired. The default is 410 Gone.
+func ExpiredStatus(code int) MiddlewareOption {
+	return func(m *middleware) {
+		m.expiredStatus = code
+	}
+}
+
+// InvalidStatus sets the status code of responses to requests that fail
+// verification for any reason other than expiry, e.g. ErrInvalidSignature.
+// The default is 403 Forbidden.
+func InvalidStatus(code int) MiddlewareOption {
+	return func(m *middleware) {
+		m.invalidStatus = code
+	}
+}
+
+// OnExpired responds to requests with signed URLs that have a valid signature
+// but have expired, in place of the expired status code, e.g. ExpiredPage.
+func OnExpired(h ExpiredHandler) MiddlewareOption {
+	return func(m *middleware) {
+		m.expired = h
+	}
+}
+
+// StripSignature rewrites the URL of requests to the URL as it was before it
+// was signed, removing the signature and expiry, and any other parameters added
+// when signing, before passing them to the next handler, so that routing and
+// caching downstream see the unsigned URL. The prefix that matched, if any, is
+// retained.
+func StripSignature() MiddlewareOption {
+	return func(m *middleware) {
+		m.strip = true
+	}
+}
+
+// BindClient verifies requests with Signer.VerifyForRequest rather than
+// Signer.VerifyRequest, only passing requests with URLs signed with
+// SignForClient, from the client to which they were bound, to the next
+// handler.
+func BindClient() MiddlewareOption {
+	return func(m *middleware) {
+		m.bindClient = true
+	}
+}
+
+// RequireBinding verifies requests with Signer.VerifyRequestWithBinding
+// rather than Signer.VerifyRequest, only passing requests with URLs signed
+// with SignWithBinding, bound to the value retrieved from the request by the
+// function, to the next handler.
+func RequireBinding(fn BindingFunc) MiddlewareOption {
+	return func(m *middleware) {
+		m.binding = fn
+	}
+}
+
+// Skipper passes requests for which the function returns true directly to the
+// next handler without verifying them, e.g. to exempt health checks or
+// particular routes.
+func Skipper(skip func(r *http.Request) bool) MiddlewareOption {
+	return func(m *middleware) {
+		m.skip = skip
+	}
+}
+
+// middleware verifies requests before passing them to the next handler.
+type middleware struct {
+	signer *Signer
+	next   http.Handler
+
+	expiredStatus     int
+	invalidStatus     int
+	expired           ExpiredHandler
+	strip             bool
+	bindClient        bool
+	binding           BindingFunc
+	checkDigest       bool
+	digestUploadLimit int64
+	skip              func(r *http.Request) bool
+	rollingWindow     time.Duration
+	rollingTTL        time.Duration
+	renewalHeader     string
+}
+
+// Middleware returns a handler that only passes requests with valid,
+// unexpired, signed URLs to the next handler. Requests that fail verification
+// receive a 403 Forbidden response, or a 410 Gone response if the URL has
+// expired, unless configured otherwise with options. The result of
+// verification is available to the next handler via ResultFromContext.
+func (s *Signer) Middleware(next http.Handler, opts ...MiddlewareOption) http.Handler {
+	m := &middleware{
+		signer:            s,
+		next:              next,
+		expiredStatus:     http.StatusGone,
+		invalidStatus:     http.StatusForbidden,
+		digestUploadLimit: DefaultDigestUploadLimit,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.skip != nil && m.skip(r) {
+		m.next.ServeHTTP(w, r)
+		return
+	}
+	verify := m.signer.verifyRequest
+	if m.bindClient {
+		verify = m.signer.verifyClientRequest
+	}
+	if m.binding != nil {
+		verify = func(r *http.Request) (*Result, error) {
+			return m.signer.verifyBoundRequest(r, m.binding)
+		}
+	}
+	result, err := verify(r)
+	if errors.Is(err, ErrExpired) {
+		if m.expired != nil {
+			m.expired(w, r, result)
+			return
+		}
+		http.Error(w, err.Error(), m.expiredStatus)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), m.invalidStatus)
+		return
+	}
+	if m.roll(w, r, result) {
+		return
+	}
+	r2 := r.WithContext(newContext(r.Context(), result))
+	if m.strip {
+		prefix, _ := m.signer.matchPrefix(r.URL.Path)
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = prefix + result.OriginalURL.Path
+		r2.URL.RawPath = ""
+		if result.OriginalURL.RawPath != "" {
+			r2.URL.RawPath = prefix + result.OriginalURL.RawPath
+		}
+		r2.URL.RawQuery = result.OriginalURL.RawQuery
+		r2.RequestURI = r2.URL.RequestURI()
+	}
+	if result.Headers != nil {
+		result.Headers.set(w)
+	}
+	if m.checkDigest && result.Digest != nil {
+		serveDigest(m.next, w, r2, result.Digest, m.digestUploadLimit)
+		return
+	}
+	m.next.ServeHTTP(w, r2)
+}
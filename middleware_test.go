@@ -0,0 +1,131 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	signer := New([]byte("abc123"))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok := ResultFromContext(r.Context())
+		require.True(t, ok)
+		w.Write([]byte(result.OriginalURL.Path))
+	})
+
+	valid, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	expired, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	tampered := valid + "&foo=bar"
+
+	tests := []struct {
+		name   string
+		opts   []MiddlewareOption
+		target string
+		want   int
+		body   string
+	}{
+		{
+			name:   "valid",
+			target: valid,
+			want:   http.StatusOK,
+			body:   "/a/b/c",
+		},
+		{
+			name:   "expired",
+			target: expired,
+			want:   http.StatusGone,
+		},
+		{
+			name:   "tampered",
+			target: tampered,
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "expired status",
+			opts:   []MiddlewareOption{ExpiredStatus(http.StatusUnauthorized)},
+			target: expired,
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid status",
+			opts:   []MiddlewareOption{InvalidStatus(http.StatusNotFound)},
+			target: tampered,
+			want:   http.StatusNotFound,
+		},
+		{
+			name:   "expired handler",
+			opts:   []MiddlewareOption{OnExpired(ExpiredPage(nil))},
+			target: expired,
+			want:   http.StatusGone,
+			body:   "Link expired",
+		},
+		{
+			name: "not skipped",
+			opts: []MiddlewareOption{Skipper(func(r *http.Request) bool {
+				return r.URL.Path == "/healthz"
+			})},
+			target: tampered,
+			want:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.target, nil)
+
+			signer.Middleware(next, tt.opts...).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+
+	t.Run("skipped", func(t *testing.T) {
+		var called bool
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, ok := ResultFromContext(r.Context())
+			assert.False(t, ok)
+			called = true
+		})
+		skipper := Skipper(func(r *http.Request) bool {
+			return r.URL.Path == "/healthz"
+		})
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "https://example.com/healthz", nil)
+
+		signer.Middleware(next, skipper).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, called)
+	})
+}
+
+func TestMiddleware_StripSignature(t *testing.T) {
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), f.formatter, PrefixPath("/signed"))
+			signed, err := signer.Sign("https://example.com/a/b%2Fc?foo=bar&baz=qux", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			var got *http.Request
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			})
+			w := httptest.NewRecorder()
+			signer.Middleware(next, StripSignature()).ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "/signed/a/b/c", got.URL.Path)
+			assert.Equal(t, "/signed/a/b%2Fc", got.URL.EscapedPath())
+			assert.Equal(t, "foo=bar&baz=qux", got.URL.RawQuery)
+			assert.Equal(t, "/signed/a/b%2Fc?foo=bar&baz=qux", got.RequestURI)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package mobile wraps Signer in types that gomobile can bind, for signing and
+// verifying URLs in iOS and Android apps, e.g. to generate share links
+// offline. Bind it with:
+//
+//	gomobile bind -target=android github.com/leg100/surl/v2/mobile
+//	gomobile bind -target=ios github.com/leg100/surl/v2/mobile
+//
+// Options are set on an Options struct rather than passed as functional
+// options, and expiries are seconds since the Unix epoch.
+package mobile
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+// Options configures a Signer. The zero value of each field selects the
+// default.
+type Options struct {
+	// Algorithm is the signature algorithm: blake2b-256 (the default),
+	// hmac-sha256 or hmac-sha512.
+	Algorithm string
+	// Formatter is the format of signed URLs: query (the default),
+	// short-query, path, compact, suffix or host.
+	Formatter string
+	// ExpiryEncoding is the encoding of expiries: decimal (the default),
+	// base58, base64 or timestamp.
+	ExpiryEncoding string
+	// Prefix is the path prefix of signed URLs.
+	Prefix string
+	// Purpose is the purpose from which the key is derived.
+	Purpose        string
+	SkipQuery      bool
+	SkipScheme     bool
+	SkipHost       bool
+	SelfDescribing bool
+}
+
+// NewOptions constructs options with the defaults.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// signerOptions converts the options to signer options.
+func (o *Options) signerOptions() ([]surl.Option, error) {
+	var opts []surl.Option
+	switch o.Algorithm {
+	case "", "blake2b-256":
+	case "hmac-sha256":
+		opts = append(opts, surl.WithHMACSHA256())
+	case "hmac-sha512":
+		opts = append(opts, surl.WithHMACSHA512())
+	default:
+		return nil, fmt.Errorf("unknown algorithm: %s", o.Algorithm)
+	}
+	if o.Purpose != "" {
+		opts = append(opts, surl.WithPurpose(o.Purpose))
+	}
+	switch o.Formatter {
+	case "", "query":
+	case "short-query":
+		opts = append(opts, surl.WithShortQueryFormatter())
+	case "path":
+		opts = append(opts, surl.WithPathFormatter())
+	case "compact":
+		opts = append(opts, surl.WithCompactFormatter())
+	case "suffix":
+		opts = append(opts, surl.WithSuffixFormatter())
+	case "host":
+		opts = append(opts, surl.WithHostFormatter())
+	default:
+		return nil, fmt.Errorf("unknown formatter: %s", o.Formatter)
+	}
+	switch o.ExpiryEncoding {
+	case "", "decimal":
+	case "base58":
+		opts = append(opts, surl.WithBase58Expiry())
+	case "base64":
+		opts = append(opts, surl.WithBase64Expiry())
+	case "timestamp":
+		opts = append(opts, surl.WithTimestampExpiry())
+	default:
+		return nil, fmt.Errorf("unknown expiry encoding: %s", o.ExpiryEncoding)
+	}
+	if o.Prefix != "" {
+		opts = append(opts, surl.PrefixPath(o.Prefix))
+	}
+	if o.SkipQuery {
+		opts = append(opts, surl.SkipQuery())
+	}
+	if o.SkipScheme {
+		opts = append(opts, surl.SkipScheme())
+	}
+	if o.SkipHost {
+		opts = append(opts, surl.SkipHost())
+	}
+	if o.SelfDescribing {
+		opts = append(opts, surl.SelfDescribing())
+	}
+	return opts, nil
+}
+
+// Signer signs and verifies URLs.
+type Signer struct {
+	signer *surl.Signer
+}
+
+// NewSigner constructs a signer with the key and options. Options may be nil,
+// selecting the defaults.
+func NewSigner(key []byte, options *Options) (*Signer, error) {
+	if len(key) == 0 {
+		return nil, errors.New("empty key")
+	}
+	if options == nil {
+		options = NewOptions()
+	}
+	opts, err := options.signerOptions()
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{signer: surl.New(key, opts...)}, nil
+}
+
+// Sign signs the URL, which expires at the given number of seconds since the
+// Unix epoch.
+func (s *Signer) Sign(unsigned string, expiry int64) (string, error) {
+	return s.signer.Sign(unsigned, time.Unix(expiry, 0))
+}
+
+// SignFor signs the URL, which expires after the given number of seconds.
+func (s *Signer) SignFor(unsigned string, seconds int64) (string, error) {
+	return s.signer.Sign(unsigned, time.Now().Add(time.Duration(seconds)*time.Second))
+}
+
+// Verify verifies the signed URL, returning an error if it is invalid or has
+// expired.
+func (s *Signer) Verify(signed string) error {
+	return s.signer.Verify(signed)
+}
@@ -0,0 +1,49 @@
+package mobile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	options := NewOptions()
+	options.Formatter = "path"
+	options.Purpose = "share"
+	signer, err := NewSigner([]byte("abc123"), options)
+	require.NoError(t, err)
+
+	signed, err := signer.SignFor("https://example.com/a/b/c", 60)
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(signed))
+
+	// compatible with the equivalent signer
+	compatible := surl.New([]byte("abc123"), surl.WithPathFormatter(), surl.WithPurpose("share"))
+	assert.NoError(t, compatible.Verify(signed))
+
+	expired, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute).Unix())
+	require.NoError(t, err)
+	assert.ErrorIs(t, signer.Verify(expired), surl.ErrExpired)
+
+	t.Run("default options", func(t *testing.T) {
+		signer, err := NewSigner([]byte("abc123"), nil)
+		require.NoError(t, err)
+
+		signed, err := signer.SignFor("https://example.com/a/b/c", 60)
+		require.NoError(t, err)
+		assert.NoError(t, surl.New([]byte("abc123")).Verify(signed))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewSigner([]byte("abc123"), &Options{Formatter: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		_, err := NewSigner(nil, nil)
+		assert.Error(t, err)
+	})
+}
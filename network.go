@@ -0,0 +1,63 @@
+package surl
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// networkParam is the query parameter that carries the network to which a
+// signed URL is bound.
+const networkParam = "signature_network"
+
+// SignForNetwork is like SignForClient but binds the signed URL to a network,
+// e.g. an office /24 or a VPC range, so that it is valid for requests from any
+// client in the network, which suits clients whose address changes, such as
+// those behind carrier-grade NAT. The network is added to the URL and covered
+// by the signature. Verify requests with VerifyForRequest, which checks the
+// client, which must be identified by an IP address, is in the network.
+func (s *Signer) SignForNetwork(unsigned string, expiry time.Time, network netip.Prefix) (string, error) {
+	if !network.IsValid() {
+		return "", fmt.Errorf("%w: invalid network", ErrInvalidClient)
+	}
+	network = network.Masked()
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	if err := s.signURL(context.Background(), u, expiry, networkBinding(network)); err != nil {
+		return "", err
+	}
+	appendQueryParam(u, networkParam, network.String())
+	return u.String(), nil
+}
+
+// networkBinding binds a network to a signature.
+func networkBinding(network netip.Prefix) string {
+	return "network:" + network.String()
+}
+
+// clientNetworkBinding returns the binding with which to verify a URL for a
+// client. If the URL is bound to a network then the client must be in the
+// network; otherwise the URL must be bound to the client.
+func clientNetworkBinding(u *url.URL, client string) (string, error) {
+	stripped := *u
+	param, err := removeQueryParam(&stripped, networkParam)
+	if err != nil || param == "" {
+		return clientBinding(client), err
+	}
+	network, err := netip.ParsePrefix(param)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid network: %s", ErrInvalidFormat, param)
+	}
+	addr, err := netip.ParseAddr(client)
+	if err != nil {
+		return "", fmt.Errorf("%w: not an IP address: %s", ErrInvalidClient, client)
+	}
+	if !network.Contains(addr.Unmap().WithZone("")) {
+		return "", fmt.Errorf("%w: %s not in %s", ErrInvalidClient, client, network)
+	}
+	return networkBinding(network), nil
+}
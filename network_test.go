@@ -0,0 +1,57 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignForNetwork(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignForNetwork("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute), netip.MustParsePrefix("192.0.2.17/24"))
+	require.NoError(t, err)
+	assert.Contains(t, signed, "signature_network=192.0.2.0%2F24")
+
+	request := func(signed, remoteAddr string) *http.Request {
+		r := httptest.NewRequest("GET", signed, nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	assert.NoError(t, signer.VerifyForRequest(request(signed, "192.0.2.1:1234")))
+	assert.NoError(t, signer.VerifyForRequest(request(signed, "192.0.2.254:1234")))
+	assert.NoError(t, signer.VerifyForRequest(request(signed, "[::ffff:192.0.2.1]:1234")))
+	assert.ErrorIs(t, signer.VerifyForRequest(request(signed, "198.51.100.1:1234")), ErrInvalidClient)
+
+	// not valid without the client
+	assert.ErrorIs(t, signer.Verify(signed), ErrInvalidSignature)
+
+	t.Run("widened", func(t *testing.T) {
+		widened, err := signer.SignForNetwork("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute), netip.MustParsePrefix("192.0.2.0/24"))
+		require.NoError(t, err)
+		r := request(widened, "198.51.100.1:1234")
+		q := r.URL.Query()
+		q.Set(networkParam, "0.0.0.0/0")
+		r.URL.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.VerifyForRequest(r), ErrInvalidSignature)
+	})
+
+	t.Run("invalid network", func(t *testing.T) {
+		_, err := signer.SignForNetwork("https://example.com/a/b/c", time.Now().Add(time.Minute), netip.Prefix{})
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("client not an address", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithClientFunc(func(*http.Request) (string, error) {
+			return "device-1", nil
+		}))
+		assert.ErrorIs(t, signer.VerifyForRequest(request(signed, "192.0.2.1:1234")), ErrInvalidClient)
+	})
+}
@@ -0,0 +1,140 @@
+package surl
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUsed is returned when a single-use signed URL has already been used.
+	ErrUsed = errors.New("URL has already been used")
+	// ErrNonceStoreFull is returned by MemoryNonceStore when it holds its
+	// maximum number of unexpired nonces.
+	ErrNonceStoreFull = errors.New("nonce store is full")
+)
+
+// NonceStore records the nonces of single-use signed URLs that have been
+// used.
+type NonceStore interface {
+	// Consume records that the nonce has been used, returning ErrUsed if it
+	// has already been used. It must do so atomically, so that concurrent
+	// requests cannot both use the same nonce. The nonce need only be
+	// retained until it expires, after which its URL is rejected anyway, or
+	// indefinitely if expiresAt is zero, for a URL that never expires.
+	Consume(ctx context.Context, nonce string, expiresAt time.Time) error
+}
+
+// WithNonceStore instructs Signer to sign single-use URLs, which can only be
+// verified once, e.g. for password reset and invite links. A random nonce is
+// added to every URL it signs, covered by the signature, and upon successful
+// verification the nonce is consumed in the store, failing verification with
+// ErrUsed should it have already been consumed. The nonce is the same as the
+// ID returned by URLID.
+//
+// Only Verify, VerifyContext, VerifyURL, VerifyRequest and the handlers and
+// middleware in this package consume nonces; VerifyAt and VerifyIgnoreExpiry
+// do not. URLs without a nonce, e.g. those signed before the option was added,
+// are not single-use. Errors from the store fail verification.
+func WithNonceStore(store NonceStore) Option {
+	return func(s *Signer) {
+		s.nonces = store
+	}
+}
+
+// consumeNonce consumes the nonce of the verified URL.
+func (s *Signer) consumeNonce(ctx context.Context, result *Result) error {
+	if s.nonces == nil || result.ID == "" {
+		return nil
+	}
+	if err := s.nonces.Consume(ctx, result.ID, result.ExpiresAt); err != nil {
+		if errors.Is(err, ErrUsed) {
+			return err
+		}
+		return fmt.Errorf("consuming nonce: %w", err)
+	}
+	return nil
+}
+
+// MemoryNonceStore is an in-memory NonceStore, suitable for a single instance
+// and for testing. Nonces are evicted once their URLs expire. The zero value
+// is ready to use.
+type MemoryNonceStore struct {
+	// MaxSize, if non-zero, is the maximum number of unexpired nonces
+	// retained. Rather than evict unexpired nonces, which would permit their
+	// URLs to be used again, ErrNonceStoreFull is returned when the store is
+	// full, failing verification until nonces expire.
+	MaxSize int
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	expiry nonceHeap
+	now    func() time.Time
+}
+
+// Consume implements NonceStore.
+func (m *MemoryNonceStore) Consume(ctx context.Context, nonce string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.nonces == nil {
+		m.nonces = make(map[string]time.Time)
+	}
+	m.evict()
+	if _, ok := m.nonces[nonce]; ok {
+		return ErrUsed
+	}
+	if m.MaxSize > 0 && len(m.nonces) >= m.MaxSize {
+		return ErrNonceStoreFull
+	}
+	m.nonces[nonce] = expiresAt
+	if !expiresAt.IsZero() {
+		heap.Push(&m.expiry, nonceEntry{nonce: nonce, expiresAt: expiresAt})
+	}
+	return nil
+}
+
+// Len returns the number of unexpired nonces in the store.
+func (m *MemoryNonceStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evict()
+	return len(m.nonces)
+}
+
+// evict removes expired nonces.
+func (m *MemoryNonceStore) evict() {
+	now := time.Now()
+	if m.now != nil {
+		now = m.now()
+	}
+	for len(m.expiry) > 0 && now.After(m.expiry[0].expiresAt) {
+		entry := heap.Pop(&m.expiry).(nonceEntry)
+		delete(m.nonces, entry.nonce)
+	}
+}
+
+// nonceEntry is a nonce along with the expiry of its URL.
+type nonceEntry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+// nonceHeap is a min-heap of nonces ordered by expiry.
+type nonceHeap []nonceEntry
+
+func (h nonceHeap) Len() int           { return len(h) }
+func (h nonceHeap) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h nonceHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nonceHeap) Push(x any)        { *h = append(*h, x.(nonceEntry)) }
+
+func (h *nonceHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,126 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNonceStore is a NonceStore that records nonces in a map.
+type fakeNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	err    error
+}
+
+func (f *fakeNonceStore) Consume(ctx context.Context, nonce string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.nonces[nonce]; ok {
+		return ErrUsed
+	}
+	if f.nonces == nil {
+		f.nonces = make(map[string]time.Time)
+	}
+	f.nonces[nonce] = expiresAt
+	return nil
+}
+
+func TestWithNonceStore(t *testing.T) {
+	store := &fakeNonceStore{}
+	signer := New([]byte("abc123"), WithNonceStore(store))
+	expiry := time.Now().Add(time.Minute)
+
+	signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+
+	// not consumed
+	assert.NoError(t, signer.VerifyIgnoreExpiry(signed))
+	assert.NoError(t, signer.VerifyAt(signed, time.Now()))
+
+	assert.NoError(t, signer.Verify(signed))
+	assert.ErrorIs(t, signer.Verify(signed), ErrUsed)
+
+	id, err := URLID(signed)
+	require.NoError(t, err)
+	assert.Equal(t, expiry.Truncate(time.Second), store.nonces[id].Truncate(time.Second))
+
+	t.Run("distinct nonces", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("expired not consumed", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+		id, err := URLID(signed)
+		require.NoError(t, err)
+		assert.NotContains(t, store.nonces, id)
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+		handler := signer.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		unavailable := errors.New("store unavailable")
+		signer := New([]byte("abc123"), WithNonceStore(&fakeNonceStore{err: unavailable}))
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(signed), unavailable)
+	})
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := &MemoryNonceStore{MaxSize: 2, now: func() time.Time { return now }}
+
+	require.NoError(t, store.Consume(ctx, "a", now.Add(time.Minute)))
+	assert.ErrorIs(t, store.Consume(ctx, "a", now.Add(time.Minute)), ErrUsed)
+
+	require.NoError(t, store.Consume(ctx, "b", now.Add(time.Hour)))
+	assert.ErrorIs(t, store.Consume(ctx, "c", now.Add(time.Hour)), ErrNonceStoreFull)
+	assert.Equal(t, 2, store.Len())
+
+	// a expires, making room for c
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 1, store.Len())
+	require.NoError(t, store.Consume(ctx, "c", now.Add(time.Hour)))
+	assert.ErrorIs(t, store.Consume(ctx, "b", now.Add(time.Hour)), ErrUsed)
+
+	t.Run("signer", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNonceStore(&MemoryNonceStore{}))
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		assert.ErrorIs(t, signer.Verify(signed), ErrUsed)
+	})
+}
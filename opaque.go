@@ -0,0 +1,108 @@
+package surl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OpaquePath is the path under which opaque URLs are issued, followed by the
+// token.
+const OpaquePath = "/t/"
+
+// opaquePurpose distinguishes tokens produced by SignOpaque from other tokens.
+const opaquePurpose = "opaque"
+
+// errNoEncryptionKey is returned when encrypting with a signer without a key.
+var errNoEncryptionKey = errors.New("signer has no key from which to derive an encryption key")
+
+// SignOpaque signs a URL, encrypting its path and query into a single opaque
+// token, e.g. https://example.com/t/<token>, hiding resource identifiers from
+// the holder of the URL. The original URL is recovered, once the token has
+// been verified and decrypted, with VerifyOpaque or OpaqueHandler. The token
+// expires at the given time.
+//
+// The path and query are encrypted with XChaCha20-Poly1305, using a key
+// derived from the signer's key, and so SignOpaque fails with a signer
+// constructed with NewFromSignFunc, NewFromCryptoSigner or NewFromFile.
+func (s *Signer) SignOpaque(unsigned string, expiry time.Time) (string, error) {
+	if s.key == nil {
+		return "", errNoEncryptionKey
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := encryptData(s.key, []byte(u.RequestURI()))
+	if err != nil {
+		return "", err
+	}
+	token, err := s.signToken(opaquePurpose, ciphertext, expiry)
+	if err != nil {
+		return "", err
+	}
+	opaque := url.URL{Scheme: u.Scheme, Host: u.Host, Path: OpaquePath + token}
+	return opaque.String(), nil
+}
+
+// VerifyOpaque verifies an opaque URL signed with SignOpaque, returning the
+// original URL.
+func (s *Signer) VerifyOpaque(signed string) (*url.URL, error) {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return nil, err
+	}
+	return s.verifyOpaque(u)
+}
+
+// verifyOpaque verifies an opaque URL, returning the original URL.
+func (s *Signer) verifyOpaque(u *url.URL) (*url.URL, error) {
+	token, ok := strings.CutPrefix(u.Path, OpaquePath)
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+	ciphertext, err := s.verifyToken(opaquePurpose, token)
+	if err != nil {
+		return nil, err
+	}
+	requestURI, err := s.decryptData(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	original, err := url.ParseRequestURI(string(requestURI))
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	original.Scheme = u.Scheme
+	original.Host = u.Host
+	return original, nil
+}
+
+// OpaqueHandler returns a handler that verifies requests for opaque URLs,
+// rewriting the URL of each request to the original URL before passing it to
+// the next handler, so that it is routed as if the original URL had been
+// requested. Requests that fail verification receive a 403 Forbidden
+// response, or a 410 Gone response if the URL has expired. Register it under
+// OpaquePath:
+//
+//	http.Handle(surl.OpaquePath, signer.OpaqueHandler(mux))
+func (s *Signer) OpaqueHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		original, err := s.verifyOpaque(r.URL)
+		if errors.Is(err, ErrExpired) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = original.Path
+		r2.URL.RawPath = original.RawPath
+		r2.URL.RawQuery = original.RawQuery
+		r2.RequestURI = original.RequestURI()
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,93 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignOpaque(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignOpaque("https://example.com/files/secret-report.pdf?user=bob", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "https://example.com/t/"))
+	assert.NotContains(t, signed, "secret-report")
+	assert.NotContains(t, signed, "bob")
+
+	original, err := signer.VerifyOpaque(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/files/secret-report.pdf?user=bob", original.String())
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignOpaque("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyOpaque(signed)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := New([]byte("xyz789")).VerifyOpaque(signed)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("not opaque", func(t *testing.T) {
+		_, err := signer.VerifyOpaque("https://example.com/a/b/c")
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		// an ordinary token is not accepted as an opaque URL
+		token, err := signer.SignBytes([]byte("/a/b/c"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyOpaque("https://example.com/t/" + token)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		signer := NewFromSignFunc(func(data []byte) ([]byte, error) { return data, nil })
+		_, err := signer.SignOpaque("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.Error(t, err)
+	})
+}
+
+func TestOpaqueHandler(t *testing.T) {
+	signer := New([]byte("abc123"))
+	var got *http.Request
+	handler := signer.OpaqueHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+
+	signed, err := signer.SignOpaque("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, got)
+	assert.Equal(t, "/a/b/c", got.URL.Path)
+	assert.Equal(t, "bar", got.URL.Query().Get("foo"))
+	assert.Equal(t, "/a/b/c?foo=bar", got.RequestURI)
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignOpaque("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusGone, w.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "https://example.com/t/foo.bar.baz", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,28 @@
+package surl
+
+// override is a break-glass key whose signatures are accepted in addition to
+// those of the signer's key, subject to auditing.
+type override struct {
+	key   []byte
+	alg   algorithm
+	audit func(*Result) error
+}
+
+// WithOverrideKey instructs Signer to also accept URLs signed with a separate
+// break-glass override key, e.g. for support engineers to mint emergency
+// access links using a Signer constructed with the override key. The audit
+// function is called upon every successful verification of such a URL, with a
+// result whose Override field is true. If the audit function returns an error
+// then verification fails, ensuring no override goes unrecorded.
+//
+// Override links are revoked independently of normal links by removing or
+// replacing the override key.
+func WithOverrideKey(key []byte, audit func(*Result) error) Option {
+	return func(s *Signer) {
+		s.override = &override{
+			key:   key,
+			alg:   s.keyedHashFor(key),
+			audit: audit,
+		}
+	}
+}
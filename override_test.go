@@ -0,0 +1,74 @@
+package surl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_OverrideKey(t *testing.T) {
+	var audited []*Result
+	audit := func(result *Result) error {
+		audited = append(audited, result)
+		return nil
+	}
+	signer := New([]byte("abc123"), WithOverrideKey([]byte("break-glass"), audit))
+	support := New([]byte("break-glass"))
+
+	t.Run("normal link is not audited", func(t *testing.T) {
+		audited = nil
+
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = signer.Verify(signed)
+		require.NoError(t, err)
+		assert.Empty(t, audited)
+	})
+
+	t.Run("override link is audited", func(t *testing.T) {
+		audited = nil
+
+		signed, err := support.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = signer.Verify(signed)
+		require.NoError(t, err)
+		if assert.Len(t, audited, 1) {
+			assert.True(t, audited[0].Override)
+			assert.Equal(t, "/a/b/c", audited[0].OriginalURL.Path)
+		}
+	})
+
+	t.Run("override link is not extended", func(t *testing.T) {
+		signed, err := support.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.Extend(signed, time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, ErrOverrideNotRenewable)
+	})
+
+	t.Run("failed audit", func(t *testing.T) {
+		auditErr := errors.New("audit log unavailable")
+		signer := New([]byte("abc123"), WithOverrideKey([]byte("break-glass"), func(*Result) error {
+			return auditErr
+		}))
+
+		signed, err := support.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = signer.Verify(signed)
+		assert.ErrorIs(t, err, auditErr)
+	})
+
+	t.Run("override key revoked", func(t *testing.T) {
+		signed, err := support.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		err = New([]byte("abc123"), WithOverrideKey([]byte("new-break-glass"), audit)).Verify(signed)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+}
@@ -8,7 +8,7 @@ import (
 type pathFormatter struct{}
 
 func (f *pathFormatter) addExpiry(unsigned *url.URL, expiry string) {
-	unsigned.Path = expiry + unsigned.Path
+	prependPath(unsigned, expiry)
 }
 
 func (f *pathFormatter) buildPayload(u url.URL, opts payloadOptions) string {
@@ -18,35 +18,62 @@ func (f *pathFormatter) buildPayload(u url.URL, opts payloadOptions) string {
 	if opts.skipScheme {
 		u.Scheme = ""
 	}
+	if opts.skipHost {
+		u.Host = ""
+	}
 	return u.String()
 }
 
 func (f *pathFormatter) addSignature(payload *url.URL, sig string) {
-	payload.Path = "/" + sig + "." + payload.Path
+	prependPath(payload, "/"+sig+".")
 }
 
 func (f *pathFormatter) extractSignature(u *url.URL) (string, error) {
 	// prise apart sig and payload
-	sig, payload, found := strings.Cut(u.Path, ".")
-	if !found {
+	sig, found := cutPath(u, ".")
+	if !found || sig == "" {
 		return "", ErrInvalidFormat
 	}
 	// remove leading /
-	sig = sig[1:]
-
-	u.Path = payload
-
-	return sig, nil
+	return sig[1:], nil
 }
 
 func (*pathFormatter) extractExpiry(u *url.URL) (string, error) {
 	// prise apart expiry and data
-	expiry, path, found := strings.Cut(u.Path, "/")
+	expiry, found := cutPath(u, "/")
 	if !found {
 		return "", ErrInvalidFormat
 	}
 	// add leading slash back to path
-	u.Path = "/" + path
+	prependPath(u, "/")
 
 	return expiry, nil
 }
+
+// prependPath prepends a string, which must not require escaping, to the path
+// of a URL, maintaining its encoded form.
+func prependPath(u *url.URL, s string) {
+	u.Path = s + u.Path
+	if u.RawPath != "" {
+		u.RawPath = s + u.RawPath
+	}
+}
+
+// cutPath slices the path of a URL, and its encoded form, around the first
+// instance of sep, which must not require escaping, returning the text before
+// sep and leaving the text after sep in the path.
+func cutPath(u *url.URL, sep string) (string, bool) {
+	before, after, found := strings.Cut(u.Path, sep)
+	if !found {
+		return "", false
+	}
+	u.Path = after
+	if u.RawPath != "" {
+		if _, rawAfter, found := strings.Cut(u.RawPath, sep); found {
+			u.RawPath = rawAfter
+		} else {
+			u.RawPath = ""
+		}
+	}
+	return before, true
+}
@@ -0,0 +1,214 @@
+package surl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// policyParam is the query parameter that carries a signed policy.
+const policyParam = "signature_policy"
+
+// policyPurpose distinguishes policy tokens from other tokens.
+const policyPurpose = "policy"
+
+// Policy is a policy document granting access to the URLs matching a
+// resource pattern, subject to conditions, in the manner of a CloudFront
+// custom policy.
+type Policy struct {
+	// Resource is the pattern of the URLs to which the policy grants access,
+	// e.g. https://example.com/videos/*. A * matches any sequence of
+	// characters, including slashes and the query.
+	Resource string
+	// NotBefore, if non-zero, is the time from which the policy is valid.
+	NotBefore time.Time
+	// Expiry is the time at which the policy expires.
+	Expiry time.Time
+	// Methods, if non-empty, are the permitted HTTP methods.
+	Methods []string
+	// SourceIP, if valid, is the network from which requests must be made.
+	SourceIP netip.Prefix
+}
+
+// policyDocument is the JSON encoding of a policy. The expiry is encoded in
+// the token carrying the document.
+type policyDocument struct {
+	Resource  string   `json:"resource"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	Methods   []string `json:"methods,omitempty"`
+	SourceIP  string   `json:"source_ip,omitempty"`
+}
+
+// SignPolicy signs the policy, returning the URL with the signed policy added
+// in a single query parameter. The URL must match the resource pattern of the
+// policy. The signed policy, which is not encrypted, is valid for any URL
+// matching the pattern, so it may also be added to other such URLs, with
+// PolicyURL. Verify requests with VerifyPolicy.
+func (s *Signer) SignPolicy(unsigned string, p Policy) (string, error) {
+	if p.Resource == "" {
+		return "", errors.New("policy has no resource")
+	}
+	if p.Expiry.IsZero() {
+		return "", errors.New("policy has no expiry")
+	}
+	doc := policyDocument{Resource: p.Resource}
+	if !p.NotBefore.IsZero() {
+		doc.NotBefore = p.NotBefore.Unix()
+	}
+	if len(p.Methods) > 0 {
+		var err error
+		if doc.Methods, err = normalizeMethods(p.Methods); err != nil {
+			return "", err
+		}
+	}
+	if p.SourceIP.IsValid() {
+		doc.SourceIP = p.SourceIP.Masked().String()
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	token, err := s.signToken(policyPurpose, data, p.Expiry)
+	if err != nil {
+		return "", err
+	}
+	return PolicyURL(unsigned, token)
+}
+
+// PolicyURL adds the signed policy, from a URL signed with SignPolicy or the
+// value of its query parameter, to another URL, which must match the
+// resource pattern of the policy. The policy is not verified.
+func PolicyURL(unsigned, policy string) (string, error) {
+	if signed, err := url.ParseRequestURI(policy); err == nil && signed.RawQuery != "" {
+		if policy, err = removeQueryParam(signed, policyParam); err != nil || policy == "" {
+			return "", fmt.Errorf("%w: no policy: %s", ErrInvalidFormat, signed)
+		}
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	if !matchResource(policyResource(policy), u) {
+		return "", fmt.Errorf("%w: %s", ErrNotGranted, u)
+	}
+	appendQueryParam(u, policyParam, policy)
+	return u.String(), nil
+}
+
+// VerifyPolicy verifies the signed policy of a request, returning the policy
+// if the request satisfies its conditions. The request is rejected with
+// ErrExpired or ErrNotYetValid outside the validity of the policy,
+// ErrNotGranted if its URL does not match the resource pattern,
+// ErrMethodNotAllowed if its method is not permitted, and ErrInvalidClient if
+// it is not from the source network, as identified by RemoteIP or the function
+// configured with WithClientFunc.
+func (s *Signer) VerifyPolicy(r *http.Request) (*Policy, error) {
+	u := requestURL(r)
+	token, err := removeQueryParam(u, policyParam)
+	if err != nil || token == "" {
+		return nil, fmt.Errorf("%w: no policy", ErrInvalidFormat)
+	}
+	data, err := s.verifyToken(policyPurpose, token)
+	if err != nil {
+		return nil, err
+	}
+	var doc policyDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid policy: %s", ErrInvalidFormat, err.Error())
+	}
+	p := &Policy{Resource: doc.Resource, Methods: doc.Methods}
+	if p.Expiry, err = s.tokenExpiry(token); err != nil {
+		return nil, err
+	}
+	if doc.NotBefore != 0 {
+		p.NotBefore = time.Unix(doc.NotBefore, 0)
+		if time.Now().Before(p.NotBefore) {
+			return nil, ErrNotYetValid
+		}
+	}
+	if !matchResource(p.Resource, u) {
+		return nil, fmt.Errorf("%w: %s", ErrNotGranted, u)
+	}
+	if len(p.Methods) > 0 {
+		if err := checkMethod(&Result{Methods: p.Methods}, r.Method); err != nil {
+			return nil, err
+		}
+	}
+	if doc.SourceIP != "" {
+		if p.SourceIP, err = netip.ParsePrefix(doc.SourceIP); err != nil {
+			return nil, fmt.Errorf("%w: invalid source IP: %s", ErrInvalidFormat, doc.SourceIP)
+		}
+		fn := s.clientFunc
+		if fn == nil {
+			fn = RemoteIP
+		}
+		client, err := fn(r)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := netip.ParseAddr(client)
+		if err != nil || !p.SourceIP.Contains(addr.Unmap().WithZone("")) {
+			return nil, fmt.Errorf("%w: %s not in %s", ErrInvalidClient, client, p.SourceIP)
+		}
+	}
+	return p, nil
+}
+
+// tokenExpiry returns the expiry of a token produced by signToken, which is
+// not verified.
+func (s *Signer) tokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidFormat, token)
+	}
+	return s.decodeTime(parts[1])
+}
+
+// policyResource returns the resource pattern of a signed policy, which is not
+// verified, or an empty string if it is invalid.
+func policyResource(token string) string {
+	encoded, _, _ := strings.Cut(token, ".")
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	var doc policyDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ""
+	}
+	return doc.Resource
+}
+
+// matchResource reports whether the URL matches the resource pattern. URLs
+// with dot segments in their path never match, lest they escape the pattern
+// once cleaned.
+func matchResource(pattern string, u *url.URL) bool {
+	return !hasDotSegment(u.Path) && matchWildcard(pattern, u.String())
+}
+
+// matchWildcard reports whether the string matches the pattern, in which a *
+// matches any sequence of characters.
+func matchWildcard(pattern, s string) bool {
+	literals := strings.Split(pattern, "*")
+	if len(literals) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, literals[0]) {
+		return false
+	}
+	s = s[len(literals[0]):]
+	for _, lit := range literals[1 : len(literals)-1] {
+		i := strings.Index(s, lit)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(lit):]
+	}
+	return strings.HasSuffix(s, literals[len(literals)-1])
+}
@@ -0,0 +1,129 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignPolicy(t *testing.T) {
+	signer := New([]byte("abc123"))
+	policy := Policy{
+		Resource:  "https://example.com/videos/*",
+		NotBefore: time.Now().Add(-time.Minute),
+		Expiry:    time.Now().Add(time.Minute),
+		Methods:   []string{"GET", "HEAD"},
+		SourceIP:  netip.MustParsePrefix("192.0.2.0/24"),
+	}
+
+	signed, err := signer.SignPolicy("https://example.com/videos/1234/playlist.m3u8", policy)
+	require.NoError(t, err)
+
+	request := func(method, target, remoteAddr string) *http.Request {
+		r := httptest.NewRequest(method, target, nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	got, err := signer.VerifyPolicy(request("GET", signed, "192.0.2.1:1234"))
+	require.NoError(t, err)
+	assert.Equal(t, policy.Resource, got.Resource)
+	assert.Equal(t, policy.SourceIP, got.SourceIP)
+	assert.Equal(t, policy.Expiry.Unix(), got.Expiry.Unix())
+
+	segment, err := PolicyURL("https://example.com/videos/1234/segment1.ts", signed)
+	require.NoError(t, err)
+	_, err = signer.VerifyPolicy(request("GET", segment, "192.0.2.1:1234"))
+	assert.NoError(t, err)
+
+	_, err = signer.VerifyPolicy(request("POST", signed, "192.0.2.1:1234"))
+	assert.ErrorIs(t, err, ErrMethodNotAllowed)
+
+	_, err = signer.VerifyPolicy(request("GET", signed, "198.51.100.1:1234"))
+	assert.ErrorIs(t, err, ErrInvalidClient)
+
+	t.Run("resource", func(t *testing.T) {
+		_, err := PolicyURL("https://example.com/images/a.png", signed)
+		assert.ErrorIs(t, err, ErrNotGranted)
+
+		// moved to another resource
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		u.Path = "/images/a.png"
+		_, err = signer.VerifyPolicy(request("GET", u.String(), "192.0.2.1:1234"))
+		assert.ErrorIs(t, err, ErrNotGranted)
+
+		// escaped the resource with dot segments
+		_, err = PolicyURL("https://example.com/videos/../admin", signed)
+		assert.ErrorIs(t, err, ErrNotGranted)
+		u.Path = "/videos/../admin"
+		_, err = signer.VerifyPolicy(request("GET", u.String(), "192.0.2.1:1234"))
+		assert.ErrorIs(t, err, ErrNotGranted)
+	})
+
+	t.Run("not yet valid", func(t *testing.T) {
+		signed, err := signer.SignPolicy("https://example.com/videos/a", Policy{
+			Resource:  "https://example.com/videos/*",
+			NotBefore: time.Now().Add(time.Hour),
+			Expiry:    time.Now().Add(2 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		_, err = signer.VerifyPolicy(request("GET", signed, "192.0.2.1:1234"))
+		assert.ErrorIs(t, err, ErrNotYetValid)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignPolicy("https://example.com/videos/a", Policy{
+			Resource: "https://example.com/videos/*",
+			Expiry:   time.Now().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		_, err = signer.VerifyPolicy(request("GET", signed, "192.0.2.1:1234"))
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("forged", func(t *testing.T) {
+		_, err := New([]byte("xyz789")).VerifyPolicy(request("GET", signed, "192.0.2.1:1234"))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		_, err := signer.VerifyPolicy(request("GET", "https://example.com/videos/a", "192.0.2.1:1234"))
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := signer.SignPolicy("https://example.com/videos/a", Policy{Expiry: time.Now().Add(time.Hour)})
+		assert.Error(t, err)
+		_, err = signer.SignPolicy("https://example.com/videos/a", Policy{Resource: "https://example.com/videos/*"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMatchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"https://example.com/a", "https://example.com/a", true},
+		{"https://example.com/a", "https://example.com/a/b", false},
+		{"https://example.com/*", "https://example.com/a/b?c=d", true},
+		{"https://example.com/*.ts", "https://example.com/a/b.ts", true},
+		{"https://example.com/*.ts", "https://example.com/a/b.ts?c=d", false},
+		{"https://*.example.com/*/a", "https://cdn.example.com/x/y/a", true},
+		{"https://example.com/a*a", "https://example.com/a", false},
+		{"*", "anything", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchWildcard(tt.pattern, tt.s), "%s %s", tt.pattern, tt.s)
+	}
+}
@@ -0,0 +1,67 @@
+package surl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errInvalidExpiry is returned when the expiry of a signed URL cannot be
+// decoded.
+var errInvalidExpiry = errors.New("invalid expiry")
+
+// WithMillisecondExpiry instructs Signer to encode the expiry of the URLs it
+// signs with millisecond precision rather than truncating it to the second,
+// for short-lived URLs, e.g. internal redirects valid for a fraction of a
+// second. The milliseconds are appended to the encoded expiry as a decimal
+// fraction, e.g. 1700000000.250, and any signer verifies such URLs, along with
+// URLs with second precision, regardless of the option.
+func WithMillisecondExpiry() Option {
+	return func(s *Signer) {
+		s.millisecondExpiry = true
+	}
+}
+
+// encodeExpiry encodes the expiry of a URL, with millisecond precision if
+// configured.
+func (s *Signer) encodeExpiry(expiry time.Time) string {
+	encoded := s.encodeTime(expiry)
+	if s.millisecondExpiry {
+		encoded += fmt.Sprintf(".%03d", expiry.Nanosecond()/int(time.Millisecond))
+	}
+	return encoded
+}
+
+// SignedExpiry returns the expiry of URLs signed by the signer with the given
+// expiry, which is rounded up to the granularity set with
+// WithExpiryGranularity, and truncated to the second, or to the millisecond
+// with WithMillisecondExpiry. The zero time is returned as is.
+func (s *Signer) SignedExpiry(expiry time.Time) time.Time {
+	if expiry.IsZero() {
+		return expiry
+	}
+	expiry = s.roundExpiry(expiry)
+	if s.millisecondExpiry {
+		return expiry.Truncate(time.Millisecond)
+	}
+	return expiry.Truncate(time.Second)
+}
+
+// decodeExpiry decodes an expiry encoded by encodeExpiry.
+func (s *Signer) decodeExpiry(encoded string) (time.Time, error) {
+	secs, millis, found := strings.Cut(encoded, ".")
+	expiry, err := s.decodeTime(secs)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w: %s", ErrInvalidFormat, errInvalidExpiry, encoded)
+	}
+	if !found {
+		return expiry, nil
+	}
+	ms, err := strconv.ParseUint(millis, 10, 16)
+	if err != nil || len(millis) != 3 {
+		return time.Time{}, fmt.Errorf("%w: %w: %s", ErrInvalidFormat, errInvalidExpiry, encoded)
+	}
+	return expiry.Add(time.Duration(ms) * time.Millisecond), nil
+}
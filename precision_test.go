@@ -0,0 +1,87 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMillisecondExpiry(t *testing.T) {
+	signer := New([]byte("abc123"), WithMillisecondExpiry())
+	expiry := time.Unix(1700000000, 250*int64(time.Millisecond)+999)
+
+	signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(signed, ".250"), signed)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	result, err := signer.verifyURLAt(context.Background(), u, "", expiry.Add(-time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, expiry.Truncate(time.Millisecond), result.ExpiresAt)
+
+	u, err = url.Parse(signed)
+	require.NoError(t, err)
+	_, err = signer.verifyURLAt(context.Background(), u, "", expiry.Add(time.Millisecond))
+	assert.ErrorIs(t, err, ErrExpired)
+
+	t.Run("second precision", func(t *testing.T) {
+		signed, err := New([]byte("abc123")).Sign("https://example.com/a/b/c", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("verified without option", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.NoError(t, New([]byte("abc123")).Verify(signed))
+	})
+
+	t.Run("path formatter", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithMillisecondExpiry(), WithPathFormatter(), WithBase58Expiry())
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := strings.Replace(signed, ".250", ".999", 1)
+		assert.ErrorIs(t, signer.VerifyAt(tampered, expiry.Add(-time.Second)), ErrInvalidSignature)
+	})
+}
+
+func TestSigner_SignedExpiry(t *testing.T) {
+	expiry := time.Unix(1700000000, 250*int64(time.Millisecond)+999)
+
+	tests := []struct {
+		name   string
+		signer *Signer
+		want   time.Time
+	}{
+		{"default", New([]byte("abc123")), time.Unix(1700000000, 0)},
+		{"millisecond", New([]byte("abc123"), WithMillisecondExpiry()), time.Unix(1700000000, 250*int64(time.Millisecond))},
+		{"granularity", New([]byte("abc123"), WithExpiryGranularity(time.Hour)), time.Unix(1700002800, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.signer.SignedExpiry(expiry)
+			assert.True(t, tt.want.Equal(got), got)
+
+			// matches the expiry encoded in the URL
+			signed, err := tt.signer.Sign("https://example.com/a/b/c", expiry)
+			require.NoError(t, err)
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			result, err := tt.signer.verifyURLAt(context.Background(), u, "", expiry.Add(-time.Hour))
+			require.NoError(t, err)
+			assert.True(t, got.Equal(result.ExpiresAt), result.ExpiresAt)
+		})
+	}
+
+	assert.True(t, New([]byte("abc123"), WithNoExpiry()).SignedExpiry(time.Time{}).IsZero())
+}
@@ -0,0 +1,54 @@
+package surl
+
+import (
+	"strings"
+)
+
+// PrefixPaths is like PrefixPath but accepts several prefixes when verifying,
+// e.g. when the same handler is mounted under both /signed and a legacy /s,
+// stripping whichever matches, the longest if several do. URLs are signed with
+// the first prefix. StripSignature and ServeMux retain the prefix that
+// matched, so handlers downstream see the path as requested.
+func PrefixPaths(prefixes ...string) Option {
+	return func(s *Signer) {
+		s.prefix = ""
+		s.prefixes = nil
+		if len(prefixes) > 0 {
+			s.prefix = prefixes[0]
+			s.prefixes = prefixes
+		}
+	}
+}
+
+// matchPrefix returns the prefix of the path, of those accepted when
+// verifying, reporting whether any matched.
+func (s *Signer) matchPrefix(path string) (string, bool) {
+	if s.prefixes == nil {
+		return s.prefix, strings.HasPrefix(path, s.prefix)
+	}
+	var matched string
+	var ok bool
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(path, prefix) && (!ok || len(prefix) > len(matched)) {
+			matched, ok = prefix, true
+		}
+	}
+	return matched, ok
+}
+
+// WithSignedPrefix instructs Signer to cover the prefix set with PrefixPath by
+// the signature, which otherwise excludes it, so that a URL signed for one
+// prefixed mount point cannot be moved to another, for deployments in which
+// the prefix is meaningful. The signer verifying URLs must also be configured
+// with the option.
+func WithSignedPrefix() Option {
+	return func(s *Signer) {
+		s.signedPrefix = true
+	}
+}
+
+// prefixBinding binds the prefix, along with any other binding, to a
+// signature.
+func prefixBinding(prefix, binding string) string {
+	return tagBinding("prefix", prefix, binding)
+}
@@ -0,0 +1,80 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSignedPrefix(t *testing.T) {
+	signer := New([]byte("abc123"), PrefixPath("/public"), WithSignedPrefix())
+	other := New([]byte("abc123"), PrefixPath("/admin"), WithSignedPrefix())
+
+	signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(signed))
+
+	// moving the URL to another mount point invalidates it
+	moved := strings.Replace(signed, "/public", "/admin", 1)
+	assert.ErrorIs(t, other.Verify(moved), ErrInvalidSignature)
+
+	t.Run("unsigned prefix", func(t *testing.T) {
+		signer := New([]byte("abc123"), PrefixPath("/public"))
+		other := New([]byte("abc123"), PrefixPath("/admin"))
+
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		moved := strings.Replace(signed, "/public", "/admin", 1)
+		assert.NoError(t, other.Verify(moved))
+	})
+
+	t.Run("path formatter", func(t *testing.T) {
+		signer := New([]byte("abc123"), PrefixPath("/public"), WithSignedPrefix(), WithPathFormatter())
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+	})
+}
+
+func TestPrefixPaths(t *testing.T) {
+	signer := New([]byte("abc123"), PrefixPaths("/signed", "/s"))
+	legacy := New([]byte("abc123"), PrefixPath("/s"))
+
+	signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "https://example.com/signed/a/b/c"), signed)
+	assert.NoError(t, signer.Verify(signed))
+
+	legacySigned, err := legacy.SignFor("https://example.com/a/b/c", time.Minute)
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(legacySigned))
+
+	other, err := New([]byte("abc123"), PrefixPath("/other")).SignFor("https://example.com/a/b/c", time.Minute)
+	require.NoError(t, err)
+	assert.ErrorIs(t, signer.Verify(other), ErrInvalidFormat)
+
+	t.Run("signed prefix", func(t *testing.T) {
+		signer := New([]byte("abc123"), PrefixPaths("/signed", "/s"), WithSignedPrefix())
+		legacy := New([]byte("abc123"), PrefixPath("/s"), WithSignedPrefix())
+
+		signed, err := legacy.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("strip signature", func(t *testing.T) {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path))
+		})
+		w := httptest.NewRecorder()
+		signer.Middleware(next, StripSignature()).ServeHTTP(w, httptest.NewRequest("GET", legacySigned, nil))
+		// the prefix that matched is retained
+		assert.Equal(t, "/s/a/b/c", w.Body.String())
+	})
+}
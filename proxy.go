@@ -0,0 +1,25 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// Proxy returns a handler that forwards requests with valid, unexpired,
+// signed URLs to the target, e.g. private object storage. Requests that fail
+// verification receive a 403 Forbidden response, or a 410 Gone response if
+// the URL has expired. The request is forwarded with the URL as it was before
+// it was signed, without the prefix, joined to the path of the target, and
+// with the Host header of the target. Options configure the verifying
+// middleware.
+func (s *Signer) Proxy(target *url.URL, opts ...MiddlewareOption) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.SetXForwarded()
+		},
+	}
+	forward := http.StripPrefix(s.prefix, proxy)
+	return s.Middleware(forward, append([]MiddlewareOption{StripSignature()}, opts...)...)
+}
@@ -0,0 +1,59 @@
+package surl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.RequestURI())
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL + "/bucket")
+	require.NoError(t, err)
+
+	signer := New([]byte("abc123"), PrefixPath("/signed"))
+	proxy := httptest.NewServer(signer.Proxy(target))
+	defer proxy.Close()
+
+	t.Run("valid", func(t *testing.T) {
+		signed, err := signer.Sign(proxy.URL+"/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		resp, err := http.Get(signed)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/bucket/a/b/c?foo=bar", string(body))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign(proxy.URL+"/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		resp, err := http.Get(signed)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		resp, err := http.Get(proxy.URL + "/signed/a/b/c")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,31 @@
+package surl
+
+import (
+	"fmt"
+)
+
+// WithPurpose instructs Signer to sign with a key derived from its key and the
+// purpose, e.g. password-reset, so that a URL signed for one purpose is never
+// valid for a signer configured with another purpose, or with none, whilst
+// only one key needs to be managed. Fallback keys, and keys from a keyring or
+// key function, are derived in the same way. Signers without a symmetric key
+// fail with ErrNoKey.
+func WithPurpose(purpose string) Option {
+	return func(s *Signer) {
+		if s.key == nil {
+			s.fail(fmt.Errorf("%w: WithPurpose", ErrNoKey))
+			return
+		}
+		s.purpose = purpose
+		s.key = deriveKey(s.key, "surl purpose "+purpose)
+		s.alg = s.keyedHashFor(s.key)
+		if len(s.fallbacks) > 0 {
+			fallbacks := make([]fallbackKey, len(s.fallbacks))
+			for i, fb := range s.fallbacks {
+				key := deriveKey(fb.key, "surl purpose "+purpose)
+				fallbacks[i] = fallbackKey{key: key, alg: s.keyedHashFor(key)}
+			}
+			s.fallbacks = fallbacks
+		}
+	}
+}
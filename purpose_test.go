@@ -0,0 +1,75 @@
+package surl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPurpose(t *testing.T) {
+	reset := New([]byte("abc123"), WithPurpose("password-reset"))
+	invite := New([]byte("abc123"), WithPurpose("invite"))
+	unscoped := New([]byte("abc123"))
+
+	signed, err := reset.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	assert.NoError(t, reset.Verify(signed))
+	assert.ErrorIs(t, invite.Verify(signed), ErrInvalidSignature)
+	assert.ErrorIs(t, unscoped.Verify(signed), ErrInvalidSignature)
+	assert.Equal(t, "password-reset", reset.Config().Purpose)
+
+	t.Run("derived signer", func(t *testing.T) {
+		derived := unscoped.With(WithPurpose("password-reset"))
+
+		assert.NoError(t, derived.Verify(signed))
+	})
+
+	t.Run("with hash", func(t *testing.T) {
+		a := New([]byte("abc123"), WithPurpose("password-reset"), WithHMACSHA256())
+		b := New([]byte("abc123"), WithHMACSHA256(), WithPurpose("password-reset"))
+
+		signed, err := a.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.NoError(t, b.Verify(signed))
+	})
+
+	t.Run("fallback keys", func(t *testing.T) {
+		a := New([]byte("def456"), WithPurpose("password-reset"), WithFallbackKeys([]byte("abc123")))
+		b := New([]byte("def456"), WithFallbackKeys([]byte("abc123")), WithPurpose("password-reset"))
+
+		assert.NoError(t, a.Verify(signed))
+		assert.NoError(t, b.Verify(signed))
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		signed, err := reset.Scoped("/a").Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, reset.Verify(signed))
+		assert.ErrorIs(t, invite.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("key function", func(t *testing.T) {
+		keyFunc := WithKeyFunc(func(context.Context, string) ([]byte, error) {
+			return []byte("ghi789"), nil
+		})
+		reset := New([]byte("abc123"), WithPurpose("password-reset"), keyFunc)
+		invite := New([]byte("abc123"), WithPurpose("invite"), keyFunc)
+
+		signed, err := reset.SignWithKeyID(context.Background(), "tenant-1", "https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, reset.Verify(signed))
+		assert.ErrorIs(t, invite.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		signer := NewFromSignFunc(func(data []byte) ([]byte, error) { return data, nil }, WithPurpose("invite"))
+		_, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrNoKey)
+	})
+}
@@ -3,54 +3,101 @@ package surl
 import (
 	"fmt"
 	"net/url"
+	"strings"
 )
 
-type queryFormatter struct{}
+// queryFormatter stores the signature and expiry in query parameters.
+type queryFormatter struct {
+	expiryParam    string
+	signatureParam string
+}
+
+func newQueryFormatter() *queryFormatter {
+	return &queryFormatter{
+		expiryParam:    "expiry",
+		signatureParam: "signature",
+	}
+}
+
+// newShortQueryFormatter constructs a query formatter using one-character
+// parameter names.
+func newShortQueryFormatter() *queryFormatter {
+	return &queryFormatter{
+		expiryParam:    "e",
+		signatureParam: "s",
+	}
+}
 
 func (f *queryFormatter) addExpiry(unsigned *url.URL, expiry string) {
-	q := unsigned.Query()
-	q.Add("expiry", expiry)
-	unsigned.RawQuery = q.Encode()
+	appendQueryParam(unsigned, f.expiryParam, expiry)
 }
 
 func (f *queryFormatter) buildPayload(u url.URL, opts payloadOptions) string {
 	if opts.skipQuery {
 		// Remove all query params other than expiry
-		expiry := u.Query().Get("expiry")
-		u.RawQuery = url.Values{"expiry": []string{expiry}}.Encode()
+		expiry := u.Query().Get(f.expiryParam)
+		u.RawQuery = url.Values{f.expiryParam: []string{expiry}}.Encode()
 	}
 	if opts.skipScheme {
 		u.Scheme = ""
 	}
+	if opts.skipHost {
+		u.Host = ""
+	}
 	return u.String()
 }
 
 func (f *queryFormatter) addSignature(payload *url.URL, sig string) {
-	q := payload.Query()
-	q.Add("signature", sig)
-	payload.RawQuery = q.Encode()
+	appendQueryParam(payload, f.signatureParam, sig)
 }
 
 func (f *queryFormatter) extractSignature(u *url.URL) (string, error) {
-	q := u.Query()
-	sig := q.Get("signature")
-	if sig == "" {
+	sig, err := removeQueryParam(u, f.signatureParam)
+	if err != nil || sig == "" {
 		return "", fmt.Errorf("%w: %s", ErrInvalidFormat, u.String())
 	}
-	q.Del("signature")
-	u.RawQuery = q.Encode()
-
 	return sig, nil
 }
 
 func (f *queryFormatter) extractExpiry(u *url.URL) (string, error) {
-	q := u.Query()
-	expiry := q.Get("expiry")
-	if expiry == "" {
+	expiry, err := removeQueryParam(u, f.expiryParam)
+	if err != nil || expiry == "" {
 		return "", ErrInvalidFormat
 	}
-	q.Del("expiry")
-	u.RawQuery = q.Encode()
-
 	return expiry, nil
 }
+
+// appendQueryParam appends a parameter to the query of a URL. Unlike
+// url.Values, the existing query is left untouched, preserving the order of
+// repeated keys, keys without a value, and so on.
+func appendQueryParam(u *url.URL, key, value string) {
+	param := url.QueryEscape(key) + "=" + url.QueryEscape(value)
+	if u.RawQuery == "" {
+		u.RawQuery = param
+	} else {
+		u.RawQuery += "&" + param
+	}
+}
+
+// removeQueryParam removes the last occurrence of a parameter from the query
+// of a URL, returning its unescaped value, or an empty string if not found.
+// The remainder of the query is left untouched.
+func removeQueryParam(u *url.URL, key string) (string, error) {
+	if u.RawQuery == "" {
+		return "", nil
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i := len(params) - 1; i >= 0; i-- {
+		value, found := strings.CutPrefix(params[i], key+"=")
+		if !found {
+			continue
+		}
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return "", err
+		}
+		u.RawQuery = strings.Join(append(params[:i], params[i+1:]...), "&")
+		return unescaped, nil
+	}
+	return "", nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 func TestQueryFormatter(t *testing.T) {
-	f := queryFormatter{}
+	f := newQueryFormatter()
 	// unsigned url with existing query
 	u := &url.URL{RawQuery: "foo=bar"}
 
@@ -19,15 +19,15 @@ func TestQueryFormatter(t *testing.T) {
 	encoded := stdIntEncoding(10).Encode(expiry.Unix())
 
 	f.addExpiry(u, encoded)
-	assert.Equal(t, "expiry=3507595200&foo=bar", u.RawQuery)
+	assert.Equal(t, "foo=bar&expiry=3507595200", u.RawQuery)
 
 	f.addSignature(u, "abcdef")
-	assert.Equal(t, "expiry=3507595200&foo=bar&signature=abcdef", u.RawQuery)
+	assert.Equal(t, "foo=bar&expiry=3507595200&signature=abcdef", u.RawQuery)
 
 	sig, err := f.extractSignature(u)
 	require.NoError(t, err)
 	assert.Equal(t, "abcdef", string(sig))
-	assert.Equal(t, "expiry=3507595200&foo=bar", u.RawQuery)
+	assert.Equal(t, "foo=bar&expiry=3507595200", u.RawQuery)
 
 	got, err := f.extractExpiry(u)
 	require.NoError(t, err)
@@ -35,6 +35,54 @@ func TestQueryFormatter(t *testing.T) {
 	assert.Equal(t, "foo=bar", u.RawQuery)
 }
 
+func TestShortQueryFormatter(t *testing.T) {
+	f := newShortQueryFormatter()
+	u := &url.URL{RawQuery: "foo=bar"}
+
+	f.addExpiry(u, "3507595200")
+	f.addSignature(u, "abcdef")
+	assert.Equal(t, "foo=bar&e=3507595200&s=abcdef", u.RawQuery)
+
+	sig, err := f.extractSignature(u)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", sig)
+
+	got, err := f.extractExpiry(u)
+	require.NoError(t, err)
+	assert.Equal(t, "3507595200", got)
+	assert.Equal(t, "foo=bar", u.RawQuery)
+}
+
+func TestQueryFormatter_PreservesQuery(t *testing.T) {
+	f := newQueryFormatter()
+	// query that does not survive a round trip through url.Values
+	raw := "b=2&a=1&b=1&flag&empty=&a=0"
+	u := &url.URL{RawQuery: raw}
+
+	f.addExpiry(u, "3507595200")
+	f.addSignature(u, "abcdef")
+	assert.Equal(t, raw+"&expiry=3507595200&signature=abcdef", u.RawQuery)
+
+	_, err := f.extractSignature(u)
+	require.NoError(t, err)
+	_, err = f.extractExpiry(u)
+	require.NoError(t, err)
+	assert.Equal(t, raw, u.RawQuery)
+}
+
+// TestQueryFormatter_Legacy tests URLs signed before the query was preserved,
+// when the query was sorted by key, continue to verify.
+func TestQueryFormatter_Legacy(t *testing.T) {
+	f := newQueryFormatter()
+	u := &url.URL{RawQuery: "a=1&expiry=3507595200&foo=bar&signature=abcdef&zoo=1"}
+
+	sig, err := f.extractSignature(u)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", sig)
+	// the payload is unchanged from when it was signed
+	assert.Equal(t, "a=1&expiry=3507595200&foo=bar&zoo=1", u.RawQuery)
+}
+
 func TestQueryFormatter_Errors(t *testing.T) {
 	signer := New([]byte("abc123"), WithQueryFormatter())
 
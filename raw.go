@@ -0,0 +1,71 @@
+package surl
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// rawPurpose distinguishes raw signed URLs from other signed data.
+	rawPurpose = "raw"
+	// rawParam is the name of the query parameter appended to raw signed URLs.
+	rawParam = "signature"
+)
+
+// SignRaw signs the URL exactly as given, without parsing or canonicalizing
+// it, and appends a query parameter of the form signature=<expiry>.<signature>.
+// The rest of the URL is left byte-for-byte unchanged, which suits pipelines
+// in which re-serializing the URL, e.g. re-escaping or re-ordering query
+// parameters, would break downstream systems. The URL must not contain a
+// fragment. Options altering the payload, such as SkipQuery, are not
+// supported.
+func (s *Signer) SignRaw(unsigned string, expiry time.Time) (string, error) {
+	if strings.Contains(unsigned, "#") {
+		return "", fmt.Errorf("%w: raw URL must not contain a fragment", ErrInvalidFormat)
+	}
+	sep := "?"
+	if strings.Contains(unsigned, "?") {
+		sep = "&"
+	}
+	payload := unsigned + sep + rawParam + "=" + s.encodeTime(expiry) + "."
+	sig, err := s.sign(context.Background(), bind(payload, rawPurpose))
+	if err != nil {
+		return "", err
+	}
+	return payload + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyRaw verifies a URL signed by SignRaw, comparing it byte-for-byte, and
+// returns the URL as it was before it was signed.
+func (s *Signer) VerifyRaw(signed string) (string, error) {
+	i := strings.LastIndex(signed, rawParam+"=")
+	if i < 1 || (signed[i-1] != '?' && signed[i-1] != '&') {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFormat, signed)
+	}
+	j := strings.LastIndexByte(signed, '.')
+	if j < i {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFormat, signed)
+	}
+	payload, encodedSig := signed[:j+1], signed[j+1:]
+	encodedExpiry := signed[i+len(rawParam)+1 : j]
+
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid base64: %s", ErrInvalidSignature, encodedSig)
+	}
+	if err := s.verify(context.Background(), bind(payload, rawPurpose), sig); err != nil {
+		return "", err
+	}
+
+	expiry, err := s.decodeTime(encodedExpiry)
+	if err != nil {
+		return "", err
+	}
+	if time.Now().After(expiry) {
+		return "", &ExpiredError{ExpiredAt: expiry}
+	}
+	return signed[:i-1], nil
+}
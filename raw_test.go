@@ -0,0 +1,60 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaw(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	tests := []struct {
+		name     string
+		unsigned string
+	}{
+		{"no query", "https://example.com/a/b"},
+		{"query", "https://example.com/a/b?z=1&a=2"},
+		{"unusual escaping", "https://example.com/a%2fb/c%7E?q=a+b%20c&q=%2F"},
+		{"relative", "/a/b?x=%zz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := signer.SignRaw(tt.unsigned, time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, tt.unsigned, signed[:len(tt.unsigned)])
+
+			unsigned, err := signer.VerifyRaw(signed)
+			require.NoError(t, err)
+			assert.Equal(t, tt.unsigned, unsigned)
+		})
+	}
+
+	t.Run("re-escaped", func(t *testing.T) {
+		signed, err := signer.SignRaw("https://example.com/a%7Eb", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyRaw("https://example.com/a~b" + signed[len("https://example.com/a%7Eb"):])
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignRaw("https://example.com/a/b", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyRaw(signed)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		_, err := signer.SignRaw("https://example.com/a/b#top", time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := signer.VerifyRaw("https://example.com/a/b?signature")
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+}
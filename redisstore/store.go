@@ -0,0 +1,145 @@
+// Package redisstore stores the nonces of single-use signed URLs, the IDs of
+// revoked signed URLs, and the usages of signed URLs, in Redis, so that every
+// instance of a service shares them. Nonces and revocations expire along with
+// their URLs.
+//
+// The package does not depend on a Redis client. Instead, adapt the client to
+// the Client interface, e.g. for github.com/redis/go-redis:
+//
+//	type client struct{ *redis.Client }
+//
+//	func (c client) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+//		return c.Client.SetNX(ctx, key, 1, ttl).Result()
+//	}
+//
+//	func (c client) Exists(ctx context.Context, key string) (bool, error) {
+//		n, err := c.Client.Exists(ctx, key).Result()
+//		return n > 0, err
+//	}
+//
+//	func (c client) RPush(ctx context.Context, key, value string) error {
+//		return c.Client.RPush(ctx, key, value).Err()
+//	}
+//
+//	func (c client) LRange(ctx context.Context, key string) ([]string, error) {
+//		return c.Client.LRange(ctx, key, 0, -1).Result()
+//	}
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+// DefaultPrefix is the default prefix of keys.
+const DefaultPrefix = "surl:"
+
+// Client is the subset of a Redis client used by Store.
+type Client interface {
+	// SetNX sets the key, with the TTL, only if it does not already exist,
+	// reporting whether it was set, as with SET key value NX PX ttl. A zero
+	// TTL means the key does not expire.
+	SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Exists reports whether the key exists.
+	Exists(ctx context.Context, key string) (bool, error)
+	// RPush appends the value to the list at the key, as with RPUSH key
+	// value.
+	RPush(ctx context.Context, key, value string) error
+	// LRange returns every value of the list at the key, as with LRANGE key
+	// 0 -1, or none if there is no such list.
+	LRange(ctx context.Context, key string) ([]string, error)
+}
+
+// Store stores nonces, revocations and usages in Redis. It implements
+// surl.NonceStore and surl.UsageStore, and its Revoked method is a
+// surl.RevocationChecker:
+//
+//	store := &redisstore.Store{Client: client}
+//	signer := surl.New(secret,
+//		surl.WithNonceStore(store),
+//		surl.WithRevocationChecker(store.Revoked),
+//		surl.WithUsageStore(store),
+//	)
+type Store struct {
+	// Client is the Redis client.
+	Client Client
+	// Prefix is the prefix of keys. If empty, DefaultPrefix is used.
+	Prefix string
+}
+
+var (
+	_ surl.NonceStore = (*Store)(nil)
+	_ surl.UsageStore = (*Store)(nil)
+)
+
+// Consume implements surl.NonceStore.
+func (s *Store) Consume(ctx context.Context, nonce string, expiresAt time.Time) error {
+	set, err := s.Client.SetNX(ctx, s.key("nonce:", nonce), ttl(expiresAt))
+	if err != nil {
+		return err
+	}
+	if !set {
+		return surl.ErrUsed
+	}
+	return nil
+}
+
+// Revoke revokes the signed URL with the ID, which expires at the given time.
+// The revocation is retained until then.
+func (s *Store) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := s.Client.SetNX(ctx, s.key("revoked:", id), ttl(expiresAt))
+	return err
+}
+
+// Revoked reports whether the signed URL with the ID has been revoked.
+func (s *Store) Revoked(ctx context.Context, id string) (bool, error) {
+	return s.Client.Exists(ctx, s.key("revoked:", id))
+}
+
+// Record implements surl.UsageStore. Usages are appended, JSON-encoded, to a
+// list for each signed URL, which is retained indefinitely.
+func (s *Store) Record(ctx context.Context, usage surl.Usage) error {
+	value, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	return s.Client.RPush(ctx, s.key("usage:", usage.LinkID), string(value))
+}
+
+// Usages implements surl.UsageStore.
+func (s *Store) Usages(ctx context.Context, linkID string) ([]surl.Usage, error) {
+	values, err := s.Client.LRange(ctx, s.key("usage:", linkID))
+	if err != nil {
+		return nil, err
+	}
+	usages := make([]surl.Usage, len(values))
+	for i, value := range values {
+		if err := json.Unmarshal([]byte(value), &usages[i]); err != nil {
+			return nil, fmt.Errorf("decoding usage: %w", err)
+		}
+	}
+	return usages, nil
+}
+
+func (s *Store) key(kind, id string) string {
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return prefix + kind + id
+}
+
+// ttl returns the TTL of an entry for a URL that expires at the given time.
+// A second is added to allow for the expiry being truncated to the second,
+// and so that the TTL is positive. The TTL is zero, i.e. the entry does not
+// expire, for a URL that never expires.
+func ttl(expiresAt time.Time) time.Duration {
+	if expiresAt.IsZero() {
+		return 0
+	}
+	return max(time.Until(expiresAt), 0) + time.Second
+}
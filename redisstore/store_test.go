@@ -0,0 +1,154 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient is a Client storing keys in a map.
+type fakeClient struct {
+	mu    sync.Mutex
+	keys  map[string]time.Duration
+	lists map[string][]string
+	err   error
+}
+
+func (f *fakeClient) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return false, f.err
+	}
+	if _, ok := f.keys[key]; ok {
+		return false, nil
+	}
+	if f.keys == nil {
+		f.keys = make(map[string]time.Duration)
+	}
+	f.keys[key] = ttl
+	return true, nil
+}
+
+func (f *fakeClient) Exists(ctx context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.keys[key]
+	return ok, f.err
+}
+
+func (f *fakeClient) RPush(ctx context.Context, key, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	if f.lists == nil {
+		f.lists = make(map[string][]string)
+	}
+	f.lists[key] = append(f.lists[key], value)
+	return nil
+}
+
+func (f *fakeClient) LRange(ctx context.Context, key string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.lists[key], f.err
+}
+
+func TestStore(t *testing.T) {
+	client := &fakeClient{}
+	store := &Store{Client: client}
+	signer := surl.New([]byte("abc123"),
+		surl.WithNonceStore(store),
+		surl.WithRevocationChecker(store.Revoked),
+	)
+	expiry := time.Now().Add(time.Hour)
+
+	t.Run("single use", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		assert.ErrorIs(t, signer.Verify(signed), surl.ErrUsed)
+
+		id, err := surl.URLID(signed)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour+time.Second, client.keys["surl:nonce:"+id], float64(time.Second))
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+		id, err := surl.URLID(signed)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Revoke(context.Background(), id, expiry))
+		assert.ErrorIs(t, signer.Verify(signed), surl.ErrRevoked)
+	})
+
+	t.Run("usages", func(t *testing.T) {
+		signer := surl.New([]byte("abc123"), surl.WithUsageStore(store))
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+		handler := signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		for _, agent := range []string{"curl/8.0", "Mozilla/5.0"} {
+			r := httptest.NewRequest("GET", signed, nil)
+			r.Header.Set("User-Agent", agent)
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+		}
+
+		id, err := signer.LinkID(signed)
+		require.NoError(t, err)
+		usages, err := store.Usages(context.Background(), id)
+		require.NoError(t, err)
+		require.Len(t, usages, 2)
+		assert.Equal(t, id, usages[0].LinkID)
+		assert.Equal(t, "curl/8.0", usages[0].UserAgent)
+		assert.Equal(t, "Mozilla/5.0", usages[1].UserAgent)
+		assert.False(t, usages[0].Time.IsZero())
+		assert.Len(t, client.lists["surl:usage:"+id], 2)
+
+		usages, err = store.Usages(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, usages)
+	})
+
+	t.Run("never expires", func(t *testing.T) {
+		require.NoError(t, store.Consume(context.Background(), "permanent", time.Time{}))
+
+		assert.Equal(t, time.Duration(0), client.keys["surl:nonce:permanent"])
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		store := &Store{Client: client, Prefix: "app:"}
+		require.NoError(t, store.Consume(context.Background(), "xyz", expiry))
+
+		assert.Contains(t, client.keys, "app:nonce:xyz")
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		unavailable := errors.New("connection refused")
+		store := &Store{Client: &fakeClient{err: unavailable}}
+
+		assert.ErrorIs(t, store.Consume(context.Background(), "xyz", expiry), unavailable)
+		_, err := store.Revoked(context.Background(), "xyz")
+		assert.ErrorIs(t, err, unavailable)
+		assert.ErrorIs(t, store.Record(context.Background(), surl.Usage{LinkID: "xyz"}), unavailable)
+		_, err = store.Usages(context.Background(), "xyz")
+		assert.ErrorIs(t, err, unavailable)
+	})
+}
@@ -0,0 +1,196 @@
+package surl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultReloadInterval is the default interval at which a signer
+	// constructed with NewFromFile checks its key file for a new key.
+	DefaultReloadInterval = 10 * time.Second
+	// DefaultReloadOverlap is the default period for which a signer
+	// constructed with NewFromFile continues to accept signatures produced
+	// with its previous key.
+	DefaultReloadOverlap = time.Hour
+)
+
+// NewFromFile constructs a signer with the key read from the file at path,
+// e.g. a mounted Kubernetes secret. Before signing or verifying, the signer
+// reads the file again if it has not done so within the reload interval, and
+// if the key has changed, it atomically replaces its key. Signatures produced
+// with a replaced key continue to be accepted for the overlap window. Should
+// the file become unreadable or empty, the signer keeps its key.
+//
+// Use WithReloadInterval and WithReloadOverlap to change the defaults,
+// DefaultReloadInterval and DefaultReloadOverlap. The signer cannot derive
+// scoped signers.
+func NewFromFile(path string, opts ...Option) (*Signer, error) {
+	key, err := readKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fk := &fileKey{
+		path:     path,
+		interval: DefaultReloadInterval,
+		overlap:  DefaultReloadOverlap,
+		now:      time.Now,
+	}
+	s := newSigner(nil, fk, opts...)
+	fk.newAlg = s.keyedHashFor
+	fk.current = fileKeyVersion{key: key, alg: fk.newAlg(key)}
+	fk.checked = fk.now()
+	return s, nil
+}
+
+// WithReloadInterval sets the interval at which a signer constructed with
+// NewFromFile checks its key file for a new key. It has no effect on other
+// signers.
+func WithReloadInterval(interval time.Duration) Option {
+	return func(s *Signer) {
+		if fk, ok := s.alg.(*fileKey); ok {
+			fk.interval = interval
+		}
+	}
+}
+
+// WithReloadOverlap sets the period for which a signer constructed with
+// NewFromFile continues to accept signatures produced with a key after it has
+// been replaced. It has no effect on other signers.
+func WithReloadOverlap(overlap time.Duration) Option {
+	return func(s *Signer) {
+		if fk, ok := s.alg.(*fileKey); ok {
+			fk.overlap = overlap
+		}
+	}
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("key file is empty: %s", path)
+	}
+	return key, nil
+}
+
+// fileKey is an algorithm that signs with the key in a file, reloading the
+// key when the file changes.
+type fileKey struct {
+	path     string
+	interval time.Duration
+	overlap  time.Duration
+	newAlg   func(key []byte) *keyedHash
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	current  fileKeyVersion
+	previous []fileKeyVersion
+	checked  time.Time
+}
+
+// fileKeyVersion is a key read from the file.
+type fileKeyVersion struct {
+	key []byte
+	alg algorithm
+	// replaced is when the key was replaced, or zero for the current key.
+	replaced time.Time
+}
+
+func (f *fileKey) sign(data []byte) ([]byte, error) {
+	f.reload()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.current.alg.sign(data)
+}
+
+func (f *fileKey) verify(data, sig []byte) error {
+	return f.verifyVersions(func(alg algorithm) error {
+		return alg.verify(data, sig)
+	})
+}
+
+func (f *fileKey) verifyTruncated(data, sig []byte, n int) error {
+	return f.verifyVersions(func(alg algorithm) error {
+		return alg.(truncatedVerifier).verifyTruncated(data, sig, n)
+	})
+}
+
+// verifyVersions verifies a signature using the current key and then, within
+// the overlap, the previous keys, until one succeeds or fails for a reason
+// other than an invalid signature.
+func (f *fileKey) verifyVersions(verify func(alg algorithm) error) error {
+	f.reload()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	err := verify(f.current.alg)
+	for _, prev := range f.previous {
+		if !errors.Is(err, ErrInvalidSignature) {
+			break
+		}
+		if f.now().Sub(prev.replaced) < f.overlap {
+			err = verify(prev.alg)
+		}
+	}
+	return err
+}
+
+// name names the algorithm of the current key.
+func (f *fileKey) name() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return algorithmName(f.current.alg)
+}
+
+// reload reads the key file if it has not been read within the interval,
+// replacing the current key if it has changed. The file is read and the key
+// parsed without holding the lock, which is only taken to claim the reload and
+// then to swap the key, so that signing and verifying are not blocked on disk.
+func (f *fileKey) reload() {
+	now := f.now()
+
+	f.mu.RLock()
+	due := now.Sub(f.checked) >= f.interval
+	f.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	f.mu.Lock()
+	if now.Sub(f.checked) < f.interval {
+		// reloaded by another goroutine in the meantime
+		f.mu.Unlock()
+		return
+	}
+	f.checked = now
+	f.mu.Unlock()
+
+	key, err := readKeyFile(f.path)
+	if err != nil {
+		return
+	}
+	next := fileKeyVersion{key: key, alg: f.newAlg(key)}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if bytes.Equal(key, f.current.key) {
+		return
+	}
+	// retain replaced keys within the overlap window, most recent first
+	previous := []fileKeyVersion{{key: f.current.key, alg: f.current.alg, replaced: now}}
+	for _, prev := range f.previous {
+		if now.Sub(prev.replaced) < f.overlap {
+			previous = append(previous, prev)
+		}
+	}
+	f.previous = previous
+	f.current = next
+}
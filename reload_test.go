@@ -0,0 +1,119 @@
+package surl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte("abc123"), 0o600))
+
+	signer, err := NewFromFile(path, WithReloadInterval(time.Minute), WithReloadOverlap(time.Hour))
+	require.NoError(t, err)
+
+	fk := signer.alg.(*fileKey)
+	now := time.Now()
+	fk.now = func() time.Time { return now }
+
+	signed, err := signer.Sign("https://example.com/a/b/c", now.Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.NoError(t, New([]byte("abc123")).Verify(signed))
+
+	// rotate key
+	require.NoError(t, os.WriteFile(path, []byte("def456"), 0o600))
+
+	t.Run("before reload interval", func(t *testing.T) {
+		resigned, err := signer.Sign("https://example.com/a/b/c", now.Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, signed, resigned)
+	})
+
+	now = now.Add(time.Minute)
+
+	t.Run("after reload interval", func(t *testing.T) {
+		resigned, err := signer.Sign("https://example.com/a/b/c", now.Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.NoError(t, New([]byte("def456")).Verify(resigned))
+		assert.NoError(t, signer.Verify(resigned))
+	})
+
+	t.Run("within overlap window", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("after overlap window", func(t *testing.T) {
+		now = now.Add(time.Hour)
+
+		assert.ErrorIs(t, signer.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("keeps key when file is unreadable", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+		now = now.Add(time.Minute)
+
+		resigned, err := signer.Sign("https://example.com/a/b/c", now.Add(48*time.Hour))
+		require.NoError(t, err)
+		assert.NoError(t, New([]byte("def456")).Verify(resigned))
+	})
+
+	t.Run("with hash", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("abc123"), 0o600))
+		signer, err := NewFromFile(path, WithHMACSHA256())
+		require.NoError(t, err)
+
+		assert.Equal(t, "hmac-sha256", signer.Config().Algorithm)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewFromFile(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewFromFile_ReloadUnlocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte("abc123"), 0o600))
+
+	signer, err := NewFromFile(path, WithReloadInterval(time.Minute))
+	require.NoError(t, err)
+
+	fk := signer.alg.(*fileKey)
+	now := time.Now()
+	fk.now = func() time.Time { return now }
+
+	// block parsing the new key
+	parsing, release := make(chan struct{}), make(chan struct{})
+	newAlg := fk.newAlg
+	fk.newAlg = func(key []byte) *keyedHash {
+		close(parsing)
+		<-release
+		return newAlg(key)
+	}
+	require.NoError(t, os.WriteFile(path, []byte("def456"), 0o600))
+	now = now.Add(time.Minute)
+
+	reloaded := make(chan error)
+	go func() {
+		_, err := signer.Sign("https://example.com/a/b/c", now.Add(time.Hour))
+		reloaded <- err
+	}()
+	<-parsing
+
+	// signing proceeds with the old key whilst the new key is parsed
+	signed, err := signer.Sign("https://example.com/a/b/c", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.NoError(t, New([]byte("abc123")).Verify(signed))
+
+	close(release)
+	require.NoError(t, <-reloaded)
+
+	signed, err = signer.Sign("https://example.com/a/b/c", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.NoError(t, New([]byte("def456")).Verify(signed))
+}
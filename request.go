@@ -0,0 +1,88 @@
+package surl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// requestURL reconstructs the full URL of a request. Server requests only
+// populate the path and query of r.URL, in which case the scheme is
+// determined from the TLS state and the host from the Host header.
+func requestURL(r *http.Request) *url.URL {
+	u := *r.URL
+	if u.IsAbs() {
+		return &u
+	}
+	u.Scheme = "http"
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+	u.Host = r.Host
+	return &u
+}
+
+// VerifyRequest verifies the URL of a server request, validating its signature
+// and ensuring it is unexpired, and that the request method is permitted if the
+// URL was signed with SignForMethods. The full URL is reconstructed from r.URL,
+// r.Host and the TLS state of the connection. Requests signed with SignRequest,
+// carrying the signature in a header, are also verified. If a usage store is configured
+// then the usage is recorded.
+func (s *Signer) VerifyRequest(r *http.Request) error {
+	_, err := s.verifyRequest(r)
+	return err
+}
+
+// VerifyRequestDetailed is like VerifyRequest but returns the result of
+// verification, e.g. for adapters to web frameworks to expose to their
+// handlers. If the signature is valid but the URL has expired then the result
+// is returned along with the error.
+func (s *Signer) VerifyRequestDetailed(r *http.Request) (*Result, error) {
+	return s.verifyRequest(r)
+}
+
+// verifyRequest verifies the URL of a request.
+func (s *Signer) verifyRequest(r *http.Request) (*Result, error) {
+	u := requestURL(r)
+	if r.Header.Get(RequestSignatureHeader) != "" {
+		// signed with SignRequest
+		return s.headerSignedURL(u, r.Header).verifyRequestURL(r, u, "")
+	}
+	return s.verifyRequestURL(r, u, "")
+}
+
+// verifyRequestURL verifies the reconstructed URL of a request, which is
+// modified in the process, with the binding, recording its usage if a usage
+// store is configured. As with verifyURL, a result is returned along with
+// ErrExpired.
+func (s *Signer) verifyRequestURL(r *http.Request, u *url.URL, binding string) (*Result, error) {
+	result, err := s.verifyURLAt(r.Context(), u, binding, time.Now())
+	if err != nil {
+		return result, err
+	}
+	if err := checkMethod(result, r.Method); err != nil {
+		return nil, err
+	}
+	if err := s.redeem(r.Context(), result); err != nil {
+		return nil, err
+	}
+	if s.usage != nil {
+		// Errors are ignored so that a failing store does not deny access.
+		_ = s.usage.Record(r.Context(), Usage{
+			LinkID:     result.LinkID,
+			Time:       time.Now(),
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+		})
+	}
+	return result, nil
+}
+
+// errorStatus maps a verification error to an HTTP status code.
+func errorStatus(err error) int {
+	if errors.Is(err, ErrExpired) {
+		return http.StatusGone
+	}
+	return http.StatusForbidden
+}
@@ -0,0 +1,83 @@
+package surl
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_VerifyRequest(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	t.Run("http", func(t *testing.T) {
+		signed, err := signer.Sign("http://example.com/a/b?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		r := httptest.NewRequest("GET", signed, nil)
+		// server requests only populate the path and query
+		r.URL.Scheme, r.URL.Host = "", ""
+
+		assert.NoError(t, signer.VerifyRequest(r))
+	})
+
+	t.Run("https", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		r := httptest.NewRequest("GET", signed, nil)
+		r.URL.Scheme, r.URL.Host = "", ""
+		require.NotNil(t, r.TLS)
+
+		assert.NoError(t, signer.VerifyRequest(r))
+
+		// without TLS the scheme differs
+		r.TLS = nil
+		assert.ErrorIs(t, signer.VerifyRequest(r), ErrInvalidSignature)
+	})
+
+	t.Run("different host", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		r := httptest.NewRequest("GET", signed, nil)
+		r.URL.Scheme, r.URL.Host = "", ""
+		r.Host = "evil.com"
+		r.TLS = &tls.ConnectionState{}
+
+		assert.ErrorIs(t, signer.VerifyRequest(r), ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.VerifyRequest(httptest.NewRequest("GET", signed, nil)), ErrExpired)
+	})
+}
+
+func TestErrorStatus(t *testing.T) {
+	assert.Equal(t, 410, errorStatus(ErrExpired))
+	assert.Equal(t, 403, errorStatus(ErrInvalidSignature))
+}
+
+func TestSigner_VerifyRequestDetailed(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.Sign("https://example.com/a/b?foo=bar", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	result, err := signer.VerifyRequestDetailed(httptest.NewRequest("GET", signed, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a/b?foo=bar", result.OriginalURL.String())
+
+	expired, err := signer.Sign("https://example.com/a/b", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	result, err = signer.VerifyRequestDetailed(httptest.NewRequest("GET", expired, nil))
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.Equal(t, "/a/b", result.OriginalURL.Path)
+}
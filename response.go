@@ -0,0 +1,105 @@
+package surl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// contentDispositionParam is the query parameter that carries the
+	// Content-Disposition header of the response to a signed URL.
+	contentDispositionParam = "response-content-disposition"
+	// contentTypeParam is the query parameter that carries the Content-Type
+	// header of the response to a signed URL.
+	contentTypeParam = "response-content-type"
+)
+
+// ResponseHeaders override headers of the response to a signed URL, in the
+// manner of the response-content-disposition and response-content-type
+// parameters of S3 presigned URLs.
+type ResponseHeaders struct {
+	// ContentDisposition, if non-empty, sets the Content-Disposition header,
+	// e.g. attachment; filename="report.pdf".
+	ContentDisposition string
+	// ContentType, if non-empty, sets the Content-Type header.
+	ContentType string
+}
+
+// SignWithResponseHeaders is like Sign but instructs the handler serving the
+// signed URL to set headers of its response, e.g. to download a file under a
+// particular name. The headers are added to the URL and covered by the
+// signature, so that they cannot be forged, and are set by the middleware and
+// ServeMux in this package before passing requests on.
+func (s *Signer) SignWithResponseHeaders(unsigned string, expiry time.Time, headers ResponseHeaders) (string, error) {
+	for _, v := range []string{headers.ContentDisposition, headers.ContentType} {
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("invalid response header: %q", v)
+		}
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	withHeaders := *s
+	withHeaders.headers = &headers
+	if err := withHeaders.signURL(context.Background(), u, expiry, ""); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// isEmpty reports whether there are no headers to override.
+func (h *ResponseHeaders) isEmpty() bool {
+	return h.ContentDisposition == "" && h.ContentType == ""
+}
+
+// set sets the headers of a response.
+func (h *ResponseHeaders) set(w http.ResponseWriter) {
+	if h.ContentDisposition != "" {
+		w.Header().Set("Content-Disposition", h.ContentDisposition)
+	}
+	if h.ContentType != "" {
+		w.Header().Set("Content-Type", h.ContentType)
+	}
+}
+
+// headersBinding binds the response headers of a signed URL, along with any
+// other binding, to its signature.
+func headersBinding(h *ResponseHeaders, binding string) string {
+	return tagBinding("headers", strconv.Itoa(len(h.ContentDisposition))+":"+h.ContentDisposition+
+		strconv.Itoa(len(h.ContentType))+":"+h.ContentType, binding)
+}
+
+// addHeaders adds the response headers to the query of a signed URL.
+func addHeaders(u *url.URL, h *ResponseHeaders) {
+	if h.ContentDisposition != "" {
+		appendQueryParam(u, contentDispositionParam, h.ContentDisposition)
+	}
+	if h.ContentType != "" {
+		appendQueryParam(u, contentTypeParam, h.ContentType)
+	}
+}
+
+// extractHeaders removes the response headers from the query of a signed URL,
+// returning nil if it has none.
+func extractHeaders(u *url.URL) (*ResponseHeaders, error) {
+	var (
+		h   ResponseHeaders
+		err error
+	)
+	if h.ContentDisposition, err = removeQueryParam(u, contentDispositionParam); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s", ErrInvalidFormat, contentDispositionParam)
+	}
+	if h.ContentType, err = removeQueryParam(u, contentTypeParam); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s", ErrInvalidFormat, contentTypeParam)
+	}
+	if h.isEmpty() {
+		return nil, nil
+	}
+	return &h, nil
+}
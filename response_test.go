@@ -0,0 +1,59 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithResponseHeaders(t *testing.T) {
+	signer := New([]byte("abc123"))
+	headers := ResponseHeaders{
+		ContentDisposition: `attachment; filename="report.pdf"`,
+		ContentType:        "application/octet-stream",
+	}
+
+	signed, err := signer.SignWithResponseHeaders("https://example.com/files/a.txt", time.Now().Add(time.Minute), headers)
+	require.NoError(t, err)
+	assert.Contains(t, signed, "response-content-disposition=")
+	assert.NoError(t, signer.Verify(signed))
+
+	files := fstest.MapFS{"a.txt": {Data: []byte("hello world")}}
+	handler := signer.Middleware(http.StripPrefix("/files", http.FileServer(http.FS(files))))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, headers.ContentDisposition, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, headers.ContentType, w.Header().Get("Content-Type"))
+
+	t.Run("forged", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(contentTypeParam, "text/html")
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("added", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/files/a.txt", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(signed+"&response-content-type=text%2Fhtml"), ErrInvalidSignature)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := signer.SignWithResponseHeaders("https://example.com/files/a.txt", time.Now().Add(time.Minute), ResponseHeaders{
+			ContentType: "text/html\r\nX-Injected: true",
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// Result is the result of successfully verifying a signed URL.
+type Result struct {
+	// OriginalURL is the URL that was originally signed, i.e. the signed URL
+	// with the prefix, signature and expiry removed.
+	OriginalURL *url.URL
+	// ExpiresAt is the time at which the signed URL expires, or the zero time
+	// if it never expires. See WithNoExpiry.
+	ExpiresAt time.Time
+	// Remaining is the time remaining until the signed URL expires, as of its
+	// verification, or zero if it never expires.
+	Remaining time.Duration
+	// KeyID is the ID of the key with which the signed URL was signed, or
+	// empty if it was not signed with a key from a keyring or key function.
+	// See WithKeyring and WithKeyFunc.
+	KeyID string
+	// LinkID identifies the signed URL. It is the encoded signature.
+	LinkID string
+	// ID is the unique ID of the signed URL, or empty if it has none. See
+	// WithRevocationChecker.
+	ID string
+	// MaxUses is the maximum number of uses of the signed URL, or zero if it
+	// is unlimited. See SignWithMaxUses.
+	MaxUses int
+	// Data is the JSON-encoded data embedded in the signed URL, or nil if it
+	// has none. See SignWithData.
+	Data []byte
+	// Methods are the HTTP methods permitted for the signed URL, or nil if
+	// any method is permitted. See SignForMethods.
+	Methods []string
+	// Upload are the constraints on uploads made with the signed URL, or nil
+	// if there are none. See SignUpload.
+	Upload *UploadConstraints
+	// Digest is the expected SHA-256 digest of the content of the signed URL,
+	// or nil if it has none. See SignWithDigest.
+	Digest []byte
+	// Headers are the headers to set on the response to the signed URL, or
+	// nil if there are none. See SignWithResponseHeaders.
+	Headers *ResponseHeaders
+	// Subtree is the path beneath which the signed URL grants access, or
+	// empty if it grants access to itself alone. See SignSubtree.
+	Subtree string
+	// NotBefore is the time from which the signed URL is valid, or the zero
+	// time if it is valid from when it was signed. See SignWindow.
+	NotBefore time.Time
+	// Override is true if the signed URL was signed with the override key.
+	Override bool
+
+	permanent bool // whether the signed URL never expires
+}
+
+// VerifyDetailed is like Verify but returns the result of verification, so
+// that callers need not re-parse the URL to retrieve, e.g., its original URL
+// and expiry. If the signature is valid but the URL has expired then the
+// result is returned along with the error.
+func (s *Signer) VerifyDetailed(signed string) (*Result, error) {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return nil, err
+	}
+	return s.verifyURL(context.Background(), u, "")
+}
+
+// Unsign verifies a signed URL, as with Verify, and returns the URL as it was
+// originally passed to Sign, i.e. with the prefix, signature, expiry and any
+// other parameters added when signing removed, e.g. for forwarding by a proxy.
+func (s *Signer) Unsign(signed string) (string, error) {
+	result, err := s.VerifyDetailed(signed)
+	if err != nil {
+		return "", err
+	}
+	return result.OriginalURL.String(), nil
+}
+
+type resultContextKey struct{}
+
+// newContext returns a copy of the context carrying the result.
+func newContext(ctx context.Context, result *Result) context.Context {
+	return context.WithValue(ctx, resultContextKey{}, result)
+}
+
+// ResultFromContext retrieves the result of verifying a request's signed URL
+// from the request context, populated by handlers and middleware in this
+// package.
+func ResultFromContext(ctx context.Context) (*Result, bool) {
+	result, ok := ctx.Value(resultContextKey{}).(*Result)
+	return result, ok
+}
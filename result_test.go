@@ -0,0 +1,95 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_VerifyDetailed(t *testing.T) {
+	signer := New([]byte("abc123"))
+	expiry := time.Now().Add(time.Hour)
+
+	signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", expiry)
+	require.NoError(t, err)
+
+	result, err := signer.VerifyDetailed(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a/b/c?foo=bar", result.OriginalURL.String())
+	assert.Equal(t, expiry.Unix(), result.ExpiresAt.Unix())
+	assert.InDelta(t, time.Hour, result.Remaining, float64(time.Second))
+	assert.Empty(t, result.KeyID)
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		result, err := signer.VerifyDetailed(signed)
+		assert.ErrorIs(t, err, ErrExpired)
+		require.NotNil(t, result)
+		assert.Equal(t, time.Duration(0), result.Remaining)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		result, err := signer.VerifyDetailed(signed + "&baz=qux")
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		assert.Nil(t, result)
+	})
+
+	t.Run("key ID", func(t *testing.T) {
+		keyring, err := NewKeyring("2024-01", map[string][]byte{
+			"2024-01": []byte("abc123"),
+		})
+		require.NoError(t, err)
+		signer := New([]byte("legacy"), WithKeyring(keyring))
+
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		result, err := signer.VerifyDetailed(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01", result.KeyID)
+	})
+
+	t.Run("never expires", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNoExpiry())
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Time{})
+		require.NoError(t, err)
+
+		result, err := signer.VerifyDetailed(signed)
+		require.NoError(t, err)
+		assert.True(t, result.ExpiresAt.IsZero())
+		assert.Equal(t, time.Duration(0), result.Remaining)
+	})
+}
+
+func TestSigner_Unsign(t *testing.T) {
+	for _, opt := range []Option{WithQueryFormatter(), WithPathFormatter()} {
+		signer := New([]byte("abc123"), opt, PrefixPath("/signed"))
+
+		for _, unsigned := range []string{
+			"https://example.com/a/b/c",
+			"https://example.com/a/b/c?foo=bar&baz=qux",
+			"https://example.com/a%2Fb/c?foo=bar",
+			"/a/b/c?foo=bar",
+		} {
+			signed, err := signer.SignFor(unsigned, time.Minute)
+			require.NoError(t, err)
+
+			got, err := signer.Unsign(signed)
+			require.NoError(t, err)
+			assert.Equal(t, unsigned, got)
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		signer := New([]byte("abc123"))
+		signed, err := New([]byte("xyz789")).SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		_, err = signer.Unsign(signed)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
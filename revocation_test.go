@@ -0,0 +1,115 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRevocationChecker(t *testing.T) {
+	revoked := make(map[string]bool)
+	checker := func(ctx context.Context, id string) (bool, error) {
+		return revoked[id], nil
+	}
+
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), f.formatter, WithRevocationChecker(checker))
+
+			signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			other, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			id, err := URLID(signed)
+			require.NoError(t, err)
+			otherID, err := URLID(other)
+			require.NoError(t, err)
+			require.NotEmpty(t, id)
+			assert.NotEqual(t, id, otherID)
+
+			assert.NoError(t, signer.Verify(signed))
+
+			revoked[id] = true
+			assert.ErrorIs(t, signer.Verify(signed), ErrRevoked)
+			assert.NoError(t, signer.Verify(other))
+
+			t.Run("tampered ID", func(t *testing.T) {
+				u, err := url.Parse(signed)
+				require.NoError(t, err)
+				q := u.Query()
+				q.Set("signature_id", otherID)
+				u.RawQuery = q.Encode()
+
+				assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+			})
+
+			t.Run("malformed ID", func(t *testing.T) {
+				u, err := url.Parse(signed)
+				require.NoError(t, err)
+				q := u.Query()
+				q.Set("signature_id", "xyz")
+				u.RawQuery = q.Encode()
+
+				assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidFormat)
+			})
+
+			t.Run("methods folded into ID", func(t *testing.T) {
+				signed, err := signer.SignForMethods("https://example.com/a/b/c", time.Now().Add(time.Minute), "PUT")
+				require.NoError(t, err)
+				id, err := URLID(signed)
+				require.NoError(t, err)
+
+				u, err := url.Parse(signed)
+				require.NoError(t, err)
+				q := u.Query()
+				q.Set("signature_id", id+":methods:PUT")
+				q.Del(methodsParam)
+				u.RawQuery = q.Encode()
+
+				assert.Error(t, signer.Verify(u.String()))
+			})
+
+			t.Run("removed ID", func(t *testing.T) {
+				u, err := url.Parse(signed)
+				require.NoError(t, err)
+				q := u.Query()
+				q.Del("signature_id")
+				u.RawQuery = q.Encode()
+
+				assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+			})
+
+			t.Run("verified without checker", func(t *testing.T) {
+				assert.NoError(t, New([]byte("abc123"), f.formatter).Verify(signed))
+			})
+		})
+	}
+
+	t.Run("without ID", func(t *testing.T) {
+		signed, err := New([]byte("abc123")).Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		id, err := URLID(signed)
+		require.NoError(t, err)
+		assert.Empty(t, id)
+
+		assert.NoError(t, New([]byte("abc123"), WithRevocationChecker(checker)).Verify(signed))
+	})
+
+	t.Run("checker error", func(t *testing.T) {
+		unavailable := errors.New("store unavailable")
+		signer := New([]byte("abc123"), WithRevocationChecker(func(context.Context, string) (bool, error) {
+			return false, unavailable
+		}))
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(signed), unavailable)
+	})
+}
@@ -0,0 +1,299 @@
+// Package rewrite signs links in HTML as it streams from a reader to a
+// writer, e.g. the asset links in a generated page.
+package rewrite
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+// DefaultAttributes are the attributes whose links are signed by default.
+var DefaultAttributes = []string{"href", "src"}
+
+// MatchFunc reports whether to sign the link in the attribute of an element.
+// The tag and attribute names are lower case, and the link is unescaped.
+type MatchFunc func(tag, attr, link string) bool
+
+// HasPrefix matches links with any of the prefixes, e.g. /assets/.
+func HasPrefix(prefixes ...string) MatchFunc {
+	return func(_, _, link string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(link, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Rewriter signs the links in HTML.
+type Rewriter struct {
+	// Signer signs the links.
+	Signer *surl.Signer
+	// TTL is the lifespan of the signed links. Every link signed by a call
+	// to Rewrite has the same expiry.
+	TTL time.Duration
+	// Match selects the links to sign. It must be non-nil.
+	Match MatchFunc
+	// Attributes are the names of the attributes, in lower case, whose links
+	// are signed. If empty, DefaultAttributes is used.
+	Attributes []string
+}
+
+// Rewrite copies HTML from src to dst, signing the links selected by Match.
+// The HTML is streamed rather than read in full, and is otherwise copied
+// verbatim. Comments, and the contents of script and style elements, are
+// left untouched. If signing a link fails then the error is returned and dst
+// holds the HTML up to that link.
+func (rw *Rewriter) Rewrite(dst io.Writer, src io.Reader) error {
+	if rw.Match == nil {
+		return errors.New("rewrite: nil Match")
+	}
+	s := &stream{
+		Rewriter: rw,
+		r:        bufio.NewReader(src),
+		w:        bufio.NewWriter(dst),
+		expiry:   time.Now().Add(rw.TTL),
+	}
+	if err := s.run(); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// stream is the state of a single call to Rewrite.
+type stream struct {
+	*Rewriter
+
+	r      *bufio.Reader
+	w      *bufio.Writer
+	expiry time.Time
+}
+
+func (s *stream) run() error {
+	for {
+		// copy text up to the next tag
+		text, err := s.r.ReadBytes('<')
+		if err == io.EOF {
+			_, err = s.w.Write(text)
+			return err
+		} else if err != nil {
+			return err
+		}
+		s.w.Write(text)
+
+		next, err := s.r.Peek(1)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		switch {
+		case bytes.HasPrefix(s.peek(3), []byte("!--")):
+			err = s.copyUntil("-->")
+		case next[0] == '/' || next[0] == '!' || next[0] == '?':
+			// end tag, doctype or processing instruction
+			err = s.copyUntil(">")
+		case isLetter(next[0]):
+			err = s.startTag()
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// peek returns up to n of the next bytes without consuming them.
+func (s *stream) peek(n int) []byte {
+	b, _ := s.r.Peek(n)
+	return b
+}
+
+// copyUntil copies everything up to and including the delimiter.
+func (s *stream) copyUntil(delim string) error {
+	var b []byte
+	for !bytes.HasSuffix(b, []byte(delim)) {
+		chunk, err := s.r.ReadBytes(delim[len(delim)-1])
+		b = append(b, chunk...)
+		if err != nil {
+			s.w.Write(b)
+			return err
+		}
+	}
+	_, err := s.w.Write(b)
+	return err
+}
+
+// copyRawText copies the contents of a script or style element, along with
+// its end tag.
+func (s *stream) copyRawText(tag string) error {
+	end := []byte("/" + tag)
+	for {
+		text, err := s.r.ReadBytes('<')
+		if err != nil {
+			s.w.Write(text)
+			return err
+		}
+		if bytes.EqualFold(s.peek(len(end)), end) {
+			s.w.Write(text)
+			return s.copyUntil(">")
+		}
+		s.w.Write(text)
+	}
+}
+
+// startTag rewrites a start tag, the opening < of which has already been
+// copied.
+func (s *stream) startTag() error {
+	tag, err := s.readTag()
+	if err != nil {
+		s.w.Write(tag)
+		return err
+	}
+	name, rewritten, err := s.rewriteTag(tag)
+	if err != nil {
+		return err
+	}
+	if _, err := s.w.Write(rewritten); err != nil {
+		return err
+	}
+	if (name == "script" || name == "style") && !bytes.HasSuffix(tag, []byte("/>")) {
+		return s.copyRawText(name)
+	}
+	return nil
+}
+
+// readTag reads the remainder of a tag, up to and including the closing >,
+// which is ignored within quoted attribute values.
+func (s *stream) readTag() ([]byte, error) {
+	var (
+		tag   []byte
+		quote byte
+	)
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return tag, err
+		}
+		tag = append(tag, c)
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return tag, nil
+		}
+	}
+}
+
+// rewriteTag signs the links in the attributes of a tag, returning the name
+// of the tag along with the rewritten tag.
+func (s *stream) rewriteTag(tag []byte) (string, []byte, error) {
+	i := 0
+	for i < len(tag) && !isSpace(tag[i]) && tag[i] != '/' && tag[i] != '>' {
+		i++
+	}
+	name := strings.ToLower(string(tag[:i]))
+
+	var out bytes.Buffer
+	out.Write(tag[:i])
+	for i < len(tag) {
+		// copy whitespace and stray slashes preceding the attribute
+		start := i
+		for i < len(tag) && (isSpace(tag[i]) || tag[i] == '/' || tag[i] == '>') {
+			i++
+		}
+		out.Write(tag[start:i])
+
+		// attribute name
+		start = i
+		for i < len(tag) && !isSpace(tag[i]) && tag[i] != '=' && tag[i] != '/' && tag[i] != '>' {
+			i++
+		}
+		attr := strings.ToLower(string(tag[start:i]))
+		out.Write(tag[start:i])
+
+		// optional value, preceded by = and optional whitespace
+		j := i
+		for j < len(tag) && isSpace(tag[j]) {
+			j++
+		}
+		if j == len(tag) || tag[j] != '=' {
+			continue
+		}
+		j++
+		for j < len(tag) && isSpace(tag[j]) {
+			j++
+		}
+		out.Write(tag[i:j])
+		i = j
+
+		start = i
+		var value []byte
+		if i < len(tag) && (tag[i] == '"' || tag[i] == '\'') {
+			quote := tag[i]
+			end := bytes.IndexByte(tag[i+1:], quote)
+			if end < 0 {
+				out.Write(tag[i:])
+				break
+			}
+			value = tag[i+1 : i+1+end]
+			i += end + 2
+		} else {
+			for i < len(tag) && !isSpace(tag[i]) && tag[i] != '>' {
+				i++
+			}
+			value = tag[start:i]
+		}
+		if !s.isLinkAttribute(attr) {
+			out.Write(tag[start:i])
+			continue
+		}
+		link := html.UnescapeString(string(value))
+		if !s.Match(name, attr, link) {
+			out.Write(tag[start:i])
+			continue
+		}
+		signed, err := s.Signer.Sign(link, s.expiry)
+		if err != nil {
+			return "", nil, err
+		}
+		out.WriteByte('"')
+		out.WriteString(html.EscapeString(signed))
+		out.WriteByte('"')
+	}
+	return name, out.Bytes(), nil
+}
+
+func (s *stream) isLinkAttribute(attr string) bool {
+	attrs := s.Attributes
+	if len(attrs) == 0 {
+		attrs = DefaultAttributes
+	}
+	for _, a := range attrs {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,74 @@
+package rewrite
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriter(t *testing.T) {
+	signer := surl.New([]byte("abc123"))
+	rw := &Rewriter{
+		Signer: signer,
+		TTL:    time.Hour,
+		Match:  HasPrefix("/assets/"),
+	}
+
+	src := `<!DOCTYPE html>
+<html>
+<head>
+<link rel="stylesheet" href="/assets/site.css">
+<script src='/assets/app.js?v=1&amp;x=2'></script>
+<script>if (a<b) { document.write('<img src="/assets/ignored.png">') }</script>
+<style>a > b { color: red }</style>
+</head>
+<body>
+<!-- <img src="/assets/commented.png"> -->
+<IMG SRC=/assets/logo.png alt="a > b" data-src="/assets/lazy.png">
+<a href="/about" class=x>about</a>
+<input disabled>
+</body>
+</html>
+`
+	var dst strings.Builder
+	require.NoError(t, rw.Rewrite(&dst, strings.NewReader(src)))
+	got := dst.String()
+
+	links := regexp.MustCompile(`(?i)(?:href|src)="([^"]+signature=[^"]+)"`).FindAllStringSubmatch(got, -1)
+	require.Len(t, links, 3)
+	for _, link := range links {
+		assert.NoError(t, signer.Verify(html.UnescapeString(link[1])))
+	}
+	assert.Contains(t, links[1][1], "/assets/app.js?v=1&amp;x=2&amp;expiry=")
+
+	// everything else is copied verbatim
+	unsigned := regexp.MustCompile(`(?i)(href|src)="([^"?]+)\?[^"]*signature=[^"]+"`).ReplaceAllString(got, "$1=$2")
+	for _, want := range []string{
+		`<!DOCTYPE html>`,
+		`<script>if (a<b) { document.write('<img src="/assets/ignored.png">') }</script>`,
+		`<style>a > b { color: red }</style>`,
+		`<!-- <img src="/assets/commented.png"> -->`,
+		`<IMG SRC=/assets/logo.png alt="a > b" data-src="/assets/lazy.png">`,
+		`<a href="/about" class=x>about</a>`,
+		`<input disabled>`,
+	} {
+		assert.Contains(t, unsigned, want)
+	}
+
+	t.Run("nil match", func(t *testing.T) {
+		err := (&Rewriter{Signer: signer}).Rewrite(&strings.Builder{}, strings.NewReader(src))
+		assert.Error(t, err)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		var dst strings.Builder
+		require.NoError(t, rw.Rewrite(&dst, strings.NewReader(`<p>text <a href="/assets/x`)))
+		assert.Equal(t, `<p>text <a href="/assets/x`, dst.String())
+	})
+}
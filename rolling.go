@@ -0,0 +1,61 @@
+package surl
+
+import (
+	"net/http"
+	"time"
+)
+
+// RollingExpiry renews signed URLs that are within the window of expiring,
+// implementing sliding sessions for, e.g., long downloads and dashboards.
+// Rather than passing GET and HEAD requests with such URLs to the next
+// handler, the middleware redirects them with 307 Temporary Redirect to the
+// URL signed afresh, expiring after the time-to-live, as with Signer.Extend.
+// The renewed URL is given as a path and query, relative to the request.
+// Requests with other methods are passed to the next handler as normal,
+// unless RenewalHeader is also given.
+//
+// URLs that are single-use, have a maximum number of uses, or are verified
+// with BindClient or RequireBinding, are never renewed, because renewal would
+// reset their uses or strip their binding.
+func RollingExpiry(window, ttl time.Duration) MiddlewareOption {
+	return func(m *middleware) {
+		m.rollingWindow = window
+		m.rollingTTL = ttl
+	}
+}
+
+// RenewalHeader instructs RollingExpiry to return renewed URLs in the named
+// response header, e.g. Signed-URL, on requests with any method, rather than
+// redirecting, leaving the client to pick up the renewed URL for subsequent
+// requests.
+func RenewalHeader(name string) MiddlewareOption {
+	return func(m *middleware) {
+		m.renewalHeader = name
+	}
+}
+
+// roll renews the URL of the request if it is within the window of expiring,
+// returning true if the request has been redirected to the renewed URL.
+func (m *middleware) roll(w http.ResponseWriter, r *http.Request, result *Result) bool {
+	if m.rollingTTL == 0 || result.permanent || time.Until(result.ExpiresAt) > m.rollingWindow {
+		return false
+	}
+	if m.bindClient || m.binding != nil || result.MaxUses > 0 || (result.ID != "" && m.signer.nonces != nil) {
+		return false
+	}
+	redirect := m.renewalHeader == "" && (r.Method == http.MethodGet || r.Method == http.MethodHead)
+	if !redirect && m.renewalHeader == "" {
+		return false
+	}
+	renewed, err := m.signer.renew(r.Context(), result, r.URL.Query().Get(scopeParam), time.Now().Add(m.rollingTTL))
+	if err != nil {
+		// Leave the URL to expire rather than fail a valid request.
+		return false
+	}
+	if !redirect {
+		w.Header().Set(m.renewalHeader, renewed.RequestURI())
+		return false
+	}
+	http.Redirect(w, r, renewed.RequestURI(), http.StatusTemporaryRedirect)
+	return true
+}
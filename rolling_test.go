@@ -0,0 +1,83 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollingExpiry(t *testing.T) {
+	signer := New([]byte("abc123"))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	rolling := signer.Middleware(next, RollingExpiry(5*time.Minute, time.Hour))
+
+	expiring, err := signer.SignFor("https://example.com/a/b/c?foo=bar", time.Minute)
+	require.NoError(t, err)
+
+	t.Run("redirects", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rolling.ServeHTTP(w, httptest.NewRequest("GET", expiring, nil))
+
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		renewed := w.Header().Get("Location")
+		assert.NoError(t, signer.VerifyAt("https://example.com"+renewed, time.Now().Add(59*time.Minute)))
+
+		// the renewed URL is passed through
+		w = httptest.NewRecorder()
+		rolling.ServeHTTP(w, httptest.NewRequest("GET", "https://example.com"+renewed, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("outside window", func(t *testing.T) {
+		signed, err := signer.SignFor("https://example.com/a/b/c", 10*time.Minute)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		rolling.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("other method", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rolling.ServeHTTP(w, httptest.NewRequest("POST", expiring, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("renewal header", func(t *testing.T) {
+		rolling := signer.Middleware(next, RollingExpiry(5*time.Minute, time.Hour), RenewalHeader("Signed-URL"))
+		w := httptest.NewRecorder()
+		rolling.ServeHTTP(w, httptest.NewRequest("POST", expiring, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		renewed := w.Header().Get("Signed-URL")
+		require.NotEmpty(t, renewed)
+		assert.NoError(t, signer.VerifyAt("https://example.com"+renewed, time.Now().Add(59*time.Minute)))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		signer := New([]byte("abc123"), PrefixPath("/signed"))
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		signer.Middleware(next, RollingExpiry(5*time.Minute, time.Hour)).ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.NoError(t, signer.Verify("https://example.com"+w.Header().Get("Location")))
+	})
+
+	t.Run("single use", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithNonceStore(&MemoryNonceStore{}))
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		signer.Middleware(next, RollingExpiry(5*time.Minute, time.Hour)).ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,44 @@
+package surl
+
+import (
+	"context"
+	"errors"
+)
+
+// fallbackKey is a previous key whose signatures are still accepted.
+type fallbackKey struct {
+	key []byte
+	alg algorithm
+}
+
+// WithFallbackKeys instructs Signer to also accept signatures produced with
+// any of the given keys, tried in order, whilst only signing with its own key.
+// This permits the key to be rotated without immediately invalidating every
+// outstanding URL: make the new key the signer's key and the old key a
+// fallback key, and once URLs signed with the old key have expired, remove
+// it. Fallback keys apply to everything the signer verifies, including tokens
+// and webhooks.
+func WithFallbackKeys(keys ...[]byte) Option {
+	return func(s *Signer) {
+		s.fallbacks = make([]fallbackKey, len(keys))
+		for i, key := range keys {
+			if s.purpose != "" {
+				key = deriveKey(key, "surl purpose "+s.purpose)
+			}
+			s.fallbacks[i] = fallbackKey{key: key, alg: s.keyedHashFor(key)}
+		}
+	}
+}
+
+// verifyFallbacks verifies the signature using each of the fallback keys in
+// turn, until one succeeds or fails for a reason other than an invalid
+// signature.
+func (s *Signer) verifyFallbacks(ctx context.Context, data, sig []byte) error {
+	err := ErrInvalidSignature
+	for _, fb := range s.fallbacks {
+		if err = s.verifyWith(ctx, fb.alg, data, sig); !errors.Is(err, ErrInvalidSignature) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFallbackKeys(t *testing.T) {
+	old := New([]byte("old"))
+	older := New([]byte("older"))
+	signer := New([]byte("new"), WithFallbackKeys([]byte("old"), []byte("older")))
+
+	sign := func(s *Signer) string {
+		signed, err := s.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		return signed
+	}
+
+	t.Run("verifies with primary and fallback keys", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(sign(signer)))
+		assert.NoError(t, signer.Verify(sign(old)))
+		assert.NoError(t, signer.Verify(sign(older)))
+		assert.ErrorIs(t, signer.Verify(sign(New([]byte("other")))), ErrInvalidSignature)
+	})
+
+	t.Run("signs with primary key", func(t *testing.T) {
+		assert.NoError(t, New([]byte("new")).Verify(sign(signer)))
+		assert.ErrorIs(t, old.Verify(sign(signer)), ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := old.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+
+	t.Run("tokens", func(t *testing.T) {
+		token, err := old.SignBytes([]byte("data"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		got, err := signer.VerifyBytes(token)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(got))
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		signed, err := old.Scoped("/a").Sign("https://example.com/a/b", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		assert.NoError(t, signer.Scoped("/a").Verify(signed))
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		signer := New([]byte("new"), WithFallbackKeys([]byte("old")), WithHMACSHA256())
+
+		assert.NoError(t, signer.Verify(sign(New([]byte("old"), WithHMACSHA256()))))
+	})
+
+	t.Run("config", func(t *testing.T) {
+		got := signer.Config()
+		assert.Equal(t, []string{old.Config().KeyFingerprint, older.Config().KeyFingerprint}, got.FallbackKeyFingerprints)
+		assert.Contains(t, got.String(), "fallback_key_fingerprints="+old.Config().KeyFingerprint+","+older.Config().KeyFingerprint)
+	})
+}
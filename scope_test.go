@@ -0,0 +1,96 @@
+package surl
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Scoped(t *testing.T) {
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			parent := New([]byte("abc123"), f.formatter, PrefixPath("/signed"))
+			scoped := parent.Scoped("/tenants/123")
+
+			signed, err := scoped.Sign("https://example.com/tenants/123/a/b/c?foo=bar", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			t.Run("parent verifies", func(t *testing.T) {
+				err := parent.Verify(signed)
+				require.NoError(t, err)
+			})
+
+			t.Run("scoped verifies", func(t *testing.T) {
+				err := scoped.Verify(signed)
+				require.NoError(t, err)
+			})
+
+			t.Run("other scope rejects", func(t *testing.T) {
+				err := parent.Scoped("/tenants/456").Verify(signed)
+				assert.Truef(t, errors.Is(err, ErrOutOfScope), "got error: %v", err)
+			})
+		})
+	}
+
+	parent := New([]byte("abc123"))
+	scoped := parent.Scoped("/tenants/123")
+
+	t.Run("sign out of scope", func(t *testing.T) {
+		for _, unsigned := range []string{
+			"https://example.com/tenants/456/a",
+			"https://example.com/tenants/1234/a",
+			"https://example.com/",
+			"https://example.com/tenants/123/../456/a",
+			"https://example.com/tenants/123/./a",
+		} {
+			_, err := scoped.Sign(unsigned, time.Now().Add(time.Minute))
+			assert.Truef(t, errors.Is(err, ErrOutOfScope), "got error: %v", err)
+		}
+	})
+
+	t.Run("escape scope with dot segments", func(t *testing.T) {
+		// sign with the scoped key, bypassing the scoped signer's own check
+		escaper := New(deriveKey(parent.key, "surl scope /tenants/123"))
+		signed, err := escaper.Sign("https://example.com/tenants/123/../456/secret", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		addScope(u, "/tenants/123")
+
+		err = parent.Verify(u.String())
+		assert.Truef(t, errors.Is(err, ErrOutOfScope), "got error: %v", err)
+	})
+
+	t.Run("widened scope", func(t *testing.T) {
+		signed, err := scoped.Sign("https://example.com/tenants/123/a", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(scopeParam, "/tenants")
+		u.RawQuery = q.Encode()
+
+		err = parent.Verify(u.String())
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("scope removed", func(t *testing.T) {
+		signed, err := scoped.Sign("https://example.com/tenants/123/a", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Del(scopeParam)
+		u.RawQuery = q.Encode()
+
+		err = parent.Verify(u.String())
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+}
@@ -0,0 +1,62 @@
+package surl
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// ServeMux is an http.ServeMux that only routes requests with valid, unexpired,
+// signed URLs. Requests that fail verification receive a 403 Forbidden
+// response, or a 410 Gone response if the URL has expired.
+//
+// Before routing, the signature and expiry are removed from the path of the
+// request, so that patterns match the path as it was before it was signed.
+// The prefix that matched, if any, is retained, so that patterns such as
+// "GET /signed/{rest...}" match. The result of verification is available to
+// handlers via ResultFromContext.
+type ServeMux struct {
+	*http.ServeMux
+
+	// Expired, if non-nil, responds to requests with signed URLs that have
+	// a valid signature but have expired, in place of the 410 Gone response.
+	Expired ExpiredHandler
+
+	signer *Signer
+}
+
+// NewServeMux constructs a ServeMux that verifies requests using the signer.
+func NewServeMux(signer *Signer) *ServeMux {
+	return &ServeMux{
+		ServeMux: http.NewServeMux(),
+		signer:   signer,
+	}
+}
+
+// ServeHTTP verifies the request and dispatches it to the handler whose
+// pattern most closely matches the request.
+func (m *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result, err := m.signer.verifyRequest(r)
+	if errors.Is(err, ErrExpired) && m.Expired != nil {
+		m.Expired(w, r, result)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if result.Headers != nil {
+		result.Headers.set(w)
+	}
+	prefix, _ := m.signer.matchPrefix(r.URL.Path)
+	r2 := r.WithContext(newContext(r.Context(), result))
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = prefix + result.OriginalURL.Path
+	r2.URL.RawPath = ""
+	if result.OriginalURL.RawPath != "" {
+		r2.URL.RawPath = prefix + result.OriginalURL.RawPath
+	}
+
+	m.ServeMux.ServeHTTP(w, r2)
+}
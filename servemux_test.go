@@ -0,0 +1,126 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeMux(t *testing.T) {
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), f.formatter, PrefixPath("/signed"))
+
+			mux := NewServeMux(signer)
+			mux.HandleFunc("GET /signed/files/{rest...}", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(r.PathValue("rest")))
+			})
+
+			tests := []struct {
+				name     string
+				method   string
+				unsigned string
+				expiry   time.Time
+				tamper   func(string) string
+				want     int
+				body     string
+			}{
+				{
+					name:     "valid",
+					unsigned: "https://example.com/files/a/b/c?foo=bar",
+					expiry:   time.Now().Add(time.Minute),
+					want:     http.StatusOK,
+					body:     "a/b/c",
+				},
+				{
+					name:     "method mismatch",
+					method:   "POST",
+					unsigned: "https://example.com/files/a/b/c?foo=bar",
+					expiry:   time.Now().Add(time.Minute),
+					want:     http.StatusMethodNotAllowed,
+				},
+				{
+					name:     "pattern mismatch",
+					unsigned: "https://example.com/other/a/b/c?foo=bar",
+					expiry:   time.Now().Add(time.Minute),
+					want:     http.StatusNotFound,
+				},
+				{
+					name:     "expired",
+					unsigned: "https://example.com/files/a/b/c?foo=bar",
+					expiry:   time.Now().Add(-time.Minute),
+					want:     http.StatusGone,
+				},
+				{
+					name:     "tampered",
+					unsigned: "https://example.com/files/a/b/c?foo=bar",
+					expiry:   time.Now().Add(time.Minute),
+					tamper:   func(s string) string { return s + "&foo=baz" },
+					want:     http.StatusForbidden,
+				},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					signed, err := signer.Sign(tt.unsigned, tt.expiry)
+					require.NoError(t, err)
+					if tt.tamper != nil {
+						signed = tt.tamper(signed)
+					}
+					method := tt.method
+					if method == "" {
+						method = "GET"
+					}
+
+					w := httptest.NewRecorder()
+					mux.ServeHTTP(w, httptest.NewRequest(method, signed, nil))
+
+					assert.Equal(t, tt.want, w.Code)
+					if tt.body != "" {
+						assert.Equal(t, tt.body, w.Body.String())
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestServeMux_PrefixPaths(t *testing.T) {
+	signer := New([]byte("abc123"), PrefixPaths("/signed", "/s"))
+
+	mux := NewServeMux(signer)
+	mux.HandleFunc("GET /s/files/{rest...}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("rest")))
+	})
+
+	signed, err := signer.Sign("https://example.com/files/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", strings.Replace(signed, "/signed/", "/s/", 1), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a/b/c", w.Body.String())
+}
+
+func TestServeMux_EscapedPath(t *testing.T) {
+	signer := New([]byte("abc123"), PrefixPath("/signed"))
+
+	mux := NewServeMux(signer)
+	mux.HandleFunc("GET /signed/files/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("name")))
+	})
+
+	signed, err := signer.Sign("https://example.com/files/a%2Fb", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a/b", w.Body.String())
+}
@@ -0,0 +1,160 @@
+// Package service exposes a Signer over HTTP, so that services written in
+// other languages can sign and verify URLs compatible with this package.
+//
+// The handler serves two JSON endpoints:
+//
+//	POST /sign   {"url": "https://example.com/a", "ttl": "1h"}
+//	             -> {"url": "https://example.com/a?expiry=..&signature=..", "expiry": "2024-01-01T00:00:00Z"}
+//	POST /verify {"url": "https://example.com/a?expiry=..&signature=.."}
+//	             -> {"valid": true}
+//
+// Instead of a ttl, the sign endpoint accepts an expiry in RFC 3339 format.
+// The verify endpoint responds with a 200 OK status whether or not the URL is
+// valid, and if it is invalid, with the reason in the error field. Errors with
+// requests receive an error status and a JSON body with an error field.
+//
+// The handler does not authenticate requests, and anyone able to reach it is
+// able to sign URLs. Mount it on an internal address, or wrap it with
+// authentication.
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+// maxBodySize is the maximum size of a request body.
+const maxBodySize = 64 << 10
+
+// Handler serves the signing and verification endpoints.
+type Handler struct {
+	// Signer signs and verifies URLs.
+	Signer *surl.Signer
+	// MaxTTL, if non-zero, is the maximum lifespan of a signed URL.
+	// Requests to sign URLs expiring later are rejected.
+	MaxTTL time.Duration
+}
+
+// SignRequest is the body of a request to sign a URL.
+type SignRequest struct {
+	// URL is the URL to sign.
+	URL string `json:"url"`
+	// TTL is the lifespan of the signed URL, parsed by time.ParseDuration.
+	TTL string `json:"ttl,omitempty"`
+	// Expiry is the expiry of the signed URL. It is ignored if TTL is set.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// SignResponse is the body of a response to a request to sign a URL.
+type SignResponse struct {
+	// URL is the signed URL.
+	URL string `json:"url"`
+	// Expiry is the expiry of the signed URL.
+	Expiry time.Time `json:"expiry"`
+}
+
+// VerifyRequest is the body of a request to verify a signed URL.
+type VerifyRequest struct {
+	// URL is the signed URL to verify.
+	URL string `json:"url"`
+}
+
+// VerifyResponse is the body of a response to a request to verify a signed
+// URL.
+type VerifyResponse struct {
+	// Valid is true if the signed URL is valid and unexpired.
+	Valid bool `json:"valid"`
+	// Error is the reason the signed URL is invalid.
+	Error string `json:"error,omitempty"`
+}
+
+// errorResponse is the body of a response to an erroneous request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP serves the endpoints.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/sign":
+		h.serve(w, r, h.sign)
+	case "/verify":
+		h.serve(w, r, h.verify)
+	default:
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+	}
+}
+
+// serve responds to a POST request with the response returned by fn, or with
+// its error.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, fn func(*http.Request) (any, error)) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+	resp, err := fn(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) sign(r *http.Request) (any, error) {
+	var req SignRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	expiry := req.Expiry
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl: %w", err)
+		}
+		expiry = time.Now().Add(ttl)
+	}
+	if expiry.IsZero() {
+		return nil, errors.New("missing ttl or expiry")
+	}
+	if h.MaxTTL > 0 && time.Until(expiry) > h.MaxTTL {
+		return nil, fmt.Errorf("expiry exceeds maximum ttl of %s", h.MaxTTL)
+	}
+	signed, err := h.Signer.Sign(req.URL, expiry)
+	if err != nil {
+		return nil, err
+	}
+	return SignResponse{URL: signed, Expiry: expiry.UTC().Truncate(time.Second)}, nil
+}
+
+func (h *Handler) verify(r *http.Request) (any, error) {
+	var req VerifyRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if err := h.Signer.VerifyContext(r.Context(), req.URL); err != nil {
+		return VerifyResponse{Error: err.Error()}, nil
+	}
+	return VerifyResponse{Valid: true}, nil
+}
+
+// decode decodes the JSON body of the request.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
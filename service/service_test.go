@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	signer := surl.New([]byte("abc123"))
+	h := &Handler{Signer: signer, MaxTTL: 24 * time.Hour}
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	t.Run("sign", func(t *testing.T) {
+		w := do("POST", "/sign", `{"url":"https://example.com/a/b/c","ttl":"1h"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var resp SignResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.NoError(t, signer.Verify(resp.URL))
+		assert.WithinDuration(t, time.Now().Add(time.Hour), resp.Expiry, 2*time.Second)
+	})
+
+	t.Run("sign with expiry", func(t *testing.T) {
+		expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		w := do("POST", "/sign", `{"url":"https://example.com/a/b/c","expiry":"`+expiry.Format(time.RFC3339)+`"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp SignResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, expiry, resp.Expiry)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		valid, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		expired, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		w := do("POST", "/verify", `{"url":"`+valid+`"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"valid":true}`, w.Body.String())
+
+		w = do("POST", "/verify", `{"url":"`+expired+`"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"valid":false,"error":"`+surl.ErrExpired.Error()+`"}`, w.Body.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			method string
+			path   string
+			body   string
+			want   int
+		}{
+			{"unknown path", "POST", "/foo", `{}`, http.StatusNotFound},
+			{"wrong method", "GET", "/sign", ``, http.StatusMethodNotAllowed},
+			{"invalid json", "POST", "/sign", `{`, http.StatusBadRequest},
+			{"unknown field", "POST", "/sign", `{"uri":"https://example.com"}`, http.StatusBadRequest},
+			{"missing expiry", "POST", "/sign", `{"url":"https://example.com"}`, http.StatusBadRequest},
+			{"invalid ttl", "POST", "/sign", `{"url":"https://example.com","ttl":"forever"}`, http.StatusBadRequest},
+			{"exceeds max ttl", "POST", "/sign", `{"url":"https://example.com","ttl":"48h"}`, http.StatusBadRequest},
+			{"invalid url", "POST", "/sign", `{"url":"example.com","ttl":"1h"}`, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := do(tt.method, tt.path, tt.body)
+				assert.Equal(t, tt.want, w.Code)
+
+				var resp errorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.NotEmpty(t, resp.Error)
+			})
+		}
+	})
+}
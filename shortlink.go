@@ -0,0 +1,132 @@
+package surl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when a short link does not exist or has expired.
+var ErrNotFound = errors.New("not found")
+
+// ShortLinkStore stores signed URLs keyed by short code.
+type ShortLinkStore interface {
+	// Put stores the signed URL under the code until the expiry.
+	Put(ctx context.Context, code, signed string, expiry time.Time) error
+	// Get retrieves the signed URL stored under the code, returning
+	// ErrNotFound if it does not exist or has expired.
+	Get(ctx context.Context, code string) (string, error)
+}
+
+// Shortener issues short links for signed URLs, for when a signed URL is too
+// long, e.g. for an SMS or a QR code. Each short link consists of a base URL
+// and a random code, which is mapped to the signed URL in a store. Shortener
+// is also an http.Handler that resolves short links, redirecting to the signed
+// URL.
+type Shortener struct {
+	// Signer signs and verifies URLs.
+	Signer *Signer
+	// Store maps codes to signed URLs.
+	Store ShortLinkStore
+	// Base is the URL to which codes are appended to form short links, e.g.
+	// https://exa.mp/s/. The Shortener should handle requests to this URL.
+	Base string
+}
+
+// Shorten signs the URL and returns a short link to it. The short link expires
+// along with the signed URL.
+func (s *Shortener) Shorten(ctx context.Context, unsigned string, expiry time.Time) (string, error) {
+	signed, err := s.Signer.Sign(unsigned, expiry)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := base64.RawURLEncoding.EncodeToString(b)
+	if err := s.Store.Put(ctx, code, signed, expiry); err != nil {
+		return "", err
+	}
+	return s.Base + code, nil
+}
+
+// Resolve retrieves and verifies the signed URL for a code. A use of the
+// signed URL, or its nonce, is not redeemed: that is left to the verifier of
+// the URL to which the short link redirects.
+func (s *Shortener) Resolve(ctx context.Context, code string) (string, error) {
+	signed, err := s.Store.Get(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.Signer.verifyURLAt(ctx, u, "", time.Now()); err != nil {
+		return "", err
+	}
+	return signed, nil
+}
+
+// ServeHTTP resolves the short link, taking the code from the last segment of
+// the request path, and redirects to the signed URL.
+func (s *Shortener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	signed, err := s.Resolve(r.Context(), path.Base(r.URL.Path))
+	if errors.Is(err, ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	http.Redirect(w, r, signed, http.StatusFound)
+}
+
+// MemoryShortLinkStore is an in-memory ShortLinkStore, suitable for a single
+// instance. Expired links are removed as new links are added.
+type MemoryShortLinkStore struct {
+	mu    sync.Mutex
+	links map[string]memoryShortLink
+}
+
+type memoryShortLink struct {
+	signed string
+	expiry time.Time
+}
+
+// Put implements ShortLinkStore.
+func (m *MemoryShortLinkStore) Put(ctx context.Context, code, signed string, expiry time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.links == nil {
+		m.links = make(map[string]memoryShortLink)
+	}
+	now := time.Now()
+	for code, link := range m.links {
+		if now.After(link.expiry) {
+			delete(m.links, code)
+		}
+	}
+	m.links[code] = memoryShortLink{signed: signed, expiry: expiry}
+	return nil
+}
+
+// Get implements ShortLinkStore.
+func (m *MemoryShortLinkStore) Get(ctx context.Context, code string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	link, ok := m.links[code]
+	if !ok || time.Now().After(link.expiry) {
+		return "", ErrNotFound
+	}
+	return link.signed, nil
+}
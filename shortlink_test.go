@@ -0,0 +1,81 @@
+package surl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortener(t *testing.T) {
+	ctx := context.Background()
+	shortener := &Shortener{
+		Signer: New([]byte("abc123")),
+		Store:  &MemoryShortLinkStore{},
+		Base:   "https://exa.mp/s/",
+	}
+
+	t.Run("resolve", func(t *testing.T) {
+		short, err := shortener.Shorten(ctx, "https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Len(t, strings.TrimPrefix(short, "https://exa.mp/s/"), 8)
+
+		w := httptest.NewRecorder()
+		shortener.ServeHTTP(w, httptest.NewRequest("GET", short, nil))
+		assert.Equal(t, http.StatusFound, w.Code)
+
+		location := w.Header().Get("Location")
+		assert.True(t, strings.HasPrefix(location, "https://example.com/a/b/c?"))
+		require.NoError(t, shortener.Signer.Verify(location))
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		shortener.ServeHTTP(w, httptest.NewRequest("GET", "https://exa.mp/s/abcdefgh", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		short, err := shortener.Shorten(ctx, "https://example.com/a/b/c?foo=bar", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		shortener.ServeHTTP(w, httptest.NewRequest("GET", short, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("tampered store", func(t *testing.T) {
+		store := &MemoryShortLinkStore{}
+		err := store.Put(ctx, "abcdefgh", "https://example.com/a/b/c?expiry=9999999999&signature=forged", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		shortener := &Shortener{Signer: shortener.Signer, Store: store}
+		w := httptest.NewRecorder()
+		shortener.ServeHTTP(w, httptest.NewRequest("GET", "https://exa.mp/s/abcdefgh", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("single use", func(t *testing.T) {
+		shortener := &Shortener{
+			Signer: New([]byte("abc123"), WithNonceStore(&MemoryNonceStore{})),
+			Store:  &MemoryShortLinkStore{},
+			Base:   "https://exa.mp/s/",
+		}
+		short, err := shortener.Shorten(ctx, "https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		shortener.ServeHTTP(w, httptest.NewRequest("GET", short, nil))
+		require.Equal(t, http.StatusFound, w.Code)
+
+		// the redirect target is still usable, once
+		location := w.Header().Get("Location")
+		require.NoError(t, shortener.Signer.Verify(location))
+		assert.Error(t, shortener.Signer.Verify(location))
+	})
+}
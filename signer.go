@@ -1,6 +1,7 @@
 package surl
 
 import (
+	"context"
 	"crypto/subtle"
 	"encoding/base64"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"hash"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,8 +23,18 @@ var (
 	// ErrInvalidFormat is returned when the format of the signed URL is
 	// invalid.
 	ErrInvalidFormat = errors.New("invalid format")
-	// ErrExpired is returned when a signed URL has expired.
+	// ErrExpired is returned when a signed URL has expired, wrapped in an
+	// ExpiredError carrying the time at which it expired. It must therefore be
+	// matched with errors.Is rather than compared with ==.
 	ErrExpired = errors.New("URL has expired")
+	// ErrNotYetValid is returned when a signed URL is not yet valid.
+	ErrNotYetValid = errors.New("URL is not yet valid")
+	// ErrNoKey is returned when signing or verifying with a signer configured
+	// to derive a key, e.g. with WithPurpose, WithEncryptedData or Scoped, but
+	// which has no symmetric key from which to derive it, e.g. because it was
+	// constructed with NewEd25519, NewFromSignFunc, NewFromCryptoSigner or
+	// NewFromFile.
+	ErrNoKey = errors.New("signer has no key from which to derive keys")
 
 	// DefaultFormatter sets the default format for the query parameter to the
 	// query formatter.
@@ -34,14 +46,54 @@ var (
 
 // Signer is capable of signing and verifying signed URLs with an expiry.
 type Signer struct {
-	mu     sync.Mutex
-	hash   hash.Hash
-	dirty  bool
+	key    []byte
+	alg    algorithm
+	hash   func(key []byte) *keyedHash // nil for the default, BLAKE2b
 	prefix string
+	// prefixes accepted when verifying, including prefix, if more than one
+	prefixes []string
+	scope    string
+	purpose  string // from which the key is derived, if any
+
+	webhookTolerance  time.Duration
+	selfDescribing    bool
+	usage             UsageStore
+	override          *override
+	fallbacks         []fallbackKey
+	keyring           *Keyring
+	keyFunc           KeyFunc
+	revocation        RevocationChecker
+	nonces            NonceStore
+	uses              UseCounter
+	maxUses           int                // of the URL being signed, if limited
+	data              []byte             // JSON-encoded data of the URL being signed, if any
+	methods           []string           // permitted for the URL being signed, if restricted
+	upload            *UploadConstraints // of the URL being signed, if any
+	digest            []byte             // of the content of the URL being signed, if any
+	headers           *ResponseHeaders   // of the URL being signed, if any
+	subtree           bool               // whether the URL being signed is a subtree
+	notBefore         time.Time          // of the URL being signed, if any
+	noExpiry          bool
+	signedPrefix      bool
+	maxLifetime       time.Duration
+	granularity       time.Duration
+	epoch             int64 // seconds since the Unix epoch
+	millisecondExpiry bool
+	signatureLength   int          // to which signatures are truncated, if any
+	explanation       *Explanation // records the verification being explained, if any
+	encryptData       bool
+	v1Compat          bool
+	clientFunc        ClientFunc
+	drift             *DriftDetector
+	err               error // of an option that could not be applied, if any
+	// numbers of options setting the formatter and expiry encoding, excluding
+	// the defaults, which NewWithValidation checks for conflicts
+	formatterOptions int
+	encodingOptions  int
 
 	payloadOptions
 	formatter
-	intEncoding
+	ExpiryEncoding
 }
 
 // New constructs a new signer, performing the one-off task of generating a
@@ -49,16 +101,21 @@ type Signer struct {
 // anything longer is truncated. Options alter the default format and behaviour
 // of signed URLs.
 func New(key []byte, opts ...Option) *Signer {
-	hash, err := blake2b.New256(key)
-	if err != nil {
-		// Safely ignore one and only error regarding keys longer than 64 bytes.
-		hash, _ = blake2b.New256(key[0:64])
-	}
+	return newSigner(key, newKeyedHash(key), opts...)
+}
+
+// newSigner constructs a signer that signs with the algorithm. The key is the
+// secret from which scoped keys are derived, or nil if the signer cannot derive
+// them.
+func newSigner(key []byte, alg algorithm, opts ...Option) *Signer {
 	s := &Signer{
-		hash: hash,
+		key:              key,
+		alg:              alg,
+		webhookTolerance: DefaultWebhookTolerance,
 	}
 	DefaultFormatter(s)
 	DefaultExpiryFormatter(s)
+	s.formatterOptions, s.encodingOptions = 0, 0
 
 	// Leave caller options til last so that they override defaults.
 	for _, o := range opts {
@@ -68,9 +125,42 @@ func New(key []byte, opts ...Option) *Signer {
 	return s
 }
 
+// With returns a copy of the signer with the options applied on top of its
+// existing configuration. The copy shares the key and hash state of the
+// signer, making it cheap to derive signers that serve several URL styles
+// without repeating key setup.
+func (s *Signer) With(opts ...Option) *Signer {
+	derived := *s
+	for _, o := range opts {
+		o(&derived)
+	}
+	return &derived
+}
+
 // Option permits customising the construction of a Signer
 type Option func(*Signer)
 
+// setFormatter sets the formatter, counting the options setting it.
+func (s *Signer) setFormatter(f formatter) {
+	s.formatter = f
+	s.formatterOptions++
+}
+
+// setExpiryEncoding sets the expiry encoding, counting the options setting
+// it.
+func (s *Signer) setExpiryEncoding(enc ExpiryEncoding) {
+	s.ExpiryEncoding = enc
+	s.encodingOptions++
+}
+
+// fail records an error of an option that could not be applied, which is
+// returned when signing and verifying. Only the first such error is recorded.
+func (s *Signer) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
 // SkipQuery instructs Signer to skip the query string when computing the
 // signature. This is useful, say, if you have pagination query parameters but
 // you want to use the same signed URL regardless of their value.
@@ -89,12 +179,22 @@ func SkipScheme() Option {
 	}
 }
 
+// SkipHost instructs Signer to skip the host when computing the signature.
+// This is useful, say, if the host differs between signing and verification,
+// e.g. when a server sits behind a proxy that rewrites the Host header.
+func SkipHost() Option {
+	return func(s *Signer) {
+		s.skipHost = true
+	}
+}
+
 // PrefixPath prefixes the signed URL's path with a string. This can make it easier for a server
 // to differentiate between signed and non-signed URLs. Note: the prefix is not
-// part of the signature computation.
+// part of the signature computation, unless WithSignedPrefix is given.
 func PrefixPath(prefix string) Option {
 	return func(s *Signer) {
 		s.prefix = prefix
+		s.prefixes = nil
 	}
 }
 
@@ -102,7 +202,16 @@ func PrefixPath(prefix string) Option {
 // and expiry in a signed URL.
 func WithQueryFormatter() Option {
 	return func(s *Signer) {
-		s.formatter = &queryFormatter{}
+		s.setFormatter(newQueryFormatter())
+	}
+}
+
+// WithShortQueryFormatter instructs Signer to use query parameters to store the
+// signature and expiry in a signed URL, with one-character parameter names: e
+// for the expiry and s for the signature.
+func WithShortQueryFormatter() Option {
+	return func(s *Signer) {
+		s.setFormatter(newShortQueryFormatter())
 	}
 }
 
@@ -110,109 +219,768 @@ func WithQueryFormatter() Option {
 // path of a signed URL.
 func WithPathFormatter() Option {
 	return func(s *Signer) {
-		s.formatter = &pathFormatter{}
+		s.setFormatter(&pathFormatter{})
+	}
+}
+
+// WithCompactFormatter instructs Signer to store the expiry and signature in
+// a single query parameter, token, producing shorter URLs that are simpler to
+// strip, e.g. https://example.com/a/b/c?token=1667331055.TGvxmRwpoAUt9YEIbeJ164lMYrzA2DBnYB9Lcy9m1T
+func WithCompactFormatter() Option {
+	return func(s *Signer) {
+		s.setFormatter(&compactFormatter{})
+	}
+}
+
+// WithSuffixFormatter instructs Signer to store the signature and expiry at
+// the end of the path of a signed URL, before the extension of the final
+// segment, so that the extension remains last and content types can still be
+// inferred from it, e.g.
+// https://example.com/a/b/c.TGvxmRwpoAUt9YEIbeJ164lMYrzA2DBnYB9Lcy9m1T.1667331055.pdf
+// Paths without an extension receive an additional final segment, e.g.
+// https://example.com/a/b/c/TGvxmRwpoAUt9YEIbeJ164lMYrzA2DBnYB9Lcy9m1T.1667331055
+//
+// The suffix formatter does not support URLs that never expire.
+func WithSuffixFormatter() Option {
+	return func(s *Signer) {
+		s.setFormatter(&suffixFormatter{})
+	}
+}
+
+// WithHostFormatter instructs Signer to store the signature and expiry in a
+// subdomain label prepended to the host of a signed URL, leaving the path and
+// query intact, for download hosts with wildcard DNS records, e.g.
+// https://wj4wpipsgagw2czlelt23visbff4u6v3swjwlzo2g4njhkygjcta-1667331055.dl.example.com/a/b/c
+//
+// The label must not exceed the 63 character limit of DNS, which requires
+// decimal expiries without milliseconds and signatures no longer than 32
+// bytes, e.g. those of the default algorithm, blake2b-256. The host formatter
+// does not support URLs that never expire, nor self-describing URLs.
+func WithHostFormatter() Option {
+	return func(s *Signer) {
+		s.setFormatter(&hostFormatter{})
 	}
 }
 
 // WithDecimalExpiry instructs Signer to use base10 to encode the expiry
 func WithDecimalExpiry() Option {
 	return func(s *Signer) {
-		s.intEncoding = stdIntEncoding(10)
+		s.setExpiryEncoding(stdIntEncoding(10))
 	}
 }
 
 // WithBase58Expiry instructs Signer to use base58 to encode the expiry
 func WithBase58Expiry() Option {
 	return func(s *Signer) {
-		s.intEncoding = &base58Encoding{}
+		s.setExpiryEncoding(&base58Encoding{})
+	}
+}
+
+// WithBase64Expiry instructs Signer to use base64 to encode the expiry
+func WithBase64Expiry() Option {
+	return func(s *Signer) {
+		s.setExpiryEncoding(&base64Encoding{})
+	}
+}
+
+// WithExpiryEncoding instructs Signer to use a custom encoding for the expiry.
+// Signers using a custom encoding cannot produce self-describing URLs.
+func WithExpiryEncoding(enc ExpiryEncoding) Option {
+	return func(s *Signer) {
+		s.setExpiryEncoding(enc)
+	}
+}
+
+// WithTimestampExpiry instructs Signer to encode the expiry as a
+// human-readable UTC timestamp, e.g. 20250101T120000Z, so that support staff
+// can read when a URL expires directly from the link, at the cost of a few
+// characters. Expiries are encoded as timestamps regardless of WithEpoch.
+func WithTimestampExpiry() Option {
+	return func(s *Signer) {
+		s.setExpiryEncoding(timestampEncoding{})
 	}
 }
 
 // Sign generates a signed URL with the given lifespan.
 func (s *Signer) Sign(unsigned string, expiry time.Time) (string, error) {
+	return s.SignContext(context.Background(), unsigned, expiry)
+}
+
+// SignContext is like Sign but with a context, which is passed to the service
+// computing signatures for a signer constructed with NewFromMACService.
+func (s *Signer) SignContext(ctx context.Context, unsigned string, expiry time.Time) (string, error) {
 	u, err := url.ParseRequestURI(unsigned)
 	if err != nil {
 		return "", err
 	}
+	if err := s.signURL(ctx, u, expiry, ""); err != nil {
+		return "", err
+	}
 
-	// Add expiry to unsigned URL
-	encodedExpiry := s.Encode(expiry.Unix())
-	s.addExpiry(u, encodedExpiry)
+	// return signed URL
+	return u.String(), nil
+}
 
-	// Build payload for signature computation
-	payload := s.buildPayload(*u, s.payloadOptions)
+// SignFor is like Sign but the signed URL expires after the given
+// time-to-live, i.e. it is equivalent to Sign(unsigned, time.Now().Add(ttl)).
+func (s *Signer) SignFor(unsigned string, ttl time.Duration) (string, error) {
+	return s.Sign(unsigned, time.Now().Add(ttl))
+}
+
+// Verify verifies a signed URL, validating its signature and ensuring it is
+// unexpired.
+func (s *Signer) Verify(signed string) error {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyURL(context.Background(), u, "")
+	return err
+}
+
+// VerifyContext is like Verify but with a context, which is passed to the key
+// function configured with WithKeyFunc, and to the service verifying
+// signatures for a signer constructed with NewFromMACService.
+func (s *Signer) VerifyContext(ctx context.Context, signed string) error {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyURL(ctx, u, "")
+	return err
+}
+
+// SignURL is like Sign but operates on a parsed URL, returning a signed copy
+// of the URL. Its encoded path (RawPath) is preserved.
+func (s *Signer) SignURL(unsigned *url.URL, expiry time.Time) (*url.URL, error) {
+	u := *unsigned
+	if err := s.signURL(context.Background(), &u, expiry, ""); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// VerifyURL is like Verify but operates on a parsed URL. The URL is not
+// modified.
+func (s *Signer) VerifyURL(signed *url.URL) error {
+	u := *signed
+	_, err := s.verifyURL(context.Background(), &u, "")
+	return err
+}
+
+// VerifyAt verifies a signed URL as if at the given time, validating its
+// signature and ensuring it was unexpired at that time. This is useful for
+// auditing historical requests, e.g. from access logs.
+func (s *Signer) VerifyAt(signed string, t time.Time) error {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyURLAt(context.Background(), u, "", t)
+	return err
+}
+
+// VerifySignatureAt is like VerifyAt but only checks the signature, expiry,
+// not-before time and lifetime of the URL, skipping the checks and hooks that
+// concern the present rather than the given time: revocation, override
+// auditing and drift detection. It is intended for auditing historical
+// requests, which are neither revoked nor audited again after the fact.
+func (s *Signer) VerifySignatureAt(signed string, t time.Time) error {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	result, err := s.verifySignature(context.Background(), u, "")
+	if err != nil {
+		return err
+	}
+	return s.checkValidAt(result, t)
+}
+
+// VerifyIgnoreExpiry verifies the signature of a signed URL but, unlike
+// Verify, does not reject it if it has expired. This is useful for confirming
+// a link was genuinely issued even though it has since lapsed, e.g. in admin
+// and support tooling. It must not be used to authorize access.
+func (s *Signer) VerifyIgnoreExpiry(signed string) error {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifySignature(context.Background(), u, "")
+	return err
+}
+
+// signURL signs the URL in place. A non-empty binding is included in the
+// signature computation but is not stored in the URL; the verifier must
+// supply the same binding.
+func (s *Signer) signURL(ctx context.Context, u *url.URL, expiry time.Time, binding string) error {
+	if s.scope != "" && !inScope(u.Path, s.scope) {
+		return fmt.Errorf("%w: %s", ErrOutOfScope, u.Path)
+	}
+
+	var kid string
+	if s.keyring != nil {
+		// switch to the current key of the keyring
+		var key []byte
+		kid, key = s.keyring.currentKey()
+		s = s.withKey(key)
+	}
+
+	var encodedData string
+	if s.signedPrefix {
+		binding = prefixBinding(s.prefix, binding)
+	}
+	if s.methods != nil {
+		binding = methodsBinding(s.methods, binding)
+	}
+	if s.upload != nil && !s.upload.isEmpty() {
+		binding = uploadBinding(s.upload, binding)
+	}
+	if s.digest != nil {
+		binding = digestBinding(s.digest, binding)
+	}
+	if s.headers != nil && !s.headers.isEmpty() {
+		binding = headersBinding(s.headers, binding)
+	}
+	var encodedNotBefore string
+	if !s.notBefore.IsZero() {
+		encodedNotBefore = s.encodeTime(s.notBefore)
+		binding = notBeforeBinding(encodedNotBefore, binding)
+	}
+	var subtree string
+	if s.subtree {
+		subtree = u.Path
+		binding = subtreeBinding(subtree, binding)
+	}
+	if s.data != nil {
+		var err error
+		if encodedData, err = s.encodeData(s.data); err != nil {
+			return err
+		}
+		binding = dataBinding(encodedData, binding)
+	}
+
+	var id string
+	if s.revocation != nil || s.nonces != nil || s.maxUses > 0 {
+		var err error
+		if id, err = newURLID(); err != nil {
+			return err
+		}
+		if s.maxUses > 0 {
+			binding = usesBinding(s.maxUses, binding)
+		}
+		binding = idBinding(id, binding)
+	}
+
+	permanent := s.noExpiry && expiry.IsZero()
+	if !permanent {
+		expiry = s.roundExpiry(expiry)
+	}
+	if err := s.checkLifetime(expiry, permanent, time.Now()); err != nil {
+		return err
+	}
+
+	// Add expiry to unsigned URL, unless it never expires
+	if permanent {
+		binding = noExpiryBinding(binding)
+	} else {
+		s.addExpiry(u, s.encodeExpiry(expiry))
+	}
 
 	// Sign payload creating a signature
-	sig := s.sign([]byte(payload))
+	encodedSig, err := s.signURLPayload(ctx, *u, binding)
+	if err != nil {
+		return err
+	}
 
 	// Add signature to url
-	encodedSig := base64.RawURLEncoding.EncodeToString(sig)
 	s.addSignature(u, encodedSig)
 
+	if s.scope != "" {
+		addScope(u, s.scope)
+	}
+	if kid != "" {
+		appendQueryParam(u, keyIDParam, kid)
+	}
+	if id != "" {
+		appendQueryParam(u, idParam, id)
+	}
+	if s.maxUses > 0 {
+		appendQueryParam(u, maxUsesParam, strconv.Itoa(s.maxUses))
+	}
+	if encodedData != "" {
+		appendQueryParam(u, dataParam, encodedData)
+	}
+	if s.methods != nil {
+		appendQueryParam(u, methodsParam, strings.Join(s.methods, ","))
+	}
+	if s.upload != nil {
+		addUpload(u, s.upload)
+	}
+	if s.digest != nil {
+		appendQueryParam(u, digestParam, base64.RawURLEncoding.EncodeToString(s.digest))
+	}
+	if s.headers != nil {
+		addHeaders(u, s.headers)
+	}
+	if encodedNotBefore != "" {
+		appendQueryParam(u, notBeforeParam, encodedNotBefore)
+	}
+	if subtree != "" {
+		appendQueryParam(u, subtreeParam, subtree)
+	}
+
 	if s.prefix != "" {
-		u.Path = path.Join(s.prefix, u.Path)
+		u.Path = joinPrefix(s.prefix, u.Path)
+		if u.RawPath != "" {
+			u.RawPath = joinPrefix(s.prefix, u.RawPath)
+		}
 	}
+	return nil
+}
 
-	// return signed URL
-	return u.String(), nil
+// joinPrefix joins the prefix to a path, retaining any trailing slash of the
+// path, which is part of the signature computation.
+func joinPrefix(prefix, p string) string {
+	joined := path.Join(prefix, p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
 }
 
-// Verify verifies a signed URL, validating its signature and ensuring it is
-// unexpired.
-func (s *Signer) Verify(signed string) error {
-	u, err := url.ParseRequestURI(signed)
+// verifyURL verifies the signed URL, which is modified in the process. If the
+// signature is valid but has expired then the result is returned along with
+// ErrExpired.
+func (s *Signer) verifyURL(ctx context.Context, u *url.URL, binding string) (*Result, error) {
+	result, err := s.verifyURLAt(ctx, u, binding, time.Now())
 	if err != nil {
-		return err
+		return result, err
+	}
+	if err := s.redeem(ctx, result); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	if !strings.HasPrefix(u.Path, s.prefix) {
-		return ErrInvalidFormat
+// redeem counts a use of, or consumes the nonce of, the verified URL.
+func (s *Signer) redeem(ctx context.Context, result *Result) error {
+	if result.MaxUses > 0 {
+		return s.countUse(ctx, result)
 	}
-	u.Path = u.Path[len(s.prefix):]
+	return s.consumeNonce(ctx, result)
+}
 
-	encodedSig, err := s.extractSignature(u)
+// verifyURLAt is verifyURL as if at the given time.
+func (s *Signer) verifyURLAt(ctx context.Context, u *url.URL, binding string, now time.Time) (*Result, error) {
+	result, err := s.verifySignature(ctx, u, binding)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if s.drift != nil && !result.permanent {
+		s.drift.observe(result.ExpiresAt, now)
+	}
+	if err := s.checkValidAt(result, now); err != nil {
+		var expired *ExpiredError
+		if errors.As(err, &expired) {
+			return result, err
+		}
+		return nil, err
+	}
+	if err := s.checkRevocation(ctx, result); err != nil {
+		return nil, err
+	}
+	if result.Override {
+		if err := s.override.audit(result); err != nil {
+			return nil, fmt.Errorf("auditing override: %w", err)
+		}
+	}
+
+	// valid, unexpired, signature
+	return result, nil
+}
+
+// checkValidAt checks that the URL with a valid signature was valid at the
+// given time, setting the time remaining until it expires.
+func (s *Signer) checkValidAt(result *Result, now time.Time) error {
+	if !result.permanent {
+		if now.After(result.ExpiresAt) {
+			return &ExpiredError{ExpiredAt: result.ExpiresAt}
+		}
+		result.Remaining = result.ExpiresAt.Sub(now)
+	}
+	if now.Before(result.NotBefore) {
+		return ErrNotYetValid
+	}
+	return s.checkLifetime(result.ExpiresAt, result.permanent, now)
+}
+
+// verifySignature validates the signature of the signed URL, which is
+// modified in the process. It does not check whether the URL has expired.
+func (s *Signer) verifySignature(ctx context.Context, u *url.URL, binding string) (*Result, error) {
+	var kid string
+	if s.keyring != nil || s.keyFunc != nil {
+		kid = u.Query().Get(keyIDParam)
+	}
+	s, scope, err := s.prepare(ctx, u)
 	if err != nil {
-		return fmt.Errorf("%w: invalid base64: %s", ErrInvalidSignature, encodedSig)
+		return nil, err
+	}
+	// the network, if any, is bound by the caller; see clientNetworkBinding
+	if _, err := removeQueryParam(u, networkParam); err != nil {
+		return nil, fmt.Errorf("%w: invalid network", ErrInvalidFormat)
+	}
+	if s.signedPrefix {
+		binding = prefixBinding(s.prefix, binding)
+	}
+	result, binding, err := s.extractComponents(u, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedSig, err := s.extractSignature(u)
+	if err != nil {
+		return nil, err
 	}
 
-	// build the payload for signature computation
-	payload := s.buildPayload(*u, s.payloadOptions)
+	permanent := s.noExpiry && !s.hasExpiry(*u)
+	if permanent {
+		binding = noExpiryBinding(binding)
+	}
+
+	// the signature of a URL beneath a subtree is that of the subtree
+	payloadURL := u
+	if result.Subtree != "" {
+		if payloadURL, err = s.subtreePayloadURL(*u, result.Subtree, permanent); err != nil {
+			return nil, err
+		}
+	}
 
 	// create another signature for comparison and compare
-	compare := s.sign([]byte(payload))
-	if subtle.ConstantTimeCompare(sig, compare) != 1 {
-		return ErrInvalidSignature
+	var override bool
+	if err := s.compareURLSignature(ctx, *payloadURL, binding, encodedSig); err != nil {
+		if !errors.Is(err, ErrInvalidSignature) || s.override == nil {
+			return nil, err
+		}
+		// try again using the override key
+		o := *s
+		o.alg = s.override.alg
+		o.fallbacks = nil
+		if err := o.compareURLSignature(ctx, *payloadURL, binding, encodedSig); err != nil {
+			return nil, err
+		}
+		override = true
 	}
 
 	// get expiry from signed URL
-	encodedExpiry, err := s.extractExpiry(u)
+	var expiresAt time.Time
+	if !permanent {
+		encodedExpiry, err := s.extractExpiry(u)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt, err = s.decodeExpiry(encodedExpiry); err != nil {
+			return nil, err
+		}
+	}
+
+	if scope != "" && !inScope(u.Path, scope) {
+		return nil, fmt.Errorf("%w: %s", ErrOutOfScope, u.Path)
+	}
+	if result.Data != nil && s.encryptData {
+		if result.Data, err = s.decryptData(result.Data); err != nil {
+			return nil, err
+		}
+	}
+	result.OriginalURL = u
+	result.ExpiresAt = expiresAt
+	result.KeyID = kid
+	result.LinkID = encodedSig
+	result.Override = override
+	result.permanent = permanent
+	return result, nil
+}
+
+// extractComponents removes the components added to a signed URL by the Sign*
+// helpers, e.g. its methods and data, returning them in a result along with
+// the binding of them, and any other binding, to the signature.
+func (s *Signer) extractComponents(u *url.URL, binding string) (*Result, string, error) {
+	methods, err := extractMethods(u)
 	if err != nil {
-		return err
+		return nil, "", err
+	}
+	if methods != nil {
+		binding = methodsBinding(methods, binding)
+	}
+	upload, err := extractUpload(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if upload != nil {
+		binding = uploadBinding(upload, binding)
+	}
+	digest, err := extractDigest(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if digest != nil {
+		binding = digestBinding(digest, binding)
+	}
+	headers, err := extractHeaders(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if headers != nil {
+		binding = headersBinding(headers, binding)
+	}
+	encodedNotBefore, notBefore, err := s.extractNotBefore(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if encodedNotBefore != "" {
+		binding = notBeforeBinding(encodedNotBefore, binding)
+	}
+	subtree, err := removeQueryParam(u, subtreeParam)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: invalid subtree", ErrInvalidFormat)
+	}
+	if subtree != "" {
+		binding = subtreeBinding(subtree, binding)
+	}
+	encodedData, data, err := extractData(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if encodedData != "" {
+		binding = dataBinding(encodedData, binding)
+	}
+	maxUses, err := extractMaxUses(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if maxUses > 0 {
+		binding = usesBinding(maxUses, binding)
+	}
+	id, err := removeQueryParam(u, idParam)
+	if err != nil || (id != "" && !validURLID(id)) {
+		return nil, "", fmt.Errorf("%w: invalid ID", ErrInvalidFormat)
+	}
+	if id != "" {
+		binding = idBinding(id, binding)
 	}
-	expiry, err := s.Decode(encodedExpiry)
+	return &Result{
+		ID:        id,
+		MaxUses:   maxUses,
+		Data:      data,
+		Methods:   methods,
+		Upload:    upload,
+		Digest:    digest,
+		Headers:   headers,
+		Subtree:   subtree,
+		NotBefore: notBefore,
+	}, binding, nil
+}
+
+// prepare removes the prefix and scope from the signed URL, returning the
+// scope along with the signer to use for verifying the URL: either this signer
+// or one derived from it according to the scope or descriptor.
+func (s *Signer) prepare(ctx context.Context, u *url.URL) (*Signer, string, error) {
+	prefix, ok := s.matchPrefix(u.Path)
+	if !ok {
+		return nil, "", ErrInvalidFormat
+	}
+	u.Path = u.Path[len(prefix):]
+	u.RawPath = strings.TrimPrefix(u.RawPath, prefix)
+	if prefix != s.prefix {
+		// bind the prefix that matched; see WithSignedPrefix
+		matched := *s
+		matched.prefix = prefix
+		s = &matched
+	}
+
+	scope, err := s.extractScope(u)
+	if err != nil {
+		return nil, "", err
+	}
+	s, err = s.extractKeyID(ctx, u)
+	if err != nil {
+		return nil, "", err
+	}
+	if scope != "" && s.scope == "" {
+		if s.key == nil {
+			return nil, "", fmt.Errorf("%w: cannot derive key for scope", ErrInvalidSignature)
+		}
+		// switch to the key derived for the scope
+		s = s.Scoped(scope)
+	}
+
+	if s.selfDescribing {
+		// switch to the configuration described by the URL
+		described, err := s.described(u)
+		if err != nil {
+			return nil, "", err
+		}
+		s = described
+	}
+	return s, scope, nil
+}
+
+// signURLPayload builds the payload for signature computation from a URL,
+// signs it, and returns the encoded signature.
+func (s *Signer) signURLPayload(ctx context.Context, u url.URL, binding string) (string, error) {
+	data, desc, err := s.urlPayload(u, binding)
+	if err != nil {
+		return "", err
+	}
+	sig, err := s.sign(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	return desc + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// compareURLSignature verifies the given encoded signature of a URL, returning
+// ErrInvalidSignature if it is invalid.
+func (s *Signer) compareURLSignature(ctx context.Context, u url.URL, binding, encodedSig string) error {
+	data, desc, err := s.urlPayload(u, binding)
 	if err != nil {
 		return err
 	}
-	if time.Now().After(time.Unix(expiry, 0)) {
-		return ErrExpired
+	if s.explanation != nil {
+		s.explanation.Payload = string(data)
 	}
+	encodedSig, found := strings.CutPrefix(encodedSig, desc)
+	if !found {
+		return ErrInvalidSignature
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return fmt.Errorf("%w: invalid base64: %s", ErrInvalidSignature, encodedSig)
+	}
+	return s.verify(ctx, data, sig)
+}
 
-	// valid, unexpired, signature
+// urlPayload builds the data to be signed for a URL. For a self-describing
+// signer, it also returns the descriptor, including its separator, which
+// prefixes the encoded signature.
+func (s *Signer) urlPayload(u url.URL, binding string) ([]byte, string, error) {
+	payload := s.buildPayload(u, s.payloadOptions)
+	if !s.selfDescribing {
+		return bind(payload, binding), "", nil
+	}
+	desc, err := s.describe()
+	if err != nil {
+		return nil, "", err
+	}
+	desc += descriptorSeparator
+	return bind(desc+payload, binding), desc, nil
+}
+
+// bind combines a payload with a binding, returning the data to be signed. The
+// binding is length-prefixed so that it cannot be confused with the payload.
+func bind(payload, binding string) []byte {
+	if binding == "" {
+		return []byte(payload)
+	}
+	return []byte(strconv.Itoa(len(binding)) + ":" + binding + payload)
+}
+
+func (s *Signer) sign(ctx context.Context, data []byte) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var (
+		sig []byte
+		err error
+	)
+	if ca, ok := s.alg.(contextAlgorithm); ok {
+		sig, err = ca.signContext(ctx, data)
+	} else {
+		sig, err = s.alg.sign(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.truncate(sig), nil
+}
+
+func (s *Signer) verify(ctx context.Context, data, sig []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	err := s.verifyWith(ctx, s.alg, data, sig)
+	if errors.Is(err, ErrInvalidSignature) && len(s.fallbacks) > 0 {
+		err = s.verifyFallbacks(ctx, data, sig)
+	}
+	if errors.Is(err, ErrInvalidSignature) && s.v1Compat {
+		err = s.verifyV1(ctx, data, sig)
+	}
+	return err
+}
+
+// algorithm computes and verifies signatures.
+type algorithm interface {
+	// sign returns the signature of the data.
+	sign(data []byte) ([]byte, error)
+	// verify returns ErrInvalidSignature if sig is not a valid signature of
+	// the data.
+	verify(data, sig []byte) error
+}
+
+// contextAlgorithm is implemented by algorithms that make calls honouring a
+// context, e.g. to a remote service, in which case its methods are used in
+// place of those of algorithm.
+type contextAlgorithm interface {
+	signContext(ctx context.Context, data []byte) ([]byte, error)
+	verifyContext(ctx context.Context, data, sig []byte) error
+}
+
+// keyedHashFor constructs a keyed hash from the key using the signer's hash
+// algorithm.
+func (s *Signer) keyedHashFor(key []byte) *keyedHash {
+	if s.hash == nil {
+		return newKeyedHash(key)
+	}
+	return s.hash(key)
+}
+
+func newKeyedHash(key []byte) *keyedHash {
+	hash, err := blake2b.New256(key)
+	if err != nil {
+		// Safely ignore one and only error regarding keys longer than 64 bytes.
+		hash, _ = blake2b.New256(key[0:64])
+	}
+	return &keyedHash{name: "blake2b-256", hash: hash}
+}
+
+// keyedHash computes signatures using a keyed hash, serializing access to the
+// hash so that it is safe for concurrent use.
+type keyedHash struct {
+	name  string
+	mu    sync.Mutex
+	hash  hash.Hash
+	dirty bool
+}
+
+func (k *keyedHash) sign(data []byte) ([]byte, error) {
+	return k.sum(data), nil
+}
+
+func (k *keyedHash) verify(data, sig []byte) error {
+	if subtle.ConstantTimeCompare(sig, k.sum(data)) != 1 {
+		return ErrInvalidSignature
+	}
 	return nil
 }
 
-func (s *Signer) sign(data []byte) []byte {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (k *keyedHash) sum(data []byte) []byte {
+	k.mu.Lock()
+	defer k.mu.Unlock()
 
-	if s.dirty {
-		s.hash.Reset()
+	if k.dirty {
+		k.hash.Reset()
 	}
-	s.dirty = true
-	s.hash.Write(data)
-	return s.hash.Sum(nil)
+	k.dirty = true
+	k.hash.Write(data)
+	return k.hash.Sum(nil)
 }
@@ -1,9 +1,11 @@
 package surl
 
 import (
+	"context"
 	"crypto/rand"
 	"net/url"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +26,10 @@ var (
 			name:      "query",
 			formatter: WithQueryFormatter(),
 		},
+		{
+			name:      "short query",
+			formatter: WithShortQueryFormatter(),
+		},
 	}
 
 	encoders = []struct {
@@ -71,6 +77,18 @@ var (
 			name:    "prefix and skip query and skip scheme",
 			options: []Option{SkipQuery(), SkipScheme(), PrefixPath("/signed")},
 		},
+		{
+			name:    "skip host",
+			options: []Option{SkipHost()},
+		},
+		{
+			name:    "skip scheme and skip host",
+			options: []Option{SkipScheme(), SkipHost()},
+		},
+		{
+			name:    "self describing",
+			options: []Option{SelfDescribing()},
+		},
 	}
 )
 
@@ -95,6 +113,22 @@ func TestSigner(t *testing.T) {
 			name:     "absolute path",
 			unsigned: "/a/b/c",
 		},
+		{
+			name:     "with unsorted repeated keys",
+			unsigned: "https://example.com/a/b/c?b=2&a=1&b=1",
+		},
+		{
+			name:     "with key without equals",
+			unsigned: "https://example.com/a/b/c?flag&foo=bar",
+		},
+		{
+			name:     "with encoded path",
+			unsigned: "https://example.com/a%2Fb/c",
+		},
+		{
+			name:     "with empty value",
+			unsigned: "https://example.com/a/b/c?empty=&foo=bar",
+		},
 	}
 	// invoke test for each combination of unsigned url, formatter, encoder, and set of
 	// options
@@ -195,6 +229,39 @@ func TestSigner_SkipScheme(t *testing.T) {
 	})
 }
 
+func TestSigner_SkipHost(t *testing.T) {
+	// Demonstrate the SkipHost option by changing the host on the signed URL
+	// and showing it still verifies.
+	t.Run("skip host", func(t *testing.T) {
+		signer := New([]byte("abc123"), SkipHost())
+
+		signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		u.Host = "internal.example.com:8080"
+
+		err = signer.Verify(u.String())
+		require.NoError(t, err)
+	})
+
+	// Demonstrate how changing the host invalidates the signed URL
+	t.Run("do not skip host", func(t *testing.T) {
+		signer := New([]byte("abc123"))
+
+		signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		u.Host = "internal.example.com:8080"
+
+		err = signer.Verify(u.String())
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+}
+
 func TestSigner_Prefix(t *testing.T) {
 	signer := New([]byte("abc123"), PrefixPath("/signed"))
 
@@ -204,6 +271,146 @@ func TestSigner_Prefix(t *testing.T) {
 	})
 }
 
+func TestSigner_PreservesQuery(t *testing.T) {
+	unsigned := "https://example.com/a/b/c?b=2&a=1&b=1&flag&empty="
+
+	for _, f := range formatters {
+		t.Run(f.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), f.formatter)
+
+			signed, err := signer.Sign(unsigned, time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(u.RawQuery, "b=2&a=1&b=1&flag&empty="), u.RawQuery)
+		})
+	}
+}
+
+func TestSigner_SignURL(t *testing.T) {
+	for _, f := range formatters {
+		for _, opt := range []Option{SkipQuery(), PrefixPath("/signed")} {
+			signer := New([]byte("abc123"), f.formatter, opt)
+
+			t.Run(f.name, func(t *testing.T) {
+				unsigned, err := url.Parse("https://example.com/a%2Fb/c?foo=bar")
+				require.NoError(t, err)
+
+				signed, err := signer.SignURL(unsigned, time.Now().Add(time.Minute))
+				require.NoError(t, err)
+
+				// unsigned URL is unmodified
+				assert.Equal(t, "https://example.com/a%2Fb/c?foo=bar", unsigned.String())
+				// encoded path is preserved
+				assert.Contains(t, signed.EscapedPath(), "/a%2Fb/c")
+
+				require.NoError(t, signer.VerifyURL(signed))
+				// signed URL is unmodified
+				assert.Contains(t, signed.EscapedPath(), "/a%2Fb/c")
+				require.NoError(t, signer.VerifyURL(signed))
+
+				// and it survives a round trip through a string
+				require.NoError(t, signer.Verify(signed.String()))
+			})
+		}
+	}
+}
+
+func TestSigner_SignFor(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	signed, err := signer.SignFor("https://example.com/a/b/c", time.Hour)
+	require.NoError(t, err)
+
+	assert.NoError(t, signer.Verify(signed))
+	assert.NoError(t, signer.VerifyAt(signed, time.Now().Add(59*time.Minute)))
+	assert.ErrorIs(t, signer.VerifyAt(signed, time.Now().Add(61*time.Minute)), ErrExpired)
+}
+
+func TestSigner_VerifyAt(t *testing.T) {
+	signer := New([]byte("abc123"))
+	expiry := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+
+	assert.NoError(t, signer.VerifyAt(signed, expiry.Add(-time.Minute)))
+	assert.ErrorIs(t, signer.VerifyAt(signed, expiry.Add(time.Minute)), ErrExpired)
+}
+
+func TestSigner_VerifySignatureAt(t *testing.T) {
+	var audited int
+	signer := New([]byte("abc123"),
+		WithRevocationChecker(func(context.Context, string) (bool, error) { return true, nil }),
+		WithOverrideKey([]byte("break-glass"), func(*Result) error {
+			audited++
+			return nil
+		}),
+	)
+	expiry := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+	assert.ErrorIs(t, signer.VerifyAt(signed, expiry.Add(-time.Minute)), ErrRevoked)
+	// revocation is not checked
+	assert.NoError(t, signer.VerifySignatureAt(signed, expiry.Add(-time.Minute)))
+	assert.ErrorIs(t, signer.VerifySignatureAt(signed, expiry.Add(time.Minute)), ErrExpired)
+
+	// the override is not audited
+	override, err := New([]byte("break-glass")).Sign("https://example.com/a/b/c", expiry)
+	require.NoError(t, err)
+	assert.NoError(t, signer.VerifySignatureAt(override, expiry.Add(-time.Minute)))
+	assert.Zero(t, audited)
+
+	tampered := strings.Replace(signed, "/a/b/c", "/a/b/d", 1)
+	assert.ErrorIs(t, signer.VerifySignatureAt(tampered, expiry.Add(-time.Minute)), ErrInvalidSignature)
+}
+
+func TestSigner_VerifyIgnoreExpiry(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		err = signer.VerifyIgnoreExpiry(signed)
+		require.NoError(t, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		hacked, err := url.Parse(signed)
+		require.NoError(t, err)
+		hacked.Path = "/a/b/d"
+
+		err = signer.VerifyIgnoreExpiry(hacked.String())
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+}
+
+func TestSigner_With(t *testing.T) {
+	signer := New([]byte("abc123"))
+	derived := signer.With(WithPathFormatter(), PrefixPath("/signed"))
+
+	assert.Same(t, signer.alg, derived.alg)
+
+	signed, err := derived.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Regexp(t, `^https://example.com/signed/[^/]+\.\d+/a/b/c$`, signed)
+
+	err = derived.Verify(signed)
+	require.NoError(t, err)
+
+	t.Run("parent is unchanged", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Regexp(t, `^https://example.com/a/b/c\?expiry=\d+&signature=.+$`, signed)
+	})
+}
+
 func TestSigner_Errors(t *testing.T) {
 	t.Run("expired", func(t *testing.T) {
 		signer := New([]byte("abc123"))
@@ -213,7 +420,24 @@ func TestSigner_Errors(t *testing.T) {
 		require.NoError(t, err)
 
 		err = signer.Verify(signed)
-		assert.Equal(t, ErrExpired, err)
+		// Breaking change: ErrExpired is now wrapped in an ExpiredError, and
+		// so it must be matched with errors.Is rather than compared with ==.
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("expired error", func(t *testing.T) {
+		signer := New([]byte("abc123"))
+
+		expiry := time.Now().Add(-time.Hour)
+		signed, err := signer.Sign("https://example.com/a/b/c", expiry)
+		require.NoError(t, err)
+
+		err = signer.Verify(signed)
+		assert.Equal(t, ErrExpired.Error(), err.Error())
+
+		var expired *ExpiredError
+		require.ErrorAs(t, err, &expired)
+		assert.Equal(t, expiry.Unix(), expired.ExpiredAt.Unix())
 	})
 
 	t.Run("relative path", func(t *testing.T) {
@@ -0,0 +1,117 @@
+package surl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// subtreeParam is the query parameter that carries the path prefix beneath
+// which a signed URL grants access.
+const subtreeParam = "signature_subtree"
+
+// SignSubtree signs a URL granting access to every URL beneath its path, e.g.
+// https://example.com/videos/1234/ for the hundreds of segments of an HLS
+// stream, in the manner of a CloudFront custom policy with a wildcard. The
+// path must end with a slash and the URL must not have a query. Every URL
+// beneath the path, with any query, is valid with the signature, expiry and
+// other parameters of the signed URL, which are added to it with SubtreeURL.
+// The path is added to the URL and covered by the signature.
+func (s *Signer) SignSubtree(unsigned string, expiry time.Time) (string, error) {
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		return "", fmt.Errorf("subtree path must end with a slash: %s", u.Path)
+	}
+	if u.RawQuery != "" {
+		return "", fmt.Errorf("subtree URL must not have a query: %s", unsigned)
+	}
+	if hasDotSegment(u.Path) {
+		return "", fmt.Errorf("subtree path must not have dot segments: %s", u.Path)
+	}
+	granted := *s
+	granted.subtree = true
+	prefix := subtreeURL(u, u.Path)
+	if err := granted.signURL(context.Background(), prefix, expiry, ""); err != nil {
+		return "", err
+	}
+	return prefix.String(), nil
+}
+
+// SubtreeURL returns the URL beneath the path of a URL signed with
+// SignSubtree, with the parameters of the signed URL, which is not verified.
+// Only the path and query of the target URL are used.
+func SubtreeURL(signed, target string) (string, error) {
+	s, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return "", err
+	}
+	t, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	c := *s
+	subtree, err := removeQueryParam(&c, subtreeParam)
+	if err != nil || subtree == "" {
+		return "", fmt.Errorf("%w: not a subtree URL: %s", ErrInvalidFormat, signed)
+	}
+	if !strings.HasPrefix(t.Path, subtree) {
+		return "", fmt.Errorf("%w: %s not beneath %s", ErrNotGranted, t.Path, subtree)
+	}
+	head, found := strings.CutSuffix(s.Path, subtree)
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFormat, signed)
+	}
+	u := &url.URL{Scheme: s.Scheme, Host: s.Host, Path: head + t.Path, RawQuery: s.RawQuery}
+	if t.RawQuery != "" {
+		u.RawQuery = t.RawQuery + "&" + s.RawQuery
+	}
+	return u.String(), nil
+}
+
+// subtreeURL returns the URL, for signing, of the subtree of a URL.
+func subtreeURL(u *url.URL, subtree string) *url.URL {
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: subtree}
+}
+
+// subtreeBinding binds the subtree of a signed URL, along with any other
+// binding, to its signature.
+func subtreeBinding(subtree, binding string) string {
+	return tagBinding("subtree", subtree, binding)
+}
+
+// subtreePayloadURL returns the URL whose signature is compared for a URL,
+// from which its signature has been extracted, beneath the subtree: the URL of
+// the subtree along with the expiry of the URL, unless it is permanent.
+func (s *Signer) subtreePayloadURL(u url.URL, subtree string, permanent bool) (*url.URL, error) {
+	var encodedExpiry string
+	if !permanent {
+		var err error
+		if encodedExpiry, err = s.extractExpiry(&u); err != nil {
+			return nil, err
+		}
+	}
+	if !strings.HasSuffix(subtree, "/") || !strings.HasPrefix(u.Path, subtree) || hasDotSegment(u.Path) {
+		return nil, fmt.Errorf("%w: %s", ErrNotGranted, u.Path)
+	}
+	payload := subtreeURL(&u, subtree)
+	if !permanent {
+		s.addExpiry(payload, encodedExpiry)
+	}
+	return payload, nil
+}
+
+// hasDotSegment reports whether a path has a . or .. segment, which could
+// otherwise escape a subtree.
+func hasDotSegment(p string) bool {
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package surl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignSubtree(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"query formatter", nil},
+		{"path formatter", []Option{WithPathFormatter()}},
+		{"prefix", []Option{PrefixPath("/signed")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), tt.opts...)
+
+			signed, err := signer.SignSubtree("https://example.com/videos/1234/", time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			assert.NoError(t, signer.Verify(signed))
+
+			segment, err := SubtreeURL(signed, "/videos/1234/hls/segment1.ts?quality=hd")
+			require.NoError(t, err)
+			assert.NoError(t, signer.Verify(segment))
+
+			_, err = SubtreeURL(signed, "/videos/5678/segment1.ts")
+			assert.ErrorIs(t, err, ErrNotGranted)
+		})
+	}
+
+	signer := New([]byte("abc123"))
+	signed, err := signer.SignSubtree("https://example.com/videos/1234/", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	// replaces the path of the signed URL
+	replace := func(p string) string {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		u.Path = p
+		return u.String()
+	}
+
+	t.Run("outside subtree", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify(replace("/videos/5678/segment1.ts")), ErrNotGranted)
+		assert.ErrorIs(t, signer.Verify(replace("/videos/12345/segment1.ts")), ErrNotGranted)
+		assert.ErrorIs(t, signer.Verify(replace("/videos/1234/../5678/segment1.ts")), ErrNotGranted)
+	})
+
+	t.Run("widened", func(t *testing.T) {
+		u, err := url.Parse(replace("/videos/5678/segment1.ts"))
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(subtreeParam, "/videos/")
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("ordinary URL", func(t *testing.T) {
+		// an ordinary signed URL cannot be turned into a subtree grant
+		signed, err := signer.Sign("https://example.com/videos/1234/", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		u, err := url.Parse(signed + "&" + subtreeParam + "=%2Fvideos%2F1234%2F")
+		require.NoError(t, err)
+		u.Path = "/videos/1234/segment1.ts"
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignSubtree("https://example.com/videos/1234/", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		segment, err := SubtreeURL(signed, "/videos/1234/segment1.ts")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, signer.Verify(segment), ErrExpired)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, unsigned := range []string{
+			"https://example.com/videos/1234",
+			"https://example.com/videos/1234/?foo=bar",
+			"https://example.com/videos/../1234/",
+		} {
+			_, err := signer.SignSubtree(unsigned, time.Now().Add(time.Minute))
+			assert.Error(t, err, unsigned)
+		}
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		var got *Result
+		handler := signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = ResultFromContext(r.Context())
+		}))
+		segment, err := SubtreeURL(signed, "/videos/1234/segment1.ts")
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", segment, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, got)
+		assert.Equal(t, "/videos/1234/", got.Subtree)
+		assert.Equal(t, "/videos/1234/segment1.ts", got.OriginalURL.Path)
+	})
+}
@@ -0,0 +1,112 @@
+package surl
+
+import (
+	"net/url"
+	"strings"
+)
+
+// suffixFormatter stores the signature and expiry at the end of the path of a
+// signed URL, before the extension of the final segment if it has one, e.g.
+// /a/b/c.<sig>.<expiry>.pdf, and otherwise in a final segment of their own,
+// e.g. /a/b/c/<sig>.<expiry>, so that the extension remains last. Dots in
+// the expiry, i.e. of millisecond expiries, are replaced with tildes. URLs
+// that never expire are unsupported.
+type suffixFormatter struct{}
+
+func (f *suffixFormatter) addExpiry(unsigned *url.URL, expiry string) {
+	expiry = strings.ReplaceAll(expiry, ".", "~")
+	editLastSegment(unsigned, func(segment string) string {
+		if i := strings.LastIndex(segment, "."); i > 0 && i < len(segment)-1 {
+			// insert before the extension
+			return segment[:i] + "." + expiry + segment[i:]
+		}
+		return segment + "/" + expiry
+	})
+}
+
+func (f *suffixFormatter) buildPayload(u url.URL, opts payloadOptions) string {
+	if opts.skipQuery {
+		u.RawQuery = ""
+	}
+	if opts.skipScheme {
+		u.Scheme = ""
+	}
+	if opts.skipHost {
+		u.Host = ""
+	}
+	return u.String()
+}
+
+func (f *suffixFormatter) addSignature(payload *url.URL, sig string) {
+	editLastSegment(payload, func(segment string) string {
+		parts := strings.Split(segment, ".")
+		if len(parts) == 1 {
+			// the segment is the expiry
+			return sig + "." + segment
+		}
+		// insert before the expiry
+		n := len(parts)
+		return strings.Join(append(parts[:n-2:n-2], sig, parts[n-2], parts[n-1]), ".")
+	})
+}
+
+func (f *suffixFormatter) extractSignature(u *url.URL) (string, error) {
+	var sig string
+	editLastSegment(u, func(segment string) string {
+		parts := strings.Split(segment, ".")
+		switch n := len(parts); {
+		case n == 2:
+			sig = parts[0]
+			return parts[1]
+		case n >= 4:
+			sig = parts[n-3]
+			return strings.Join(append(parts[:n-3:n-3], parts[n-2], parts[n-1]), ".")
+		}
+		return segment
+	})
+	if sig == "" {
+		return "", ErrInvalidFormat
+	}
+	return sig, nil
+}
+
+func (f *suffixFormatter) extractExpiry(u *url.URL) (string, error) {
+	var expiry string
+	editLastSegment(u, func(segment string) string {
+		parts := strings.Split(segment, ".")
+		switch n := len(parts); {
+		case n == 1:
+			// remove the segment, along with its slash
+			expiry = segment
+			return "\x00"
+		case n >= 3:
+			expiry = parts[n-2]
+			return strings.Join(append(parts[:n-2:n-2], parts[n-1]), ".")
+		}
+		return segment
+	})
+	if expiry == "" {
+		return "", ErrInvalidFormat
+	}
+	return strings.ReplaceAll(expiry, "~", "."), nil
+}
+
+// editLastSegment replaces the final segment of the path of a URL, and of its
+// encoded form, with the result of the function, which must not require
+// escaping. If the function returns a NUL byte the segment is removed along
+// with its preceding slash.
+func editLastSegment(u *url.URL, fn func(segment string) string) {
+	edit := func(p string) string {
+		i := strings.LastIndex(p, "/")
+		if edited := fn(p[i+1:]); edited != "\x00" {
+			return p[:i+1] + edited
+		} else if i < 0 {
+			return ""
+		}
+		return p[:i]
+	}
+	u.Path = edit(u.Path)
+	if u.RawPath != "" {
+		u.RawPath = edit(u.RawPath)
+	}
+}
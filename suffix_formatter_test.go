@@ -0,0 +1,110 @@
+package surl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuffixFormatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		expiry  string
+		payload string
+		signed  string
+	}{
+		{"extension", "/a/b/c.pdf", "3507595200", "/a/b/c.3507595200.pdf", "/a/b/c.abcdef.3507595200.pdf"},
+		{"multiple dots", "/a/b/c.tar.gz", "3507595200", "/a/b/c.tar.3507595200.gz", "/a/b/c.tar.abcdef.3507595200.gz"},
+		{"no extension", "/a/b/c", "3507595200", "/a/b/c/3507595200", "/a/b/c/abcdef.3507595200"},
+		{"trailing slash", "/a/b/", "3507595200", "/a/b//3507595200", "/a/b//abcdef.3507595200"},
+		{"dotfile", "/a/.b", "3507595200", "/a/.b/3507595200", "/a/.b/abcdef.3507595200"},
+		{"empty path", "", "3507595200", "/3507595200", "/abcdef.3507595200"},
+		{"millisecond expiry", "/a/b/c.pdf", "3507595200.250", "/a/b/c.3507595200~250.pdf", "/a/b/c.abcdef.3507595200~250.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &suffixFormatter{}
+			u := &url.URL{Path: tt.path}
+
+			f.addExpiry(u, tt.expiry)
+			assert.Equal(t, tt.payload, u.Path)
+
+			f.addSignature(u, "abcdef")
+			assert.Equal(t, tt.signed, u.Path)
+
+			sig, err := f.extractSignature(u)
+			require.NoError(t, err)
+			assert.Equal(t, "abcdef", sig)
+			assert.Equal(t, tt.payload, u.Path)
+
+			got, err := f.extractExpiry(u)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expiry, got)
+			assert.Equal(t, tt.path, u.Path)
+		})
+	}
+}
+
+func TestSuffixFormatter_Errors(t *testing.T) {
+	f := &suffixFormatter{}
+	for _, path := range []string{"", "/", "/a/b/c", "/a/b/c.d.pdf", "/a/b/.3507595200.pdf"} {
+		_, err := f.extractSignature(&url.URL{Path: path})
+		assert.ErrorIs(t, err, ErrInvalidFormat, path)
+	}
+}
+
+func TestWithSuffixFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"millisecond expiry", []Option{WithMillisecondExpiry()}},
+		{"base58 expiry", []Option{WithBase58Expiry()}},
+		{"timestamp expiry", []Option{WithTimestampExpiry()}},
+		{"skip query", []Option{SkipQuery()}},
+		{"self-describing", []Option{SelfDescribing()}},
+		{"prefix", []Option{PrefixPath("/signed")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), append([]Option{WithSuffixFormatter()}, tt.opts...)...)
+
+			signed, err := signer.SignFor("https://example.com/a/b/c.pdf?foo=bar", time.Minute)
+			require.NoError(t, err)
+			assert.Regexp(t, `/a/b/c\.[^/]+\.pdf\?foo=bar$`, signed)
+			assert.NoError(t, signer.Verify(signed))
+
+			unsigned, err := signer.Unsign(signed)
+			require.NoError(t, err)
+			assert.Equal(t, "https://example.com/a/b/c.pdf?foo=bar", unsigned)
+		})
+	}
+
+	t.Run("escaped path", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithSuffixFormatter())
+		signed, err := signer.SignFor("https://example.com/a%2Fb/c%20d.pdf", time.Minute)
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+
+		unsigned, err := signer.Unsign(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/a%2Fb/c%20d.pdf", unsigned)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithSuffixFormatter())
+		signed, err := signer.SignFor("https://example.com/a/b/c.pdf", -time.Minute)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrExpired)
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		_, err := NewWithValidation([]byte("abc123"), WithSuffixFormatter(), WithNoExpiry())
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	})
+}
@@ -0,0 +1,53 @@
+// Package surlecho verifies signed URLs in Echo applications.
+//
+//	e.Use(surlecho.Middleware(signer))
+//	e.GET("/files/*", func(c echo.Context) error {
+//		result, _ := surlecho.Result(c)
+//		return c.String(http.StatusOK, "link expires at "+result.ExpiresAt.String())
+//	})
+//
+// The package is a separate module, so that only applications using it depend
+// on Echo.
+package surlecho
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/leg100/surl/v2"
+)
+
+// ResultKey is the key under which the result of verification is stored in
+// the echo context.
+const ResultKey = "surl.result"
+
+// Middleware returns echo middleware that verifies the signed URL of each
+// request with the signer, as with surl.Signer.VerifyRequest. Requests that
+// fail verification are rejected with an *echo.HTTPError, with a 410 Gone
+// status if the URL has expired, or a 403 Forbidden status otherwise, which
+// wraps the error from the signer. The result of verification is stored in
+// the echo context, and retrieved with Result.
+func Middleware(signer *surl.Signer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			result, err := signer.VerifyRequestDetailed(c.Request())
+			if err != nil {
+				status := http.StatusForbidden
+				if errors.Is(err, surl.ErrExpired) {
+					status = http.StatusGone
+				}
+				return echo.NewHTTPError(status, err.Error()).SetInternal(err)
+			}
+			c.Set(ResultKey, result)
+			return next(c)
+		}
+	}
+}
+
+// Result retrieves the result of verification from the echo context,
+// populated by Middleware.
+func Result(c echo.Context) (*surl.Result, bool) {
+	result, ok := c.Get(ResultKey).(*surl.Result)
+	return result, ok
+}
@@ -0,0 +1,76 @@
+package surlecho
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	signer := surl.New([]byte("abc123"))
+
+	e := echo.New()
+	e.Use(Middleware(signer))
+	e.GET("/a/b/c", func(c echo.Context) error {
+		result, ok := Result(c)
+		require.True(t, ok)
+		return c.String(http.StatusOK, result.OriginalURL.Path)
+	})
+
+	valid, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	expired, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+		body   string
+	}{
+		{
+			name:   "valid",
+			target: valid,
+			want:   http.StatusOK,
+			body:   "/a/b/c",
+		},
+		{
+			name:   "expired",
+			target: expired,
+			want:   http.StatusGone,
+			body:   `{"message":"URL has expired"}` + "\n",
+		},
+		{
+			name:   "tampered",
+			target: valid + "&foo=bar",
+			want:   http.StatusForbidden,
+			body:   `{"message":"invalid signature"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			e.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+
+	t.Run("http error", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest("GET", expired, nil), httptest.NewRecorder())
+		err := Middleware(signer)(func(echo.Context) error { return nil })(c)
+
+		var httpErr *echo.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusGone, httpErr.Code)
+		assert.ErrorIs(t, err, surl.ErrExpired)
+	})
+}
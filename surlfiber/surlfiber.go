@@ -0,0 +1,57 @@
+// Package surlfiber verifies signed URLs in Fiber applications.
+//
+//	app.Use(surlfiber.Middleware(signer))
+//	app.Get("/files/*", func(c *fiber.Ctx) error {
+//		result, _ := surlfiber.Result(c)
+//		return c.SendString("link expires at " + result.ExpiresAt.String())
+//	})
+//
+// Fiber is built on fasthttp rather than net/http, so the request is converted
+// to a net/http request before verification, which reconstructs its URL from
+// the request URI, the Host header and the TLS state of the connection. The
+// package is a separate module, so that only applications using it depend on
+// Fiber.
+package surlfiber
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leg100/surl/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// ResultKey is the key under which the result of verification is stored in
+// the fiber context's locals.
+const ResultKey = "surl.result"
+
+// Middleware returns fiber middleware that verifies the signed URL of each
+// request with the signer, as with surl.Signer.VerifyRequest. Requests that
+// fail verification are rejected with a fiber error, with a 410 Gone status if
+// the URL has expired, or a 403 Forbidden status otherwise. The result of
+// verification is stored in the fiber context, and retrieved with Result.
+func Middleware(signer *surl.Signer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var r http.Request
+		if err := fasthttpadaptor.ConvertRequest(c.Context(), &r, true); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		result, err := signer.VerifyRequestDetailed(r.WithContext(c.UserContext()))
+		if err != nil {
+			if errors.Is(err, surl.ErrExpired) {
+				return fiber.NewError(fiber.StatusGone, err.Error())
+			}
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
+		c.Locals(ResultKey, result)
+		return c.Next()
+	}
+}
+
+// Result retrieves the result of verification from the fiber context,
+// populated by Middleware.
+func Result(c *fiber.Ctx) (*surl.Result, bool) {
+	result, ok := c.Locals(ResultKey).(*surl.Result)
+	return result, ok
+}
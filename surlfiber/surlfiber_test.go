@@ -0,0 +1,78 @@
+package surlfiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	signer := surl.New([]byte("abc123"))
+
+	app := fiber.New()
+	app.Use(Middleware(signer))
+	app.Get("/a/b/c", func(c *fiber.Ctx) error {
+		result, ok := Result(c)
+		require.True(t, ok)
+		return c.SendString(result.OriginalURL.Path)
+	})
+
+	valid, err := signer.Sign("http://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	expired, err := signer.Sign("http://example.com/a/b/c", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	post, err := signer.SignForMethods("http://example.com/a/b/c", time.Now().Add(time.Minute), http.MethodPost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+		body   string
+	}{
+		{
+			name:   "valid",
+			target: valid,
+			want:   http.StatusOK,
+			body:   "/a/b/c",
+		},
+		{
+			name:   "expired",
+			target: expired,
+			want:   http.StatusGone,
+			body:   "URL has expired",
+		},
+		{
+			name:   "tampered",
+			target: valid + "&foo=bar",
+			want:   http.StatusForbidden,
+			body:   "invalid signature",
+		},
+		{
+			name:   "method not permitted",
+			target: post,
+			want:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.target, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.body != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Package surlgin verifies signed URLs in Gin applications.
+//
+//	router.Use(surlgin.Middleware(signer))
+//	router.GET("/files/*path", func(c *gin.Context) {
+//		result, _ := surlgin.Result(c)
+//		c.String(http.StatusOK, "link expires at %s", result.ExpiresAt)
+//	})
+//
+// The package is a separate module, so that only applications using it depend
+// on Gin.
+package surlgin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leg100/surl/v2"
+)
+
+// ResultKey is the key under which the result of verification is stored in
+// the gin context.
+const ResultKey = "surl.result"
+
+// Middleware returns gin middleware that verifies the signed URL of each
+// request with the signer, as with surl.Signer.VerifyRequest. Requests that
+// fail verification are aborted with a JSON error, e.g.
+// {"error":"URL has expired"}, and a 410 Gone status if the URL has expired,
+// or a 403 Forbidden status otherwise. The result of verification is stored in
+// the gin context, and retrieved with Result.
+func Middleware(signer *surl.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := signer.VerifyRequestDetailed(c.Request)
+		if err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, surl.ErrExpired) {
+				status = http.StatusGone
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(ResultKey, result)
+		c.Next()
+	}
+}
+
+// Result retrieves the result of verification from the gin context,
+// populated by Middleware.
+func Result(c *gin.Context) (*surl.Result, bool) {
+	v, ok := c.Get(ResultKey)
+	if !ok {
+		return nil, false
+	}
+	result, ok := v.(*surl.Result)
+	return result, ok
+}
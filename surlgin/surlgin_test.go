@@ -0,0 +1,66 @@
+package surlgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leg100/surl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := surl.New([]byte("abc123"))
+
+	router := gin.New()
+	router.Use(Middleware(signer))
+	router.GET("/a/b/c", func(c *gin.Context) {
+		result, ok := Result(c)
+		require.True(t, ok)
+		c.String(http.StatusOK, result.OriginalURL.Path)
+	})
+
+	valid, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	expired, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+		body   string
+	}{
+		{
+			name:   "valid",
+			target: valid,
+			want:   http.StatusOK,
+			body:   "/a/b/c",
+		},
+		{
+			name:   "expired",
+			target: expired,
+			want:   http.StatusGone,
+			body:   `{"error":"URL has expired"}`,
+		},
+		{
+			name:   "tampered",
+			target: valid + "&foo=bar",
+			want:   http.StatusForbidden,
+			body:   `{"error":"invalid signature"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package surl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// templatePurpose distinguishes template tokens from other tokens.
+const templatePurpose = "template"
+
+// ErrTemplateMismatch is returned when a URL does not match a signed template
+// or violates one of its constraints.
+var ErrTemplateMismatch = errors.New("URL does not match template")
+
+// Template is a URI template (RFC 6570) along with constraints on the values
+// of its variables. Only simple string expansion ({var}), which matches a
+// single path segment, and reserved expansion ({+var}), which matches any
+// number of path segments, are supported.
+type Template struct {
+	// Template is the URI template, e.g.
+	// https://example.com/reports/{id}/pages/{page}
+	Template string `json:"t"`
+	// Constraints constrains the values of variables, keyed by variable
+	// name. Variables without constraints may take any value.
+	Constraints map[string]Constraint `json:"c,omitempty"`
+}
+
+// Constraint constrains the value of a template variable.
+type Constraint struct {
+	// Values, if non-empty, are the only values permitted.
+	Values []string `json:"v,omitempty"`
+	// Pattern, if non-empty, is a regular expression that a value must match
+	// in full.
+	Pattern string `json:"p,omitempty"`
+}
+
+// SignTemplate signs a URI template, producing a token that grants access to
+// any URL that is an expansion of the template satisfying its constraints,
+// e.g. every page of a report, without signing each URL individually. The
+// template is not encrypted.
+func (s *Signer) SignTemplate(t Template, expiry time.Time) (string, error) {
+	if _, _, err := t.compile(); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return s.signToken(templatePurpose, data, expiry)
+}
+
+// VerifyTemplate verifies a token produced by SignTemplate, and checks the
+// concrete URL is an expansion of the template that satisfies its
+// constraints, returning the values of the template variables.
+func (s *Signer) VerifyTemplate(token, concrete string) (map[string]string, error) {
+	data, err := s.verifyToken(templatePurpose, token)
+	if err != nil {
+		return nil, err
+	}
+	var t Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	return t.Match(concrete)
+}
+
+// Match checks the concrete URL is an expansion of the template that
+// satisfies its constraints, returning the values of the template variables.
+func (t Template) Match(concrete string) (map[string]string, error) {
+	re, names, err := t.compile()
+	if err != nil {
+		return nil, err
+	}
+	matches := re.FindStringSubmatch(concrete)
+	if matches == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateMismatch, concrete)
+	}
+	values := make(map[string]string, len(names))
+	for i, name := range names {
+		value, err := url.PathUnescape(matches[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateMismatch, concrete)
+		}
+		if hasDotSegment(value) {
+			// a value must not climb out of the template's path
+			return nil, fmt.Errorf("%w: %s", ErrTemplateMismatch, concrete)
+		}
+		if prev, ok := values[name]; ok && prev != value {
+			// a variable appearing more than once must take the same value
+			return nil, fmt.Errorf("%w: %s", ErrTemplateMismatch, concrete)
+		}
+		values[name] = value
+	}
+	for name, c := range t.Constraints {
+		value := values[name]
+		if len(c.Values) > 0 && !slices.Contains(c.Values, value) {
+			return nil, fmt.Errorf("%w: %s=%q not permitted", ErrTemplateMismatch, name, value)
+		}
+		if c.Pattern != "" {
+			if !regexp.MustCompile("^(?:" + c.Pattern + ")$").MatchString(value) {
+				return nil, fmt.Errorf("%w: %s=%q not permitted", ErrTemplateMismatch, name, value)
+			}
+		}
+	}
+	return values, nil
+}
+
+// compile compiles the template into a regular expression matching its
+// expansions, returning the names of the variables in the order in which they
+// are captured. It also validates the constraints.
+func (t Template) compile() (*regexp.Regexp, []string, error) {
+	var (
+		expr  strings.Builder
+		names []string
+		rest  = t.Template
+	)
+	expr.WriteString("^")
+	for {
+		literal, after, found := strings.Cut(rest, "{")
+		expr.WriteString(regexp.QuoteMeta(literal))
+		if !found {
+			break
+		}
+		name, after, found := strings.Cut(after, "}")
+		if !found {
+			return nil, nil, fmt.Errorf("invalid template: unclosed expression: %s", t.Template)
+		}
+		if reserved, ok := strings.CutPrefix(name, "+"); ok {
+			name = reserved
+			expr.WriteString("([^?#]*)")
+		} else {
+			expr.WriteString("([^/?#]*)")
+		}
+		if !validVarName(name) {
+			return nil, nil, fmt.Errorf("invalid template: unsupported expression: {%s}", name)
+		}
+		names = append(names, name)
+		rest = after
+	}
+	expr.WriteString("$")
+
+	for name, c := range t.Constraints {
+		if !slices.Contains(names, name) {
+			return nil, nil, fmt.Errorf("invalid template: constraint on unknown variable: %s", name)
+		}
+		if c.Pattern != "" {
+			if _, err := regexp.Compile("^(?:" + c.Pattern + ")$"); err != nil {
+				return nil, nil, fmt.Errorf("invalid template: constraint on %s: %w", name, err)
+			}
+		}
+	}
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return re, names, nil
+}
+
+// validVarName determines whether name is a valid, unqualified, variable name.
+func validVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || c == '.' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package surl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Template(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	token, err := signer.SignTemplate(Template{
+		Template: "https://example.com/reports/{id}/pages/{page}{+rest}",
+		Constraints: map[string]Constraint{
+			"id":   {Values: []string{"q1", "q2"}},
+			"page": {Pattern: `\d+`},
+		},
+	}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		concrete string
+		want     map[string]string
+		err      error
+	}{
+		{
+			name:     "match",
+			concrete: "https://example.com/reports/q1/pages/12",
+			want:     map[string]string{"id": "q1", "page": "12", "rest": ""},
+		},
+		{
+			name:     "reserved expansion",
+			concrete: "https://example.com/reports/q2/pages/3/figures/1.png",
+			want:     map[string]string{"id": "q2", "page": "3", "rest": "/figures/1.png"},
+		},
+		{
+			name:     "value not permitted",
+			concrete: "https://example.com/reports/q3/pages/12",
+			err:      ErrTemplateMismatch,
+		},
+		{
+			name:     "pattern not matched",
+			concrete: "https://example.com/reports/q1/pages/twelve",
+			err:      ErrTemplateMismatch,
+		},
+		{
+			name:     "different host",
+			concrete: "https://evil.com/reports/q1/pages/12",
+			err:      ErrTemplateMismatch,
+		},
+		{
+			name:     "reserved expansion with dot segments",
+			concrete: "https://example.com/reports/q2/pages/3/../../../admin",
+			err:      ErrTemplateMismatch,
+		},
+		{
+			name:     "simple expansion does not span segments",
+			concrete: "https://example.com/reports/q1/x/pages/12",
+			err:      ErrTemplateMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signer.VerifyTemplate(token, tt.concrete)
+			if tt.err != nil {
+				assert.Truef(t, errors.Is(err, tt.err), "got error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("invalid templates", func(t *testing.T) {
+		for _, tmpl := range []Template{
+			{Template: "https://example.com/{id"},
+			{Template: "https://example.com/{?query}"},
+			{Template: "https://example.com/{id}", Constraints: map[string]Constraint{"other": {}}},
+			{Template: "https://example.com/{id}", Constraints: map[string]Constraint{"id": {Pattern: "("}}},
+		} {
+			_, err := signer.SignTemplate(tmpl, time.Now().Add(time.Minute))
+			assert.Error(t, err, tmpl.Template)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := signer.SignTemplate(Template{Template: "https://example.com/{id}"}, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyTemplate(token, "https://example.com/1")
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+}
+
+func TestTemplate_Match_DotSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		concrete string
+	}{
+		{
+			name:     "reserved expansion",
+			template: "https://example.com/files/{+path}",
+			concrete: "https://example.com/files/../admin/secret",
+		},
+		{
+			name:     "simple expansion",
+			template: "https://example.com/reports/{id}/pages/{page}",
+			concrete: "https://example.com/reports/../pages/1",
+		},
+		{
+			name:     "escaped",
+			template: "https://example.com/reports/{id}/pages/{page}",
+			concrete: "https://example.com/reports/%2e%2e/pages/1",
+		},
+		{
+			name:     "current directory",
+			template: "https://example.com/files/{+path}",
+			concrete: "https://example.com/files/a/./b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Template{Template: tt.template}.Match(tt.concrete)
+			assert.ErrorIs(t, err, ErrTemplateMismatch)
+		})
+	}
+
+	t.Run("dots within segments", func(t *testing.T) {
+		got, err := Template{Template: "https://example.com/files/{+path}"}.Match("https://example.com/files/a/..b/c.tar.gz")
+		require.NoError(t, err)
+		assert.Equal(t, "a/..b/c.tar.gz", got["path"])
+	})
+}
@@ -0,0 +1,54 @@
+package surl
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Bytes(t *testing.T) {
+	signer := New([]byte("abc123"))
+
+	t.Run("valid", func(t *testing.T) {
+		token, err := signer.SignBytes([]byte("user@example.com"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, token, url.QueryEscape(token), "token should be URL-safe")
+
+		got, err := signer.VerifyBytes(token)
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", string(got))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := signer.SignBytes([]byte("user@example.com"), time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyBytes(token)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		token, err := New([]byte("xyz789")).SignBytes([]byte("user@example.com"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyBytes(token)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("cursor is not accepted", func(t *testing.T) {
+		token, err := signer.SignCursor([]byte("user@example.com"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		_, err = signer.VerifyBytes(token)
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := signer.VerifyBytes("abc.def")
+		assert.True(t, errors.Is(err, ErrInvalidFormat), "got error: %v", err)
+	})
+}
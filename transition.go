@@ -0,0 +1,54 @@
+package surl
+
+import (
+	"errors"
+	"time"
+)
+
+// Transition assists with migrating from one signer configuration to another,
+// e.g. a new prefix, formatter, or parameter names. It signs URLs using the
+// current configuration but verifies URLs against both the current and legacy
+// configurations, so that URLs already issued remain valid during the
+// transition.
+type Transition struct {
+	// Current signs URLs and is the first to verify URLs.
+	Current *Signer
+	// Legacy signers verify URLs that fail verification with Current, tried in
+	// order.
+	Legacy []*Signer
+	// OnLegacy, if non-nil, is called whenever a URL is verified by a legacy
+	// signer, with the URL and the index of the signer in Legacy. Use it to record a
+	// metric, and when it no longer fires, drop the legacy configuration.
+	OnLegacy func(signed string, index int)
+}
+
+// Sign generates a signed URL using the current signer.
+func (t *Transition) Sign(unsigned string, expiry time.Time) (string, error) {
+	return t.Current.Sign(unsigned, expiry)
+}
+
+// Verify verifies a signed URL, trying the current signer and then each legacy
+// signer in turn until one succeeds. If they all fail, ErrExpired is returned
+// if any signer found a valid signature that had expired; otherwise the error
+// from the current signer is returned.
+func (t *Transition) Verify(signed string) error {
+	err := t.Current.Verify(signed)
+	if err == nil {
+		return nil
+	}
+	expired := errors.Is(err, ErrExpired)
+	for i, legacy := range t.Legacy {
+		legacyErr := legacy.Verify(signed)
+		if legacyErr == nil {
+			if t.OnLegacy != nil {
+				t.OnLegacy(signed, i)
+			}
+			return nil
+		}
+		if errors.Is(legacyErr, ErrExpired) && !expired {
+			expired = true
+			err = legacyErr
+		}
+	}
+	return err
+}
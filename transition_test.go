@@ -0,0 +1,81 @@
+package surl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransition(t *testing.T) {
+	key := []byte("abc123")
+	oldest := New(key, PrefixPath("/s"))
+	older := New(key, WithPathFormatter())
+	current := New(key, WithShortQueryFormatter(), PrefixPath("/signed"))
+
+	var seen []int
+	transition := &Transition{
+		Current: current,
+		Legacy:  []*Signer{older, oldest},
+		OnLegacy: func(signed string, index int) {
+			seen = append(seen, index)
+		},
+	}
+
+	tests := []struct {
+		name   string
+		signer interface {
+			Sign(string, time.Time) (string, error)
+		}
+		expiry time.Time
+		want   error
+		seen   []int
+	}{
+		{
+			name:   "current",
+			signer: transition,
+			expiry: time.Now().Add(time.Minute),
+		},
+		{
+			name:   "older",
+			signer: older,
+			expiry: time.Now().Add(time.Minute),
+			seen:   []int{0},
+		},
+		{
+			name:   "oldest",
+			signer: oldest,
+			expiry: time.Now().Add(time.Minute),
+			seen:   []int{1},
+		},
+		{
+			name:   "expired legacy",
+			signer: oldest,
+			expiry: time.Now().Add(-time.Minute),
+			want:   ErrExpired,
+		},
+		{
+			name:   "unknown",
+			signer: New([]byte("xyz789"), WithShortQueryFormatter(), PrefixPath("/signed")),
+			expiry: time.Now().Add(time.Minute),
+			want:   ErrInvalidSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+
+			signed, err := tt.signer.Sign("https://example.com/a/b/c?foo=bar", tt.expiry)
+			require.NoError(t, err)
+
+			err = transition.Verify(signed)
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+			assert.Equal(t, tt.seen, seen)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package surl
+
+import (
+	"context"
+	"crypto/subtle"
+)
+
+// MinSignatureLength is the minimum length, in bytes, to which WithSignatureLength
+// truncates signatures.
+const MinSignatureLength = 12
+
+// WithSignatureLength instructs Signer to truncate signatures to n bytes,
+// producing shorter URLs where length matters, e.g. links sent by SMS. A
+// 16 byte signature is 22 characters long rather than the 43 characters of
+// the default 32 byte signature. Lengths below MinSignatureLength are raised
+// to it, and lengths exceeding that of the algorithm's signatures have no
+// effect. Only signatures of the exact length are accepted.
+//
+// Truncation trades security for length: an attacker forging a signature by
+// brute force needs on the order of 2^(8n) attempts, so a 16 byte signature
+// offers 128 bits of security, and the minimum 12 bytes, 96 bits, which
+// suffices for online attacks against URLs with short lifetimes but offers
+// less margin against a weakness in the algorithm.
+//
+// Only keyed hashes, i.e. the default algorithm, BLAKE2b, and HMAC, support
+// truncation; public key signatures truncated by a signer would fail
+// verification.
+func WithSignatureLength(n int) Option {
+	return func(s *Signer) {
+		s.signatureLength = max(n, MinSignatureLength)
+	}
+}
+
+// truncatedVerifier is implemented by algorithms whose signatures can be
+// verified when truncated.
+type truncatedVerifier interface {
+	// verifyTruncated returns ErrInvalidSignature if sig is not a valid
+	// signature of the data truncated to n bytes.
+	verifyTruncated(data, sig []byte, n int) error
+}
+
+// truncate truncates a signature to the configured length, if any.
+func (s *Signer) truncate(sig []byte) []byte {
+	if s.signatureLength > 0 && len(sig) > s.signatureLength {
+		return sig[:s.signatureLength]
+	}
+	return sig
+}
+
+// verifyWith verifies the signature using the algorithm, taking into account
+// the configured length, if any.
+func (s *Signer) verifyWith(ctx context.Context, alg algorithm, data, sig []byte) error {
+	if s.signatureLength == 0 {
+		if ca, ok := alg.(contextAlgorithm); ok {
+			return ca.verifyContext(ctx, data, sig)
+		}
+		return alg.verify(data, sig)
+	}
+	tv, ok := alg.(truncatedVerifier)
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return tv.verifyTruncated(data, sig, s.signatureLength)
+}
+
+func (k *keyedHash) verifyTruncated(data, sig []byte, n int) error {
+	sum := k.sum(data)
+	if len(sum) > n {
+		sum = sum[:n]
+	}
+	if subtle.ConstantTimeCompare(sig, sum) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
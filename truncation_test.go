@@ -0,0 +1,89 @@
+package surl
+
+import (
+	"crypto/ed25519"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSignatureLength(t *testing.T) {
+	full := New([]byte("abc123"))
+	signer := New([]byte("abc123"), WithSignatureLength(16))
+
+	signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Len(t, u.Query().Get("signature"), 22)
+	assert.NoError(t, signer.Verify(signed))
+
+	t.Run("full length is not accepted", func(t *testing.T) {
+		signed, err := full.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("truncated is not accepted by full length signer", func(t *testing.T) {
+		assert.ErrorIs(t, full.Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("shorter is not accepted", func(t *testing.T) {
+		q := u.Query()
+		q.Set("signature", q.Get("signature")[:16])
+		shorter := *u
+		shorter.RawQuery = q.Encode()
+		assert.ErrorIs(t, signer.Verify(shorter.String()), ErrInvalidSignature)
+	})
+
+	t.Run("minimum length", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithSignatureLength(4))
+		assert.Equal(t, MinSignatureLength, signer.Config().SignatureLength)
+
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		assert.Len(t, u.Query().Get("signature"), 16)
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("longer than signature", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithSignatureLength(64))
+
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+		assert.NoError(t, signer.Verify(signed))
+		assert.NoError(t, full.Verify(signed))
+	})
+
+	t.Run("hmac with fallback key", func(t *testing.T) {
+		old := New([]byte("old"), WithHMACSHA256(), WithSignatureLength(16))
+		signed, err := old.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+
+		rotated := New([]byte("new"), WithHMACSHA256(), WithFallbackKeys([]byte("old")), WithSignatureLength(16))
+		assert.NoError(t, rotated.Verify(signed))
+	})
+
+	t.Run("tokens", func(t *testing.T) {
+		token, err := signer.SignBytes([]byte("user@example.com"), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		got, err := signer.VerifyBytes(token)
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", string(got))
+	})
+
+	t.Run("public key algorithm", func(t *testing.T) {
+		_, private, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		signer := NewEd25519(private, WithSignatureLength(16))
+
+		signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(signed), ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,191 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLengthParam is the query parameter that carries the maximum content
+	// length of an upload.
+	maxLengthParam = "signature_max_length"
+	// contentTypesParam is the query parameter that carries the permitted
+	// content types of an upload.
+	contentTypesParam = "signature_content_types"
+)
+
+var (
+	// ErrContentTooLarge is returned when an upload exceeds the maximum
+	// content length of its signed URL.
+	ErrContentTooLarge = errors.New("content too large")
+	// ErrUnsupportedContentType is returned when the content type of an
+	// upload is not permitted by its signed URL.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// UploadConstraints constrain uploads made with a signed URL, in the manner
+// of the policy of an S3 presigned POST.
+type UploadConstraints struct {
+	// MaxContentLength is the maximum length of the content in bytes. Zero
+	// permits any length.
+	MaxContentLength int64
+	// ContentTypes are the permitted media types of the content, e.g.
+	// image/png, or image/* for any image. Empty permits any type.
+	ContentTypes []string
+	// Methods, if non-empty, restricts the URL to the HTTP methods, as does
+	// SignForMethods.
+	Methods []string
+}
+
+// SignUpload is like Sign but constrains uploads made with the signed URL. The
+// constraints are added to the URL and covered by the signature, and are
+// enforced by VerifyUpload.
+func (s *Signer) SignUpload(unsigned string, expiry time.Time, constraints UploadConstraints) (string, error) {
+	if constraints.MaxContentLength < 0 {
+		return "", fmt.Errorf("negative maximum content length: %d", constraints.MaxContentLength)
+	}
+	for _, t := range constraints.ContentTypes {
+		if err := checkContentType(t); err != nil {
+			return "", err
+		}
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	constrained := *s
+	constrained.upload = &constraints
+	if len(constraints.Methods) > 0 {
+		if constrained.methods, err = normalizeMethods(constraints.Methods); err != nil {
+			return "", err
+		}
+	}
+	if err := constrained.signURL(context.Background(), u, expiry, ""); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// VerifyUpload is like VerifyRequest but also enforces the constraints of a URL
+// signed with SignUpload. The request is rejected with ErrContentTooLarge if
+// its Content-Length header exceeds the maximum, and its body is limited to the
+// maximum, so that reading beyond it fails, which catches uploads without a
+// Content-Length header. The request is rejected with
+// ErrUnsupportedContentType unless its Content-Type header is permitted.
+func (s *Signer) VerifyUpload(r *http.Request) error {
+	result, err := s.verifyRequest(r)
+	if err != nil {
+		return err
+	}
+	return checkUpload(r, result.Upload)
+}
+
+// checkUpload checks the request satisfies the constraints, limiting its body
+// to the maximum content length.
+func checkUpload(r *http.Request, constraints *UploadConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+	if max := constraints.MaxContentLength; max > 0 {
+		if r.ContentLength > max {
+			return fmt.Errorf("%w: %d bytes exceeds %d", ErrContentTooLarge, r.ContentLength, max)
+		}
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(nil, r.Body, max)
+		}
+	}
+	if len(constraints.ContentTypes) > 0 {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrUnsupportedContentType, err.Error())
+		}
+		if !matchContentType(constraints.ContentTypes, mediaType) {
+			return fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+		}
+	}
+	return nil
+}
+
+// checkContentType checks that a permitted content type is a media type, and
+// contains none of the characters separating it from other types or bindings.
+func checkContentType(t string) error {
+	if !strings.Contains(t, "/") || strings.ContainsAny(t, ",;: ") {
+		return fmt.Errorf("%w: invalid content type: %q", ErrUnsupportedContentType, t)
+	}
+	return nil
+}
+
+// matchContentType reports whether the media type matches any of the
+// permitted types, which may have a wildcard subtype, e.g. image/*.
+func matchContentType(permitted []string, mediaType string) bool {
+	for _, t := range permitted {
+		t = strings.ToLower(t)
+		if t == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty reports whether there are no content constraints.
+func (c *UploadConstraints) isEmpty() bool {
+	return c.MaxContentLength == 0 && len(c.ContentTypes) == 0
+}
+
+// uploadBinding binds the content constraints of a signed URL, along with any
+// other binding, to its signature.
+func uploadBinding(c *UploadConstraints, binding string) string {
+	return tagBinding("upload", strconv.FormatInt(c.MaxContentLength, 10)+":"+strings.Join(c.ContentTypes, ","), binding)
+}
+
+// addUpload adds the content constraints to the query of a signed URL.
+func addUpload(u *url.URL, c *UploadConstraints) {
+	if c.MaxContentLength > 0 {
+		appendQueryParam(u, maxLengthParam, strconv.FormatInt(c.MaxContentLength, 10))
+	}
+	if len(c.ContentTypes) > 0 {
+		appendQueryParam(u, contentTypesParam, strings.Join(c.ContentTypes, ","))
+	}
+}
+
+// extractUpload removes the content constraints from the query of a signed
+// URL, returning nil if it has none.
+func extractUpload(u *url.URL) (*UploadConstraints, error) {
+	var c UploadConstraints
+	maxLength, err := removeQueryParam(u, maxLengthParam)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid maximum content length", ErrInvalidFormat)
+	}
+	if maxLength != "" {
+		c.MaxContentLength, err = strconv.ParseInt(maxLength, 10, 64)
+		if err != nil || c.MaxContentLength <= 0 {
+			return nil, fmt.Errorf("%w: invalid maximum content length: %s", ErrInvalidFormat, maxLength)
+		}
+	}
+	types, err := removeQueryParam(u, contentTypesParam)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid content types", ErrInvalidFormat)
+	}
+	if types != "" {
+		c.ContentTypes = strings.Split(types, ",")
+		for _, t := range c.ContentTypes {
+			if err := checkContentType(t); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+			}
+		}
+	}
+	if c.isEmpty() {
+		return nil, nil
+	}
+	return &c, nil
+}
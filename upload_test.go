@@ -0,0 +1,94 @@
+package surl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignUpload(t *testing.T) {
+	signer := New([]byte("abc123"))
+	expiry := time.Now().Add(time.Minute)
+
+	signed, err := signer.SignUpload("https://example.com/uploads/a", expiry, UploadConstraints{
+		MaxContentLength: 10,
+		ContentTypes:     []string{"image/*", "application/pdf"},
+		Methods:          []string{"PUT"},
+	})
+	require.NoError(t, err)
+
+	upload := func(method, body, contentType string) *http.Request {
+		r := httptest.NewRequest(method, signed, strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		return r
+	}
+
+	assert.NoError(t, signer.VerifyUpload(upload("PUT", "abc", "image/png")))
+	assert.NoError(t, signer.VerifyUpload(upload("PUT", "abc", "application/pdf; charset=binary")))
+	assert.ErrorIs(t, signer.VerifyUpload(upload("PUT", "abc", "text/html")), ErrUnsupportedContentType)
+	assert.ErrorIs(t, signer.VerifyUpload(upload("PUT", "abc", "")), ErrUnsupportedContentType)
+	assert.ErrorIs(t, signer.VerifyUpload(upload("PUT", "abcdefghijk", "image/png")), ErrContentTooLarge)
+	assert.ErrorIs(t, signer.VerifyUpload(upload("GET", "", "image/png")), ErrMethodNotAllowed)
+
+	t.Run("unknown content length", func(t *testing.T) {
+		r := upload("PUT", "abcdefghijk", "image/png")
+		r.ContentLength = -1
+		require.NoError(t, signer.VerifyUpload(r))
+
+		_, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		assert.ErrorAs(t, err, &maxBytesErr)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(maxLengthParam, "1000000")
+		u.RawQuery = q.Encode()
+
+		r := httptest.NewRequest("PUT", u.String(), strings.NewReader("abc"))
+		r.Header.Set("Content-Type", "image/png")
+		assert.ErrorIs(t, signer.VerifyUpload(r), ErrInvalidSignature)
+	})
+
+	t.Run("methods folded into content types", func(t *testing.T) {
+		signed, err := signer.SignUpload("https://example.com/uploads/a", expiry, UploadConstraints{
+			ContentTypes: []string{"image/png"},
+			Methods:      []string{"PUT"},
+		})
+		require.NoError(t, err)
+
+		for _, forged := range []string{"image/png:methods:PUT", "image/png" + methodsBinding([]string{"PUT"}, "")} {
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			q := u.Query()
+			q.Set(contentTypesParam, forged)
+			q.Del(methodsParam)
+			u.RawQuery = q.Encode()
+
+			assert.ErrorIs(t, signer.VerifyRequest(httptest.NewRequest("GET", u.String(), nil)), ErrInvalidFormat, forged)
+		}
+	})
+
+	t.Run("unconstrained", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/uploads/a", expiry)
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.VerifyUpload(httptest.NewRequest("PUT", signed, strings.NewReader("abc"))))
+	})
+
+	t.Run("invalid content type", func(t *testing.T) {
+		_, err := signer.SignUpload("https://example.com/uploads/a", expiry, UploadConstraints{
+			ContentTypes: []string{"image/png,text/html"},
+		})
+		assert.ErrorIs(t, err, ErrUnsupportedContentType)
+	})
+}
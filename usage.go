@@ -0,0 +1,82 @@
+package surl
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Usage records a request made with a valid signed URL.
+type Usage struct {
+	// LinkID identifies the signed URL.
+	LinkID string
+	// Time is the time of the request.
+	Time time.Time
+	// RemoteAddr is the network address of the client.
+	RemoteAddr string
+	// UserAgent is the user agent of the client.
+	UserAgent string
+}
+
+// UsageStore records and retrieves usages of signed URLs.
+type UsageStore interface {
+	// Record records a usage.
+	Record(ctx context.Context, usage Usage) error
+	// Usages retrieves the usages of a signed URL, oldest first.
+	Usages(ctx context.Context, linkID string) ([]Usage, error)
+}
+
+// WithUsageStore instructs Signer to record a usage in the store whenever a
+// request is verified by one of its handlers or middleware. This permits
+// reporting how often a link has been opened, e.g. "this share link was
+// opened 7 times". Errors from the store are ignored so that they do not deny
+// access.
+func WithUsageStore(store UsageStore) Option {
+	return func(s *Signer) {
+		s.usage = store
+	}
+}
+
+// LinkID returns the ID of a signed URL, for querying its usages. The URL is
+// not verified.
+func (s *Signer) LinkID(signed string) (string, error) {
+	u, err := url.ParseRequestURI(signed)
+	if err != nil {
+		return "", err
+	}
+	s, _, err = s.prepare(context.Background(), u)
+	if err != nil {
+		return "", err
+	}
+	return s.extractSignature(u)
+}
+
+// MemoryUsageStore is an in-memory UsageStore, suitable for a single instance
+// and for testing. Usages are retained indefinitely.
+type MemoryUsageStore struct {
+	mu     sync.Mutex
+	usages map[string][]Usage
+}
+
+// Record implements UsageStore.
+func (m *MemoryUsageStore) Record(ctx context.Context, usage Usage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.usages == nil {
+		m.usages = make(map[string][]Usage)
+	}
+	m.usages[usage.LinkID] = append(m.usages[usage.LinkID], usage)
+	return nil
+}
+
+// Usages implements UsageStore.
+func (m *MemoryUsageStore) Usages(ctx context.Context, linkID string) ([]Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usages := make([]Usage, len(m.usages[linkID]))
+	copy(usages, m.usages[linkID])
+	return usages, nil
+}
@@ -0,0 +1,42 @@
+package surl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Usage(t *testing.T) {
+	store := &MemoryUsageStore{}
+	signer := New([]byte("abc123"), WithPathFormatter(), WithUsageStore(store))
+
+	mux := NewServeMux(signer)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	signed, err := signer.Sign("https://example.com/a/b/c", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	other, err := signer.Sign("https://example.com/x/y/z", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	for _, u := range []string{signed, signed, other, signed + "tampered"} {
+		r := httptest.NewRequest("GET", u, nil)
+		r.Header.Set("User-Agent", "test")
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	id, err := signer.LinkID(signed)
+	require.NoError(t, err)
+
+	usages, err := store.Usages(context.Background(), id)
+	require.NoError(t, err)
+	if assert.Len(t, usages, 2) {
+		assert.Equal(t, id, usages[0].LinkID)
+		assert.Equal(t, "test", usages[0].UserAgent)
+		assert.Equal(t, "192.0.2.1:1234", usages[0].RemoteAddr)
+	}
+}
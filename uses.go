@@ -0,0 +1,135 @@
+package surl
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxUsesParam is the query parameter that carries the maximum number of uses
+// of a signed URL.
+const maxUsesParam = "signature_max_uses"
+
+var (
+	// ErrUsageExceeded is returned when a signed URL has been used its
+	// maximum number of times.
+	ErrUsageExceeded = errors.New("URL has exceeded its maximum number of uses")
+	// errNoUseCounter is returned when verifying a URL with a maximum number
+	// of uses without a use counter to enforce it.
+	errNoUseCounter = errors.New("cannot enforce maximum uses without a use counter")
+)
+
+// UseCounter counts the uses of signed URLs with a maximum number of uses.
+type UseCounter interface {
+	// Increment increments the number of uses of the signed URL with the ID,
+	// returning the new number. It must do so atomically, so that concurrent
+	// requests are counted correctly. The number need only be retained until
+	// the URL expires, or indefinitely if expiresAt is zero, for a URL that
+	// never expires.
+	Increment(ctx context.Context, id string, expiresAt time.Time) (int, error)
+}
+
+// WithUseCounter instructs Signer to count the uses of URLs signed with
+// SignWithMaxUses, failing verification with ErrUsageExceeded once a URL has
+// been used its maximum number of times. Verification of such URLs fails
+// without a use counter. As with WithNonceStore, only Verify, VerifyContext,
+// VerifyURL, VerifyRequest and the handlers and middleware in this package
+// count uses. Errors from the counter fail verification.
+func WithUseCounter(counter UseCounter) Option {
+	return func(s *Signer) {
+		s.uses = counter
+	}
+}
+
+// SignWithMaxUses is like Sign but the signed URL may only be used n times,
+// e.g. a download link permitting three attempts. A random, unique ID and the
+// maximum number of uses are added to the URL, covered by the signature, and
+// the signer verifying the URL must be configured with WithUseCounter.
+func (s *Signer) SignWithMaxUses(unsigned string, expiry time.Time, n int) (string, error) {
+	if n < 1 {
+		return "", fmt.Errorf("maximum uses must be at least one: %d", n)
+	}
+	u, err := url.ParseRequestURI(unsigned)
+	if err != nil {
+		return "", err
+	}
+	limited := *s
+	limited.maxUses = n
+	if err := limited.signURL(context.Background(), u, expiry, ""); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// usesBinding binds the maximum number of uses of a signed URL, along with any
+// other binding, to its signature.
+func usesBinding(n int, binding string) string {
+	return tagBinding("uses", strconv.Itoa(n), binding)
+}
+
+// extractMaxUses removes the maximum number of uses from the query of a
+// signed URL, returning zero if it has none.
+func extractMaxUses(u *url.URL) (int, error) {
+	value, err := removeQueryParam(u, maxUsesParam)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: invalid maximum uses: %s", ErrInvalidFormat, value)
+	}
+	return n, nil
+}
+
+// countUse counts a use of the verified URL.
+func (s *Signer) countUse(ctx context.Context, result *Result) error {
+	if s.uses == nil {
+		return errNoUseCounter
+	}
+	n, err := s.uses.Increment(ctx, result.ID, result.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("counting use: %w", err)
+	}
+	if n > result.MaxUses {
+		return ErrUsageExceeded
+	}
+	return nil
+}
+
+// MemoryUseCounter is an in-memory UseCounter, suitable for a single instance
+// and for testing. Counts are evicted once their URLs expire. The zero value
+// is ready to use.
+type MemoryUseCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+	expiry nonceHeap
+	now    func() time.Time
+}
+
+// Increment implements UseCounter.
+func (m *MemoryUseCounter) Increment(ctx context.Context, id string, expiresAt time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	now := time.Now()
+	if m.now != nil {
+		now = m.now()
+	}
+	for len(m.expiry) > 0 && now.After(m.expiry[0].expiresAt) {
+		entry := heap.Pop(&m.expiry).(nonceEntry)
+		delete(m.counts, entry.nonce)
+	}
+	if _, ok := m.counts[id]; !ok && !expiresAt.IsZero() {
+		heap.Push(&m.expiry, nonceEntry{nonce: id, expiresAt: expiresAt})
+	}
+	m.counts[id]++
+	return m.counts[id], nil
+}
@@ -0,0 +1,123 @@
+package surl
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWithMaxUses(t *testing.T) {
+	signer := New([]byte("abc123"), WithUseCounter(&MemoryUseCounter{}))
+	expiry := time.Now().Add(time.Minute)
+
+	signed, err := signer.SignWithMaxUses("https://example.com/a/b/c", expiry, 3)
+	require.NoError(t, err)
+
+	// not counted
+	assert.NoError(t, signer.VerifyIgnoreExpiry(signed))
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, signer.Verify(signed))
+	}
+	assert.ErrorIs(t, signer.Verify(signed), ErrUsageExceeded)
+
+	t.Run("result", func(t *testing.T) {
+		signed, err := signer.SignWithMaxUses("https://example.com/a/b/c", expiry, 2)
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		result, err := signer.verifyURL(context.Background(), u, "")
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.MaxUses)
+		assert.NotEmpty(t, result.ID)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(maxUsesParam, "100")
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("folded into ID", func(t *testing.T) {
+		signed, err := signer.SignWithMaxUses("https://example.com/a/b/c", expiry, 1)
+		require.NoError(t, err)
+		id, err := URLID(signed)
+		require.NoError(t, err)
+
+		for _, forged := range []string{id + ":uses:1", id + "uses:1:1"} {
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			q := u.Query()
+			q.Set(idParam, forged)
+			q.Del(maxUsesParam)
+			u.RawQuery = q.Encode()
+
+			for i := 0; i < 3; i++ {
+				assert.Error(t, signer.Verify(u.String()), forged)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := signer.SignWithMaxUses("https://example.com/a/b/c", expiry, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("no counter", func(t *testing.T) {
+		signer := New([]byte("abc123"))
+		signed, err := signer.SignWithMaxUses("https://example.com/a/b/c", expiry, 1)
+		require.NoError(t, err)
+
+		assert.Error(t, signer.Verify(signed))
+	})
+
+	t.Run("counter error", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithUseCounter(useCounterFunc(func(context.Context, string, time.Time) (int, error) {
+			return 0, errors.New("unavailable")
+		})))
+		signed, err := signer.SignWithMaxUses("https://example.com/a/b/c", expiry, 1)
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, signer.Verify(signed), "unavailable")
+	})
+}
+
+// useCounterFunc adapts a function to a UseCounter.
+type useCounterFunc func(ctx context.Context, id string, expiresAt time.Time) (int, error)
+
+func (f useCounterFunc) Increment(ctx context.Context, id string, expiresAt time.Time) (int, error) {
+	return f(ctx, id, expiresAt)
+}
+
+func TestMemoryUseCounter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	counter := &MemoryUseCounter{now: func() time.Time { return now }}
+
+	n, err := counter.Increment(ctx, "a", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, err = counter.Increment(ctx, "a", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, err = counter.Increment(ctx, "b", now.Add(time.Hour))
+	require.NoError(t, err)
+
+	// evicts a once expired
+	now = now.Add(2 * time.Minute)
+	_, err = counter.Increment(ctx, "c", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.NotContains(t, counter.counts, "a")
+	assert.Contains(t, counter.counts, "b")
+}
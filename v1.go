@@ -0,0 +1,43 @@
+package surl
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+)
+
+// WithV1Compat instructs Signer to also accept URLs signed by version 1 of
+// this module, so that links already delivered, e.g. in emails, remain valid
+// after upgrading. Version 1 computed signatures using HMAC-SHA256 rather than
+// keyed BLAKE2b-256, but otherwise signed URLs in the same format, so the
+// signer should be configured with the formatter, prefix and expiry encoding
+// that the version 1 signer was. Signatures are accepted from the signer's key
+// and its fallback keys, and only ever verified, never produced.
+//
+// It has no effect on a signer constructed with NewEd25519 or a backend.
+func WithV1Compat() Option {
+	return func(s *Signer) {
+		s.v1Compat = true
+	}
+}
+
+// verifyV1 verifies the signature as version 1 of this module computed it,
+// using HMAC-SHA256 with the signer's key and then each of its fallback keys,
+// until one succeeds or fails for a reason other than an invalid signature.
+func (s *Signer) verifyV1(ctx context.Context, data, sig []byte) error {
+	err := ErrInvalidSignature
+	if s.key == nil {
+		return err
+	}
+	keys := [][]byte{s.key}
+	for _, fb := range s.fallbacks {
+		keys = append(keys, fb.key)
+	}
+	for _, key := range keys {
+		alg := newHMAC("hmac-sha256", sha256.New, key)
+		if err = s.verifyWith(ctx, alg, data, sig); !errors.Is(err, ErrInvalidSignature) {
+			return err
+		}
+	}
+	return err
+}
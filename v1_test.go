@@ -0,0 +1,56 @@
+package surl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithV1Compat(t *testing.T) {
+	// signV1 signs the URL as version 1 of the module did, with the expiry in
+	// the query, sorted amongst the other parameters, and the HMAC-SHA256
+	// signature of the resulting URL appended.
+	signV1 := func(key []byte, expiry time.Time) string {
+		unsigned := "https://example.com/a/b/c?expiry=" + strconv.FormatInt(expiry.Unix(), 10) + "&foo=bar"
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(unsigned))
+		return unsigned + "&signature=" + base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	}
+	signer := New([]byte("abc123"), WithV1Compat())
+
+	t.Run("v1", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(signV1([]byte("abc123"), time.Now().Add(time.Minute))))
+	})
+
+	t.Run("v1 without compat", func(t *testing.T) {
+		signed := signV1([]byte("abc123"), time.Now().Add(time.Minute))
+		assert.ErrorIs(t, New([]byte("abc123")).Verify(signed), ErrInvalidSignature)
+	})
+
+	t.Run("v1 expired", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify(signV1([]byte("abc123"), time.Now().Add(-time.Minute))), ErrExpired)
+	})
+
+	t.Run("v1 with other key", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify(signV1([]byte("xyz789"), time.Now().Add(time.Minute))), ErrInvalidSignature)
+	})
+
+	t.Run("v1 with fallback key", func(t *testing.T) {
+		signer := New([]byte("new"), WithFallbackKeys([]byte("abc123")), WithV1Compat())
+		assert.NoError(t, signer.Verify(signV1([]byte("abc123"), time.Now().Add(time.Minute))))
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		signed, err := signer.Sign("https://example.com/a/b/c?foo=bar", time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+		assert.NoError(t, New([]byte("abc123")).Verify(signed), "signs as v2")
+	})
+}
@@ -0,0 +1,86 @@
+package surl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// ErrInvalidOption is returned by NewWithValidation when the key or options
+// are invalid.
+var ErrInvalidOption = errors.New("invalid option")
+
+// NewWithValidation is like New but validates the key and options, returning
+// ErrInvalidOption rather than silently accepting configurations that would
+// otherwise fail, or misbehave, at Sign or Verify time, e.g.:
+//
+//   - an empty key, or a key longer than the 64 bytes permitted by the default
+//     algorithm, which New truncates
+//   - a prefix that does not begin with a slash
+//   - more than one formatter, or more than one expiry encoding
+//   - a nil expiry encoding passed to WithExpiryEncoding
+//   - a formatter that does not support the expiry configuration, e.g.
+//     WithHostFormatter along with WithBase58Expiry
+//   - negative durations
+//   - WithNoExpiry along with WithMaxLifetime, which rejects every URL that
+//     never expires
+//   - SelfDescribing along with a configuration that cannot be described
+func NewWithValidation(key []byte, opts ...Option) (*Signer, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidOption)
+	}
+	s := New(key, opts...)
+	if s.err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOption, s.err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validate validates the configuration of the signer.
+func (s *Signer) validate() error {
+	if algorithmName(s.alg) == "blake2b-256" && len(s.key) > blake2b.Size {
+		return fmt.Errorf("%w: key is longer than %d bytes", ErrInvalidOption, blake2b.Size)
+	}
+	for _, prefix := range append([]string{s.prefix}, s.prefixes...) {
+		if prefix != "" && !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("%w: prefix does not begin with a slash: %s", ErrInvalidOption, prefix)
+		}
+	}
+	if s.formatterOptions > 1 {
+		return fmt.Errorf("%w: conflicting formatters", ErrInvalidOption)
+	}
+	if s.ExpiryEncoding == nil {
+		return fmt.Errorf("%w: missing expiry encoding", ErrInvalidOption)
+	}
+	if s.encodingOptions > 1 {
+		return fmt.Errorf("%w: conflicting expiry encodings", ErrInvalidOption)
+	}
+	if s.maxLifetime < 0 || s.granularity < 0 || s.webhookTolerance < 0 {
+		return fmt.Errorf("%w: negative duration", ErrInvalidOption)
+	}
+	if s.noExpiry && s.maxLifetime > 0 {
+		return fmt.Errorf("%w: URLs that never expire exceed any maximum lifetime", ErrInvalidOption)
+	}
+	if _, ok := s.formatter.(*suffixFormatter); ok && s.noExpiry {
+		return fmt.Errorf("%w: the suffix formatter does not support URLs that never expire", ErrInvalidOption)
+	}
+	if _, ok := s.formatter.(*hostFormatter); ok {
+		if s.noExpiry {
+			return fmt.Errorf("%w: the host formatter does not support URLs that never expire", ErrInvalidOption)
+		}
+		if encodingName(s.ExpiryEncoding) != "decimal" || s.millisecondExpiry {
+			return fmt.Errorf("%w: the host formatter requires decimal expiries without milliseconds", ErrInvalidOption)
+		}
+	}
+	if s.selfDescribing {
+		if _, err := s.describe(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidOption, err)
+		}
+	}
+	return nil
+}
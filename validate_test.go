@@ -0,0 +1,98 @@
+package surl
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithValidation(t *testing.T) {
+	key := []byte("abc123")
+
+	tests := []struct {
+		name string
+		key  []byte
+		opts []Option
+		err  bool
+	}{
+		{
+			name: "defaults",
+			key:  key,
+		},
+		{
+			name: "options",
+			key:  key,
+			opts: []Option{WithPathFormatter(), WithBase58Expiry(), PrefixPath("/signed"), SelfDescribing()},
+		},
+		{
+			name: "empty key",
+			err:  true,
+		},
+		{
+			name: "long key",
+			key:  bytes.Repeat([]byte("a"), 65),
+			err:  true,
+		},
+		{
+			name: "long HMAC key",
+			key:  bytes.Repeat([]byte("a"), 65),
+			opts: []Option{WithHMACSHA256()},
+		},
+		{
+			name: "relative prefix",
+			key:  key,
+			opts: []Option{PrefixPath("signed")},
+			err:  true,
+		},
+		{
+			name: "conflicting formatters",
+			key:  key,
+			opts: []Option{WithPathFormatter(), WithQueryFormatter()},
+			err:  true,
+		},
+		{
+			name: "conflicting expiry encodings",
+			key:  key,
+			opts: []Option{WithBase58Expiry(), WithTimestampExpiry()},
+			err:  true,
+		},
+		{
+			name: "negative duration",
+			key:  key,
+			opts: []Option{WithMaxLifetime(-time.Hour)},
+			err:  true,
+		},
+		{
+			name: "no expiry with maximum lifetime",
+			key:  key,
+			opts: []Option{WithNoExpiry(), WithMaxLifetime(time.Hour)},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewWithValidation(tt.key, tt.opts...)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidOption)
+				return
+			}
+			require.NoError(t, err)
+
+			signed, err := signer.SignFor("https://example.com/a/b/c", time.Minute)
+			require.NoError(t, err)
+			assert.NoError(t, signer.Verify(signed))
+		})
+	}
+}
+
+func TestNewWithValidation_AppliesOptionsOnce(t *testing.T) {
+	var applied int
+	counting := func(s *Signer) { applied++ }
+
+	_, err := NewWithValidation([]byte("abc123"), counting, WithPathFormatter())
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+}
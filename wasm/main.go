@@ -0,0 +1,159 @@
+//go:build js && wasm
+
+// Command wasm exposes signing and verification to JavaScript, e.g. in a
+// browser or Cloudflare Worker, producing URLs in the same format as this
+// package. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o surl.wasm ./wasm
+//
+// Once loaded, it defines a global surl object with a single function,
+// newSigner, taking a key and an optional object of options, and returning a
+// signer with sign and verify methods:
+//
+//	const signer = surl.newSigner("secret_key", {formatter: "path"});
+//	const {url, error} = signer.sign("https://example.com/a", new Date(Date.now() + 3600e3));
+//	const {valid, error} = signer.verify(url);
+//
+// The expiry passed to sign is a Date or a number of seconds since the Unix
+// epoch. The options are algorithm (blake2b-256, hmac-sha256 or hmac-sha512),
+// formatter (query, short-query or path), expiryEncoding (decimal or base58),
+// prefix, purpose, skipQuery, skipScheme, skipHost and selfDescribing.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+	"time"
+
+	"github.com/leg100/surl/v2"
+)
+
+func main() {
+	js.Global().Set("surl", map[string]any{
+		"newSigner": js.FuncOf(newSigner),
+	})
+	// keep running so that the functions remain callable
+	select {}
+}
+
+// newSigner constructs a signer from a key and options, returning an object
+// with its sign and verify methods, or an object with an error.
+func newSigner(_ js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString || args[0].String() == "" {
+		return errorResult(errors.New("missing key"))
+	}
+	var options js.Value
+	if len(args) > 1 {
+		options = args[1]
+	}
+	opts, err := signerOptions(options)
+	if err != nil {
+		return errorResult(err)
+	}
+	signer := surl.New([]byte(args[0].String()), opts...)
+
+	return map[string]any{
+		"sign": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			if len(args) < 2 {
+				return errorResult(errors.New("sign requires a url and an expiry"))
+			}
+			expiry, err := parseExpiry(args[1])
+			if err != nil {
+				return errorResult(err)
+			}
+			signed, err := signer.Sign(args[0].String(), expiry)
+			if err != nil {
+				return errorResult(err)
+			}
+			return map[string]any{"url": signed}
+		}),
+		"verify": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			if len(args) < 1 {
+				return errorResult(errors.New("verify requires a url"))
+			}
+			if err := signer.Verify(args[0].String()); err != nil {
+				return map[string]any{"valid": false, "error": err.Error()}
+			}
+			return map[string]any{"valid": true}
+		}),
+	}
+}
+
+// signerOptions converts an object of options to signer options.
+func signerOptions(options js.Value) ([]surl.Option, error) {
+	if options.IsUndefined() || options.IsNull() {
+		return nil, nil
+	}
+	str := func(name string) string {
+		if v := options.Get(name); v.Type() == js.TypeString {
+			return v.String()
+		}
+		return ""
+	}
+	flag := func(name string) bool {
+		return options.Get(name).Truthy()
+	}
+
+	var opts []surl.Option
+	switch str("algorithm") {
+	case "", "blake2b-256":
+	case "hmac-sha256":
+		opts = append(opts, surl.WithHMACSHA256())
+	case "hmac-sha512":
+		opts = append(opts, surl.WithHMACSHA512())
+	default:
+		return nil, fmt.Errorf("unknown algorithm: %s", str("algorithm"))
+	}
+	if purpose := str("purpose"); purpose != "" {
+		opts = append(opts, surl.WithPurpose(purpose))
+	}
+	switch str("formatter") {
+	case "", "query":
+	case "short-query":
+		opts = append(opts, surl.WithShortQueryFormatter())
+	case "path":
+		opts = append(opts, surl.WithPathFormatter())
+	default:
+		return nil, fmt.Errorf("unknown formatter: %s", str("formatter"))
+	}
+	switch str("expiryEncoding") {
+	case "", "decimal":
+	case "base58":
+		opts = append(opts, surl.WithBase58Expiry())
+	default:
+		return nil, fmt.Errorf("unknown expiry encoding: %s", str("expiryEncoding"))
+	}
+	if prefix := str("prefix"); prefix != "" {
+		opts = append(opts, surl.PrefixPath(prefix))
+	}
+	if flag("skipQuery") {
+		opts = append(opts, surl.SkipQuery())
+	}
+	if flag("skipScheme") {
+		opts = append(opts, surl.SkipScheme())
+	}
+	if flag("skipHost") {
+		opts = append(opts, surl.SkipHost())
+	}
+	if flag("selfDescribing") {
+		opts = append(opts, surl.SelfDescribing())
+	}
+	return opts, nil
+}
+
+// parseExpiry converts a Date or a number of seconds since the Unix epoch to
+// a time.
+func parseExpiry(v js.Value) (time.Time, error) {
+	switch {
+	case v.Type() == js.TypeNumber:
+		return time.Unix(int64(v.Float()), 0), nil
+	case v.InstanceOf(js.Global().Get("Date")):
+		return time.UnixMilli(int64(v.Call("getTime").Float())), nil
+	}
+	return time.Time{}, errors.New("expiry must be a Date or a number of seconds since the Unix epoch")
+}
+
+func errorResult(err error) map[string]any {
+	return map[string]any{"error": err.Error()}
+}
@@ -0,0 +1,93 @@
+package surl
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Webhook(t *testing.T) {
+	signer := New([]byte("abc123"))
+	body := []byte(`{"event":"job.completed"}`)
+
+	sign := func(signer *Signer, timestamp time.Time) string {
+		header, err := signer.SignWebhook(body, timestamp)
+		require.NoError(t, err)
+		return header
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		want   error
+	}{
+		{
+			name:   "valid",
+			header: sign(signer, time.Now()),
+			body:   body,
+		},
+		{
+			name:   "valid amongst several signatures",
+			header: sign(signer, time.Now()) + ",v1=deadbeef",
+			body:   body,
+		},
+		{
+			name:   "tampered body",
+			header: sign(signer, time.Now()),
+			body:   []byte(`{"event":"job.failed"}`),
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "signed with different key",
+			header: sign(New([]byte("xyz789")), time.Now()),
+			body:   body,
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "too old",
+			header: sign(signer, time.Now().Add(-time.Hour)),
+			body:   body,
+			want:   ErrExpired,
+		},
+		{
+			name: "missing header",
+			body: body,
+			want: ErrInvalidFormat,
+		},
+		{
+			name:   "missing timestamp",
+			header: "v1=deadbeef",
+			body:   body,
+			want:   ErrInvalidFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/webhooks", nil)
+			if tt.header != "" {
+				r.Header.Set(WebhookSignatureHeader, tt.header)
+			}
+			err := signer.VerifyWebhook(r, tt.body)
+			if tt.want == nil {
+				require.NoError(t, err)
+			} else {
+				assert.Truef(t, errors.Is(err, tt.want), "got error: %v", err)
+			}
+		})
+	}
+
+	t.Run("custom tolerance", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithWebhookTolerance(2*time.Hour))
+
+		r := httptest.NewRequest("POST", "/webhooks", nil)
+		r.Header.Set(WebhookSignatureHeader, sign(signer, time.Now().Add(-time.Hour)))
+
+		err := signer.VerifyWebhook(r, body)
+		require.NoError(t, err)
+	})
+}
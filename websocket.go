@@ -0,0 +1,56 @@
+package surl
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WebSocket returns middleware that verifies the signed ws:// or wss:// URL of
+// a WebSocket handshake request before passing it to the next handler to
+// perform the upgrade. Requests that are not WebSocket handshakes receive a 400
+// Bad Request response, and requests that fail verification receive a 403
+// Forbidden response, or a 410 Gone response if the URL has expired. The
+// result of verification is available to the next handler via
+// ResultFromContext.
+//
+// If the signed URL is served from a different host or scheme than the one
+// it was signed for, e.g. behind a proxy, use the SkipHost and SkipScheme
+// options.
+func (s *Signer) WebSocket(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isWebSocketHandshake(r) {
+			http.Error(w, "expected WebSocket handshake", http.StatusBadRequest)
+			return
+		}
+
+		u := requestURL(r)
+		switch u.Scheme {
+		case "http":
+			u.Scheme = "ws"
+		case "https":
+			u.Scheme = "wss"
+		}
+		result, err := s.verifyRequestURL(r, u, "")
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(newContext(r.Context(), result)))
+	})
+}
+
+// isWebSocketHandshake determines whether the request is a WebSocket opening
+// handshake.
+func isWebSocketHandshake(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, v := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
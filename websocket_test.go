@@ -0,0 +1,93 @@
+package surl
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_WebSocket(t *testing.T) {
+	handshake := func(target string) *http.Request {
+		r := httptest.NewRequest("GET", target, nil)
+		// server requests carry only the path and query
+		r.URL.Scheme = ""
+		r.URL.Host = ""
+		r.TLS = &tls.ConnectionState{}
+		r.Header.Set("Connection", "keep-alive, Upgrade")
+		r.Header.Set("Upgrade", "websocket")
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		signed  string
+		modify  func(r *http.Request)
+		want    int
+	}{
+		{
+			name:   "valid",
+			signed: "wss://example.com/ws/chat",
+			want:   http.StatusSwitchingProtocols,
+		},
+		{
+			name:   "signed with different scheme",
+			signed: "https://example.com/ws/chat",
+			want:   http.StatusForbidden,
+		},
+		{
+			name:    "skip scheme",
+			options: []Option{SkipScheme()},
+			signed:  "https://example.com/ws/chat",
+			want:    http.StatusSwitchingProtocols,
+		},
+		{
+			name:   "signed with different host",
+			signed: "wss://public.example.com/ws/chat",
+			want:   http.StatusForbidden,
+		},
+		{
+			name:    "skip host",
+			options: []Option{SkipHost()},
+			signed:  "wss://public.example.com/ws/chat",
+			want:    http.StatusSwitchingProtocols,
+		},
+		{
+			name:   "not a handshake",
+			signed: "wss://example.com/ws/chat",
+			modify: func(r *http.Request) { r.Header.Del("Upgrade") },
+			want:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := New([]byte("abc123"), tt.options...)
+
+			signed, err := signer.Sign(tt.signed, time.Now().Add(time.Minute))
+			require.NoError(t, err)
+
+			handler := signer.WebSocket(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				result, ok := ResultFromContext(r.Context())
+				require.True(t, ok)
+				assert.Equal(t, "/ws/chat", result.OriginalURL.Path)
+				w.WriteHeader(http.StatusSwitchingProtocols)
+			}))
+
+			// the server is reached at example.com over TLS, regardless of
+			// where the URL was signed for.
+			r := handshake(strings.Replace(signed, tt.signed[:strings.Index(tt.signed, "/ws")], "https://example.com", 1))
+			if tt.modify != nil {
+				tt.modify(r)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package surl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWindow(t *testing.T) {
+	signer := New([]byte("abc123"))
+	notBefore := time.Now().Add(time.Hour)
+	expiry := notBefore.Add(time.Hour)
+
+	signed, err := signer.SignWindow("https://example.com/a/b/c", notBefore, expiry)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, signer.Verify(signed), ErrNotYetValid)
+	assert.NoError(t, signer.VerifyAt(signed, notBefore.Add(time.Minute)))
+	assert.ErrorIs(t, signer.VerifyAt(signed, expiry.Add(time.Minute)), ErrExpired)
+
+	t.Run("brought forward", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Set(notBeforeParam, signer.Encode(time.Now().Add(-time.Hour).Unix()))
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		q.Del(notBeforeParam)
+		u.RawQuery = q.Encode()
+
+		assert.ErrorIs(t, signer.Verify(u.String()), ErrInvalidSignature)
+	})
+
+	t.Run("folded into ID", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithRevocationChecker(func(context.Context, string) (bool, error) {
+			return false, nil
+		}))
+		signed, err := signer.SignWindow("https://example.com/a/b/c", notBefore, expiry)
+		require.NoError(t, err)
+		id, err := URLID(signed)
+		require.NoError(t, err)
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		q := u.Query()
+		encoded := q.Get(notBeforeParam)
+		for _, forged := range []string{id + ":nbf:" + encoded, id + notBeforeBinding(encoded, "")} {
+			q.Set(idParam, forged)
+			q.Del(notBeforeParam)
+			u.RawQuery = q.Encode()
+
+			assert.Error(t, signer.Verify(u.String()), forged)
+		}
+	})
+
+	t.Run("base58", func(t *testing.T) {
+		signer := New([]byte("abc123"), WithBase58Expiry())
+		signed, err := signer.SignWindow("https://example.com/a/b/c", time.Now().Add(-time.Minute), expiry)
+		require.NoError(t, err)
+
+		assert.NoError(t, signer.Verify(signed))
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := signer.SignWindow("https://example.com/a/b/c", expiry, notBefore)
+		assert.Error(t, err)
+	})
+
+	t.Run("middleware", func(t *testing.T) {
+		handler := signer.Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", signed, nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}